@@ -13,7 +13,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/autobrr/go-mediainfo/internal/cli"
-	"github.com/autobrr/go-mediainfo/internal/mediainfo"
+	"github.com/autobrr/go-mediainfo/mediainfo"
 )
 
 var version = "dev"
@@ -69,6 +69,22 @@ var versionCmd = &cobra.Command{
 	DisableFlagsInUseLine: true,
 }
 
+var checkNameCmd = &cobra.Command{
+	Use:   "check-name [--name=<release name>] [--output=JSON] <file>",
+	Short: "Check a release name against the media it names",
+	Long: "Check the resolution, codecs, HDR formats, audio flavors and languages a scene or P2P\n" +
+		"release name claims against what the file contains. The name is taken from the file\n" +
+		"name unless --name is given. Exits with status 1 when a claim fails.",
+	Args:                  cobra.ExactArgs(1),
+	SilenceUsage:          true,
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		output, _ := cmd.Flags().GetString("output")
+		os.Exit(cli.CheckName(args[0], name, output, cmd.OutOrStdout(), cmd.ErrOrStderr()))
+	},
+}
+
 func init() {
 	resolvedVersion := resolveVersion()
 	cli.SetVersion(resolvedVersion)
@@ -78,6 +94,9 @@ func init() {
 	rootCmd.SetHelpTemplate(helpTemplate)
 	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(versionCmd)
+	checkNameCmd.Flags().String("name", "", "release name to check instead of the file name")
+	checkNameCmd.Flags().String("output", "TEXT", "output format: TEXT or JSON")
+	rootCmd.AddCommand(checkNameCmd)
 }
 
 func main() {
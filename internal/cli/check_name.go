@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/autobrr/go-mediainfo/mediainfo"
+)
+
+type checkNameResult struct {
+	File   string                `json:"file"`
+	Name   string                `json:"name"`
+	Pass   bool                  `json:"pass"`
+	Checks []mediainfo.NameCheck `json:"checks"`
+}
+
+// CheckName runs "check-name": it compares the release name of file, or name when set,
+// with what the file contains. It exits with an error when a claim fails.
+func CheckName(file, name, output string, stdout, stderr io.Writer) int {
+	output = strings.ToUpper(output)
+	if output != "" && output != "TEXT" && output != "JSON" {
+		fmt.Fprintf(stderr, "unsupported output format: %s\n", output)
+		return exitError
+	}
+
+	report, err := mediainfo.AnalyzeFile(file)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	if name == "" {
+		name = file
+	}
+	result := checkNameResult{File: file, Name: name, Pass: true, Checks: mediainfo.CheckReleaseName(name, report)}
+	counts := map[mediainfo.CheckStatus]int{}
+	for _, check := range result.Checks {
+		counts[check.Status]++
+		if check.Status == mediainfo.CheckFail {
+			result.Pass = false
+		}
+	}
+	if result.Checks == nil {
+		result.Checks = []mediainfo.NameCheck{}
+	}
+
+	if output == "JSON" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		fmt.Fprintln(stdout, string(data))
+	} else {
+		fmt.Fprintln(stdout, name)
+		for _, check := range result.Checks {
+			detail := "actual: " + check.Actual
+			if check.Actual == "" {
+				detail = "actual: none"
+			}
+			if check.Reason != "" {
+				detail += "; " + check.Reason
+			}
+			fmt.Fprintf(stdout, "%-10s %s: %s (%s)\n", strings.ToUpper(string(check.Status)), check.Attribute, check.Claimed, detail)
+		}
+		if len(result.Checks) == 0 {
+			fmt.Fprintln(stdout, "no checkable attributes in name")
+		}
+		fmt.Fprintf(stdout, "%d passed, %d failed, %d unverified\n", counts[mediainfo.CheckPass], counts[mediainfo.CheckFail], counts[mediainfo.CheckUnverified])
+	}
+	if !result.Pass {
+		return exitError
+	}
+	return exitOK
+}
@@ -1,15 +1,18 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
 
-	"github.com/autobrr/go-mediainfo/internal/mediainfo"
+	"github.com/autobrr/go-mediainfo/mediainfo"
 )
 
 const (
@@ -23,6 +26,14 @@ type Options struct {
 	Language    string
 	LogFile     string
 	Bom         bool
+	Concurrency int
+	Strict      bool
+	Redact      bool
+	Recursive   bool
+	MaxDepth    int
+	Include     []string
+	Exclude     []string
+	Symlinks    mediainfo.SymlinkPolicy
 	CoreOptions []CoreOption
 }
 
@@ -63,7 +74,7 @@ func Run(args []string, stdout, stderr io.Writer) int {
 			if value, ok := valueAfterEqual(original); ok {
 				opts.Language = value
 			}
-		case strings.HasPrefix(normalized, "--output="):
+		case strings.HasPrefix(normalized, "--output=") || strings.HasPrefix(normalized, "--inform="):
 			if value, ok := valueAfterEqual(original); ok {
 				opts.Output = value
 			} else {
@@ -76,6 +87,42 @@ func Run(args []string, stdout, stderr io.Writer) int {
 			opts.LogFile = valueAfterLogfile(original)
 		case normalized == "--bom":
 			opts.Bom = true
+		case normalized == "--strict":
+			opts.Strict = true
+		case normalized == "--redact":
+			opts.Redact = true
+		case normalized == "--recursive" || normalized == "-r":
+			opts.Recursive = true
+		case strings.HasPrefix(normalized, "--max-depth="):
+			value, _ := valueAfterEqual(original)
+			depth, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || depth < 0 {
+				fmt.Fprintf(stderr, "invalid max depth: %s\n", value)
+				return exitError
+			}
+			opts.MaxDepth = depth
+		case strings.HasPrefix(normalized, "--include="):
+			value, _ := valueAfterEqual(original)
+			opts.Include = append(opts.Include, value)
+		case strings.HasPrefix(normalized, "--exclude="):
+			value, _ := valueAfterEqual(original)
+			opts.Exclude = append(opts.Exclude, value)
+		case strings.HasPrefix(normalized, "--symlinks="):
+			value, _ := valueAfterEqual(original)
+			policy, ok := parseSymlinkPolicy(value)
+			if !ok {
+				fmt.Fprintf(stderr, "invalid symlinks policy: %s\n", value)
+				return exitError
+			}
+			opts.Symlinks = policy
+		case strings.HasPrefix(normalized, "--concurrency="):
+			value, _ := valueAfterEqual(original)
+			workers, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || workers < 0 {
+				fmt.Fprintf(stderr, "invalid concurrency: %s\n", value)
+				return exitError
+			}
+			opts.Concurrency = workers
 		case strings.HasPrefix(normalized, "--"):
 			if normalized == "--" {
 				continue
@@ -98,8 +145,31 @@ func Run(args []string, stdout, stderr io.Writer) int {
 		writeBOM(stdout, stderr)
 	}
 
-	output, filesCount, err := runCore(opts, files)
-	if err != nil {
+	// Language codes without an embedded table fall back to English, as --language was
+	// accepted and ignored before; an unreadable language file is an error.
+	lang, err := loadLanguage(opts.Language)
+	if errors.Is(err, errLanguageNotAvailable) {
+		fmt.Fprintf(stderr, "%v, using English\n", err)
+		lang = nil
+	} else if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	// A *BatchError still comes with output: failed files are rendered as error entries
+	// and reported on stderr as well.
+	var (
+		output     string
+		filesCount int
+	)
+	streaming := isNDJSON(opts.Output)
+	if streaming {
+		filesCount, err = runNDJSON(opts, files, stdout)
+	} else {
+		output, filesCount, err = runCore(opts, lang, files)
+	}
+	var batchErr *mediainfo.BatchError
+	if err != nil && !errors.As(err, &batchErr) {
 		fmt.Fprintln(stderr, err)
 		return exitError
 	}
@@ -107,14 +177,22 @@ func Run(args []string, stdout, stderr io.Writer) int {
 	if output != "" {
 		fmt.Fprintln(stdout, output)
 	}
+	if batchErr != nil {
+		for _, failure := range batchErr.Failures {
+			fmt.Fprintln(stderr, failure)
+		}
+	}
 
-	if opts.LogFile != "" {
+	if opts.LogFile != "" && !streaming {
 		if err := writeLogFile(opts.LogFile, output, opts.Bom); err != nil {
 			fmt.Fprintln(stderr, err)
 			return exitError
 		}
 	}
 
+	if batchErr != nil && opts.Strict {
+		return exitError
+	}
 	if filesCount > 0 {
 		return exitOK
 	}
@@ -177,6 +255,19 @@ func parseCoreOption(normalized, original string) (string, string) {
 	return name, original[eq+1:]
 }
 
+func parseSymlinkPolicy(value string) (mediainfo.SymlinkPolicy, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "files":
+		return mediainfo.SymlinkFiles, true
+	case "follow":
+		return mediainfo.SymlinkFollow, true
+	case "skip":
+		return mediainfo.SymlinkSkip, true
+	default:
+		return 0, false
+	}
+}
+
 func writeBOM(stdout, stderr io.Writer) {
 	if runtime.GOOS != "windows" {
 		return
@@ -196,21 +287,46 @@ func writeLogFile(path, output string, includeBOM bool) error {
 	return os.WriteFile(path, data, 0o644) //nolint:gosec // user-facing output file
 }
 
-func runCore(opts Options, files []string) (string, int, error) {
-	if opts.Output != "" {
-		if strings.Contains(opts.Output, ";") || strings.HasPrefix(strings.ToLower(opts.Output), "file://") {
-			return "", 0, fmt.Errorf("output template not implemented: %s", opts.Output)
+func runCore(opts Options, lang *mediainfo.Language, files []string) (string, int, error) {
+	var tmpl *mediainfo.Template
+	if isOutputTemplate(opts.Output) {
+		var err error
+		if tmpl, err = loadTemplate(opts.Output); err != nil {
+			return "", 0, err
 		}
-
+	} else if opts.Output != "" {
 		outputName := strings.ToUpper(strings.TrimSpace(opts.Output))
 		switch outputName {
-		case "TEXT", "JSON", "XML", "OLDXML", "HTML", "CSV", "EBUCORE", "EBUCORE_JSON", "PBCORE", "PBCORE2":
+		case "TEXT", "JSON", "XML", "OLDXML", "HTML", "CSV", "EBUCORE", "EBUCORE_JSON", "PBCORE", "PBCORE2", "GRAPH_SVG", "GRAPH_DOT", "NDJSON", "FFPROBE_JSON", "BBCODE", "BBCODE_CODE", "MARKDOWN", "CONSISTENCY":
 		default:
 			return "", 0, fmt.Errorf("output format not implemented: %s", opts.Output)
 		}
 	}
+	analyzeOpts := buildAnalyzeOptions(opts)
+	reports, count, err := mediainfo.AnalyzeFilesWithOptions(files, analyzeOpts)
+	var batchErr *mediainfo.BatchError
+	if err != nil && !errors.As(err, &batchErr) {
+		return "", 0, err
+	}
+	if tmpl != nil {
+		return mediainfo.RenderTemplate(reports, tmpl), count, err
+	}
+	renderOpts := mediainfo.RenderOptions{Full: opts.Full, Language: lang}
+	if markup, ok := trackerMarkup(opts.Output); ok {
+		return mediainfo.RenderTracker(reports, mediainfo.TrackerOptions{RenderOptions: renderOpts, Markup: markup, Redact: opts.Redact}), count, err
+	}
+	return renderOutput(opts.Output, reports, renderOpts), count, err
+}
 
-	analyzeOpts := mediainfo.AnalyzeOptions{}
+func buildAnalyzeOptions(opts Options) mediainfo.AnalyzeOptions {
+	analyzeOpts := mediainfo.AnalyzeOptions{
+		Concurrency: opts.Concurrency,
+		Recursive:   opts.Recursive,
+		MaxDepth:    opts.MaxDepth,
+		Include:     opts.Include,
+		Exclude:     opts.Exclude,
+		Symlinks:    opts.Symlinks,
+	}
 	for _, opt := range opts.CoreOptions {
 		if strings.EqualFold(opt.Name, "parsespeed") {
 			if value, err := strconv.ParseFloat(strings.TrimSpace(opt.Value), 64); err == nil {
@@ -226,34 +342,159 @@ func runCore(opts Options, files []string) (string, int, error) {
 			continue
 		}
 	}
-	reports, count, err := mediainfo.AnalyzeFilesWithOptions(files, analyzeOpts)
-	if err != nil {
-		return "", 0, err
+	return analyzeOpts
+}
+
+func isNDJSON(output string) bool {
+	return strings.EqualFold(strings.TrimSpace(output), "NDJSON")
+}
+
+// runNDJSON streams --output=NDJSON: each file's line is written to stdout, and to the
+// log file, as soon as it and the files before it are analyzed.
+func runNDJSON(opts Options, files []string, stdout io.Writer) (count int, err error) {
+	w := stdout
+	if opts.LogFile != "" {
+		file, createErr := os.Create(opts.LogFile) //nolint:gosec // user-facing output file
+		if createErr != nil {
+			return 0, createErr
+		}
+		defer func() {
+			if closeErr := file.Close(); err == nil && closeErr != nil {
+				err = closeErr
+			}
+		}()
+		if opts.Bom && runtime.GOOS == "windows" {
+			if _, err = file.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+				return 0, err
+			}
+		}
+		w = io.MultiWriter(stdout, file)
+	}
+	enc := mediainfo.NewEncoder(w)
+	return mediainfo.AnalyzeFilesFunc(context.Background(), files, buildAnalyzeOptions(opts), enc.Write)
+}
+
+func trackerMarkup(output string) (mediainfo.TrackerMarkup, bool) {
+	switch strings.ToUpper(strings.TrimSpace(output)) {
+	case "BBCODE":
+		return mediainfo.TrackerBBCode, true
+	case "BBCODE_CODE":
+		return mediainfo.TrackerBBCodeCode, true
+	case "MARKDOWN":
+		return mediainfo.TrackerMarkdown, true
+	default:
+		return 0, false
+	}
+}
+
+func isOutputTemplate(output string) bool {
+	return strings.Contains(output, ";") || hasFilePrefix(output)
+}
+
+// loadTemplate parses an --output template given inline, as file://path or as
+// Section;file://path (the file holds the text of that section).
+func loadTemplate(output string) (*mediainfo.Template, error) {
+	if hasFilePrefix(output) {
+		data, err := os.ReadFile(output[len("file://"):])
+		if err != nil {
+			return nil, err
+		}
+		return mediainfo.ParseTemplate(string(data))
+	}
+	if section, body, ok := strings.Cut(output, ";"); ok && hasFilePrefix(body) {
+		data, err := os.ReadFile(body[len("file://"):])
+		if err != nil {
+			return nil, err
+		}
+		text := strings.TrimPrefix(string(data), "\ufeff")
+		return mediainfo.ParseTemplate(section + ";" + strings.TrimRight(text, "\r\n"))
+	}
+	return mediainfo.ParseTemplate(output)
+}
+
+var errLanguageNotAvailable = errors.New("language not available")
+
+// loadLanguage returns the translation for --language: nil for English, the raw names for
+// "raw", an embedded table for its code, or a MediaInfo language file given as
+// file://path. The file name picks the column of a master table ("file://fr.csv").
+func loadLanguage(value string) (*mediainfo.Language, error) {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "" || strings.EqualFold(value, "en"):
+		return nil, nil
+	case strings.EqualFold(value, "raw"):
+		return mediainfo.RawLanguage(), nil
+	case hasFilePrefix(value):
+		path := value[len("file://"):]
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return mediainfo.LoadLanguageCSV(file, languageFileCode(path))
+	}
+	if lang, ok := mediainfo.BuiltinLanguage(value); ok {
+		return lang, nil
 	}
+	return nil, fmt.Errorf("%w: %s (use %s, raw or file://language.csv)", errLanguageNotAvailable, value, strings.Join(mediainfo.BuiltinLanguageCodes(), ", "))
+}
+
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}(?:-[a-z]{2,4})?$`)
 
-	if strings.EqualFold(opts.Output, "JSON") {
-		return mediainfo.RenderJSON(reports), count, nil
+// languageFileCode returns the language code named by a language file ("fr.csv",
+// "pt_BR.csv"), or "" when the name is not a code.
+func languageFileCode(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	if !languageCodePattern.MatchString(name) {
+		return ""
+	}
+	return name
+}
+
+func hasFilePrefix(value string) bool {
+	return strings.HasPrefix(strings.ToLower(value), "file://")
+}
+
+func renderOutput(outputName string, reports []mediainfo.Report, renderOpts mediainfo.RenderOptions) string {
+	if strings.EqualFold(outputName, "JSON") {
+		return mediainfo.RenderJSON(reports)
+	}
+	if strings.EqualFold(outputName, "FFPROBE_JSON") {
+		return mediainfo.RenderFFprobeJSON(reports)
+	}
+	if strings.EqualFold(outputName, "NDJSON") {
+		return mediainfo.RenderNDJSON(reports)
+	}
+	if strings.EqualFold(outputName, "XML") || strings.EqualFold(outputName, "OLDXML") {
+		return mediainfo.RenderXML(reports)
+	}
+	if strings.EqualFold(outputName, "CSV") {
+		return mediainfo.RenderCSV(reports)
+	}
+	if strings.EqualFold(outputName, "EBUCORE") {
+		return mediainfo.RenderEBUCore(reports)
 	}
-	if strings.EqualFold(opts.Output, "XML") || strings.EqualFold(opts.Output, "OLDXML") {
-		return mediainfo.RenderXML(reports), count, nil
+	if strings.EqualFold(outputName, "EBUCORE_JSON") {
+		return mediainfo.RenderEBUCoreJSON(reports)
 	}
-	if strings.EqualFold(opts.Output, "CSV") {
-		return mediainfo.RenderCSV(reports), count, nil
+	if strings.EqualFold(outputName, "PBCORE") {
+		return mediainfo.RenderPBCore(reports)
 	}
-	if strings.EqualFold(opts.Output, "EBUCORE") || strings.EqualFold(opts.Output, "EBUCORE_JSON") {
-		return mediainfo.RenderEBUCore(reports), count, nil
+	if strings.EqualFold(outputName, "PBCORE2") {
+		return mediainfo.RenderPBCore2(reports)
 	}
-	if strings.EqualFold(opts.Output, "PBCORE") || strings.EqualFold(opts.Output, "PBCORE2") {
-		return mediainfo.RenderPBCore(reports), count, nil
+	if strings.EqualFold(outputName, "GRAPH_SVG") {
+		return mediainfo.RenderGraphSVG(reports)
 	}
-	if strings.EqualFold(opts.Output, "GRAPH_SVG") {
-		return mediainfo.RenderGraphSVG(reports), count, nil
+	if strings.EqualFold(outputName, "GRAPH_DOT") {
+		return mediainfo.RenderGraphDOT(reports)
 	}
-	if strings.EqualFold(opts.Output, "GRAPH_DOT") {
-		return mediainfo.RenderGraphDOT(reports), count, nil
+	if strings.EqualFold(outputName, "CONSISTENCY") {
+		return mediainfo.RenderConsistency(mediainfo.CheckConsistency(reports))
 	}
-	if strings.EqualFold(opts.Output, "HTML") {
-		return mediainfo.RenderHTML(reports), count, nil
+	if strings.EqualFold(outputName, "HTML") {
+		return mediainfo.RenderHTMLWithOptions(reports, renderOpts)
 	}
-	return mediainfo.RenderText(reports), count, nil
+	return mediainfo.RenderTextWithOptions(reports, renderOpts)
 }
@@ -17,12 +17,30 @@ func Help(program string, stdout io.Writer) {
 	fmt.Fprintln(stdout, "")
 	fmt.Fprintln(stdout, "--full, -f")
 	fmt.Fprintln(stdout, "                    Full information display (all internal tags)")
-	fmt.Fprintln(stdout, "--output=TEXT|JSON|XML|OLDXML|HTML|CSV|EBUCore|EBUCore_JSON|PBCore|PBCore2|Graph_Svg|Graph_Dot")
+	fmt.Fprintln(stdout, "--output=TEXT|JSON|XML|OLDXML|HTML|CSV|EBUCore|EBUCore_JSON|PBCore|PBCore2|Graph_Svg|Graph_Dot|NDJSON|FFprobe_JSON|BBCode|BBCode_Code|Markdown|Consistency")
 	fmt.Fprintln(stdout, "                    Select output format")
+	fmt.Fprintln(stdout, "--language=de|es|fr|it")
+	fmt.Fprintln(stdout, "                    Translate the text and HTML output")
 	fmt.Fprintln(stdout, "--language=raw")
 	fmt.Fprintln(stdout, "                    Display non-translated unique identifiers (internal text)")
+	fmt.Fprintln(stdout, "--language=file://...")
+	fmt.Fprintln(stdout, "                    Translate the text and HTML output with a MediaInfo language file (CSV)")
 	fmt.Fprintln(stdout, "--logfile=...")
 	fmt.Fprintln(stdout, "                    Save the output in the specified file")
+	fmt.Fprintln(stdout, "--concurrency=...")
+	fmt.Fprintln(stdout, "                    Number of files analyzed in parallel (default: number of CPUs)")
+	fmt.Fprintln(stdout, "--recursive, -r")
+	fmt.Fprintln(stdout, "                    Also analyze files in subdirectories of directory arguments")
+	fmt.Fprintln(stdout, "--max-depth=...")
+	fmt.Fprintln(stdout, "                    Limit recursion depth (1 = only the directory itself)")
+	fmt.Fprintln(stdout, "--include=..., --exclude=...")
+	fmt.Fprintln(stdout, "                    Glob filter for files found in directories (repeatable, e.g. --exclude=*.nfo --exclude=Sample/)")
+	fmt.Fprintln(stdout, "--symlinks=files|follow|skip")
+	fmt.Fprintln(stdout, "                    Symbolic links in directories: analyze linked files (default), also follow linked directories, or skip")
+	fmt.Fprintln(stdout, "--strict")
+	fmt.Fprintln(stdout, "                    Exit with an error if any file could not be analyzed")
+	fmt.Fprintln(stdout, "--redact")
+	fmt.Fprintln(stdout, "                    With BBCode/Markdown output, drop unique IDs, file times and absolute paths")
 	fmt.Fprintln(stdout, "--bom")
 	fmt.Fprintln(stdout, "                    Byte order mark for UTF-8 output (Windows only)")
 	fmt.Fprintln(stdout, "--info-parameters")
@@ -42,14 +60,18 @@ func HelpOutput(program string, stdout io.Writer) {
 	fmt.Fprintln(stdout, "--output=...  Specify a template (BETA)")
 	fmt.Fprintf(stdout, "Usage: \"%s --output=[xxx;]Text FileName\"\n", program)
 	fmt.Fprintln(stdout, "")
-	fmt.Fprintln(stdout, "xxx can be: General, Video, Audio, Text, Chapter, Image, Menu")
+	fmt.Fprintln(stdout, "xxx can be: General, Video, Audio, Text, Image, Menu, a stream kind")
+	fmt.Fprintln(stdout, "     followed by _Begin, _Middle or _End, Page_Begin, Page_Middle,")
+	fmt.Fprintln(stdout, "     Page_End, File_Begin or File_End")
 	fmt.Fprintln(stdout, "Text can be the template text, or a filename")
 	fmt.Fprintln(stdout, "     Filename must be in the form file://filename")
 	fmt.Fprintln(stdout, "")
 	fmt.Fprintln(stdout, "See --info-parameters for available parameters in the text")
 	fmt.Fprintln(stdout, "(Parameters must be surrounded by \"%\" sign)")
+	fmt.Fprintln(stdout, "$if(%Parameter%,text if set,text if empty) selects text,")
+	fmt.Fprintln(stdout, "\\n inserts a new line")
 	fmt.Fprintln(stdout, "")
-	fmt.Fprintf(stdout, "Usage: \"%s --output=Video;%%AspectRatio%% FileName\"\n", program)
+	fmt.Fprintf(stdout, "Usage: \"%s --output=Video;%%Width%%x%%Height%%\\n FileName\"\n", program)
 	fmt.Fprintln(stdout, "")
 	fmt.Fprintf(stdout, "Usage: \"%s --output=Video;file://Video.txt FileName\"\n", program)
 	fmt.Fprintln(stdout, "and Video.txt contains ")
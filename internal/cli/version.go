@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"io"
 
-	"github.com/autobrr/go-mediainfo/internal/mediainfo"
+	"github.com/autobrr/go-mediainfo/mediainfo"
 )
 
 var appVersion = "dev"
@@ -0,0 +1,254 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// aiffCodec describes an AIFF-C compression type.
+type aiffCodec struct {
+	format     string
+	profile    string
+	endianness string
+	// bits is the stored bits per sample, when it differs from the COMM sample size.
+	bits int
+	// blockFrames is the number of sample frames per packet of block codecs; COMM counts
+	// packets for them.
+	blockFrames int
+	blockBytes  int
+}
+
+var aiffCodecs = map[string]aiffCodec{
+	"NONE": {format: "PCM", endianness: "Big"},
+	"twos": {format: "PCM", endianness: "Big"},
+	"sowt": {format: "PCM", endianness: "Little"},
+	"fl32": {format: "PCM", profile: "Float", endianness: "Big", bits: 32},
+	"FL32": {format: "PCM", profile: "Float", endianness: "Big", bits: 32},
+	"fl64": {format: "PCM", profile: "Float", endianness: "Big", bits: 64},
+	"FL64": {format: "PCM", profile: "Float", endianness: "Big", bits: 64},
+	"ulaw": {format: "ADPCM", profile: "U-Law", bits: 8},
+	"ULAW": {format: "ADPCM", profile: "U-Law", bits: 8},
+	"alaw": {format: "ADPCM", profile: "A-Law", bits: 8},
+	"ALAW": {format: "ADPCM", profile: "A-Law", bits: 8},
+	"ima4": {format: "ADPCM", profile: "IMA", blockFrames: 64, blockBytes: 34},
+}
+
+// ParseAIFF reads AIFF and AIFF-C files: COMM for the audio parameters, SSND for the
+// stream size and the NAME, AUTH, "(c) ", ANNO and ID3 chunks for the tags, returned as
+// General JSON fields.
+func ParseAIFF(file io.ReadSeeker, size int64) (ContainerInfo, []Stream, map[string]string, bool) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return ContainerInfo{}, nil, nil, false
+	}
+
+	var header [12]byte
+	if _, err := io.ReadFull(file, header[:]); err != nil {
+		return ContainerInfo{}, nil, nil, false
+	}
+	form := string(header[8:12])
+	if string(header[0:4]) != "FORM" || (form != "AIFF" && form != "AIFC") {
+		return ContainerInfo{}, nil, nil, false
+	}
+
+	var (
+		channels    uint16
+		frames      uint32
+		sampleSize  uint16
+		sampleRate  float64
+		compression string
+		commFound   bool
+		dataSize    int64
+	)
+	generalJSON := map[string]string{}
+	setTag := func(key, value string) {
+		value = strings.TrimSpace(strings.TrimRight(value, "\x00"))
+		if value != "" && generalJSON[key] == "" {
+			generalJSON[key] = value
+		}
+	}
+
+chunks:
+	for {
+		var chunkHeader [8]byte
+		if _, err := io.ReadFull(file, chunkHeader[:]); err != nil {
+			break
+		}
+		chunkID := string(chunkHeader[0:4])
+		chunkSize := int64(binary.BigEndian.Uint32(chunkHeader[4:8]))
+
+		switch chunkID {
+		case "COMM", "NAME", "AUTH", "(c) ", "ANNO", "ID3 ", "id3 ":
+			if chunkSize > 1<<24 {
+				if chunkID == "COMM" {
+					return ContainerInfo{}, nil, nil, false
+				}
+				// Tags this large are mostly embedded cover art; skip them.
+				if _, err := file.Seek(chunkSize, io.SeekCurrent); err != nil {
+					return ContainerInfo{}, nil, nil, false
+				}
+				break
+			}
+			payload := make([]byte, chunkSize)
+			if _, err := io.ReadFull(file, payload); err != nil {
+				if chunkID == "COMM" {
+					return ContainerInfo{}, nil, nil, false
+				}
+				break chunks
+			}
+			switch chunkID {
+			case "COMM":
+				if len(payload) < 18 {
+					return ContainerInfo{}, nil, nil, false
+				}
+				channels = binary.BigEndian.Uint16(payload[0:2])
+				frames = binary.BigEndian.Uint32(payload[2:6])
+				sampleSize = binary.BigEndian.Uint16(payload[6:8])
+				sampleRate = parseExtended80(payload[8:18])
+				if form == "AIFC" && len(payload) >= 22 {
+					compression = string(payload[18:22])
+				}
+				commFound = true
+			case "NAME":
+				setTag("Title", string(payload))
+			case "AUTH":
+				setTag("Performer", string(payload))
+			case "(c) ":
+				setTag("Copyright", string(payload))
+			case "ANNO":
+				setTag("Comment", string(payload))
+			default:
+				if id3, ok := parseID3v2(bytes.NewReader(payload)); ok && id3.Text != nil {
+					applyID3TextToGeneralJSON(generalJSON, nil, id3.Text)
+				}
+			}
+		case "SSND":
+			// offset and blockSize precede the sample data.
+			dataSize = max(chunkSize-8, 0)
+			if _, err := file.Seek(chunkSize, io.SeekCurrent); err != nil {
+				return ContainerInfo{}, nil, nil, false
+			}
+		default:
+			if _, err := file.Seek(chunkSize, io.SeekCurrent); err != nil {
+				return ContainerInfo{}, nil, nil, false
+			}
+		}
+
+		if chunkSize%2 == 1 {
+			if _, err := file.Seek(1, io.SeekCurrent); err != nil {
+				return ContainerInfo{}, nil, nil, false
+			}
+		}
+	}
+
+	if !commFound {
+		return ContainerInfo{}, nil, nil, false
+	}
+	if form == "AIFF" {
+		compression = "NONE"
+	}
+	codec, known := aiffCodecs[compression]
+	if !known {
+		codec = aiffCodec{format: strings.TrimSpace(compression)}
+	}
+
+	samples := int64(frames)
+	if codec.blockFrames > 0 {
+		samples *= int64(codec.blockFrames)
+	}
+	duration := 0.0
+	bitrate := 0.0
+	if sampleRate > 0 {
+		duration = float64(samples) / sampleRate
+		switch {
+		case codec.blockFrames > 0:
+			bitrate = sampleRate * float64(channels) * float64(codec.blockBytes*8) / float64(codec.blockFrames)
+		case codec.bits > 0:
+			bitrate = sampleRate * float64(channels) * float64(codec.bits)
+		case known:
+			bitrate = sampleRate * float64(channels) * float64(sampleSize)
+		}
+	}
+
+	info := ContainerInfo{DurationSeconds: duration}
+	if bitrate > 0 {
+		info.BitrateMode = "Constant"
+	}
+	if size > 0 && dataSize > 0 && dataSize <= size {
+		info.StreamOverheadBytes = size - dataSize
+	}
+
+	streamFields := []Field{{Name: "Format", Value: codec.format}}
+	if codec.profile != "" {
+		streamFields = append(streamFields, Field{Name: "Format profile", Value: codec.profile})
+	}
+	if form == "AIFC" && compression != "" {
+		streamFields = append(streamFields, Field{Name: "Codec ID", Value: strings.TrimSpace(compression)})
+	}
+	streamFields = addStreamDuration(streamFields, duration)
+	if bitrate > 0 {
+		streamFields = append(streamFields, Field{Name: "Bit rate mode", Value: "Constant"})
+		streamFields = append(streamFields, Field{Name: "Bit rate", Value: formatBitrateFraction(bitrate)})
+	}
+	if channels > 0 {
+		streamFields = append(streamFields, Field{Name: "Channel(s)", Value: formatChannels(uint64(channels))})
+	}
+	if sampleRate > 0 {
+		streamFields = append(streamFields, Field{Name: "Sampling rate", Value: formatSampleRate(sampleRate)})
+	}
+	bitDepth := int(sampleSize)
+	if codec.profile == "Float" {
+		bitDepth = codec.bits
+	}
+	if bitDepth > 0 && bitDepth <= 255 {
+		streamFields = append(streamFields, Field{Name: "Bit depth", Value: formatBitDepth(uint8(bitDepth))})
+	}
+	if codec.format == "PCM" {
+		// 8-bit samples have no byte order; AIFF samples are always signed.
+		if bitDepth > 8 {
+			streamFields = append(streamFields, Field{Name: "Format settings, Endianness", Value: codec.endianness})
+		}
+		if codec.profile != "Float" {
+			streamFields = append(streamFields, Field{Name: "Format settings, Sign", Value: "Signed"})
+		}
+	}
+
+	streamJSON := map[string]string{}
+	if dataSize > 0 {
+		streamJSON["StreamSize"] = strconv.FormatInt(dataSize, 10)
+	}
+	if samples > 0 {
+		streamJSON["SamplingCount"] = strconv.FormatInt(samples, 10)
+	}
+	if info.StreamOverheadBytes > 0 {
+		generalJSON["StreamSize"] = strconv.FormatInt(info.StreamOverheadBytes, 10)
+	}
+
+	streams := []Stream{{
+		Kind:                StreamAudio,
+		Fields:              streamFields,
+		JSON:                streamJSON,
+		JSONSkipStreamOrder: true,
+		JSONSkipComputed:    true,
+	}}
+	return info, streams, generalJSON, true
+}
+
+// parseExtended80 decodes the IEEE 754 80-bit extended float AIFF stores the sample
+// rate in.
+func parseExtended80(b []byte) float64 {
+	exponent := int(binary.BigEndian.Uint16(b[0:2]))
+	mantissa := binary.BigEndian.Uint64(b[2:10])
+	sign := 1.0
+	if exponent&0x8000 != 0 {
+		sign = -1
+		exponent &= 0x7FFF
+	}
+	if exponent == 0 && mantissa == 0 || exponent == 0x7FFF {
+		return 0
+	}
+	return sign * math.Ldexp(float64(mantissa), exponent-16383-63)
+}
@@ -0,0 +1,141 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// buildAIFF assembles a FORM file from the COMM fields, an optional AIFF-C compression
+// type, extra chunks and dataSize bytes of silence.
+func buildAIFF(channels uint16, frames uint32, sampleSize uint16, rate []byte, compression string, dataSize int, extra ...[]byte) []byte {
+	chunk := func(id string, payload []byte) []byte {
+		out := append([]byte(id), 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(out[4:8], uint32(len(payload)))
+		out = append(out, payload...)
+		if len(payload)%2 == 1 {
+			out = append(out, 0)
+		}
+		return out
+	}
+	comm := binary.BigEndian.AppendUint16(nil, channels)
+	comm = binary.BigEndian.AppendUint32(comm, frames)
+	comm = binary.BigEndian.AppendUint16(comm, sampleSize)
+	comm = append(comm, rate...)
+	form := "AIFF"
+	if compression != "" {
+		form = "AIFC"
+		comm = append(comm, compression...)
+		comm = append(comm, 0, 0) // empty Pascal string, padded
+	}
+	body := []byte(form)
+	body = append(body, chunk("COMM", comm)...)
+	for _, c := range extra {
+		body = append(body, c...)
+	}
+	body = append(body, chunk("SSND", make([]byte, 8+dataSize))...)
+	out := append([]byte("FORM"), 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(out[4:8], uint32(len(body)))
+	return append(out, body...)
+}
+
+var (
+	aiffRate44100 = []byte{0x40, 0x0E, 0xAC, 0x44, 0, 0, 0, 0, 0, 0}
+	aiffRate48000 = []byte{0x40, 0x0E, 0xBB, 0x80, 0, 0, 0, 0, 0, 0}
+)
+
+func TestParseExtended80(t *testing.T) {
+	for _, tc := range []struct {
+		raw  []byte
+		want float64
+	}{
+		{aiffRate44100, 44100},
+		{aiffRate48000, 48000},
+		{[]byte{0x40, 0x0F, 0xBB, 0x80, 0, 0, 0, 0, 0, 0}, 96000},
+		{make([]byte, 10), 0},
+	} {
+		if got := parseExtended80(tc.raw); got != tc.want {
+			t.Errorf("% x: got %v, want %v", tc.raw, got, tc.want)
+		}
+	}
+}
+
+func TestAnalyzeAIFF(t *testing.T) {
+	name := []byte("NAME\x00\x00\x00\x05Title\x00")
+	data := buildAIFF(2, 44100, 16, aiffRate44100, "", 44100*4, name)
+	report, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), "tone.aiff", defaultAnalyzeOptions())
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	text := RenderText([]Report{report})
+	for _, want := range []string{
+		"Format                                   : AIFF\n",
+		"Duration                                 : 1 s 0 ms\n",
+		"Overall bit rate                         : 1 412 kb/s\n",
+		"Format                                   : PCM\n",
+		"Bit rate                                 : 1 411.2 kb/s\n",
+		"Channel(s)                               : 2 channels\n",
+		"Sampling rate                            : 44.1 kHz\n",
+		"Bit depth                                : 16 bits\n",
+		"Format settings, Endianness              : Big\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %q in:\n%s", want, text)
+		}
+	}
+	jsonOut := RenderJSON([]Report{report})
+	for _, want := range []string{`"Title":"Title"`, `"StreamSize":"176400"`, `"SamplingCount":"44100"`} {
+		if !strings.Contains(jsonOut, want) {
+			t.Errorf("missing %s in %s", want, jsonOut)
+		}
+	}
+}
+
+func TestAnalyzeAIFFSkipsLargeTagChunk(t *testing.T) {
+	id3 := make([]byte, 8+1<<24+2)
+	copy(id3, "ID3 ")
+	binary.BigEndian.PutUint32(id3[4:8], 1<<24+2)
+	data := buildAIFF(2, 44100, 16, aiffRate44100, "", 44100*4, id3)
+	report, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), "cover.aiff", defaultAnalyzeOptions())
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	text := RenderText([]Report{report})
+	for _, want := range []string{
+		"Duration                                 : 1 s 0 ms\n",
+		"Sampling rate                            : 44.1 kHz\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestAnalyzeAIFC(t *testing.T) {
+	for _, tc := range []struct {
+		compression string
+		frames      uint32
+		dataSize    int
+		want        []string
+	}{
+		{"sowt", 48000, 48000 * 4, []string{"Codec ID                                 : sowt\n", "Format settings, Endianness              : Little\n", "Bit rate                                 : 1 536 kb/s\n"}},
+		{"fl32", 48000, 48000 * 8, []string{"Format profile                           : Float\n", "Bit depth                                : 32 bits\n", "Bit rate                                 : 3 072 kb/s\n"}},
+		{"ima4", 750, 750 * 68, []string{"Format                                   : ADPCM\n", "Format profile                           : IMA\n", "Bit rate                                 : 408 kb/s\n"}},
+		{"ulaw", 48000, 48000 * 2, []string{"Format profile                           : U-Law\n", "Bit rate                                 : 768 kb/s\n"}},
+	} {
+		t.Run(tc.compression, func(t *testing.T) {
+			data := buildAIFF(2, tc.frames, 16, aiffRate48000, tc.compression, tc.dataSize)
+			report, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), "tone.aifc", defaultAnalyzeOptions())
+			if err != nil {
+				t.Fatalf("analyze: %v", err)
+			}
+			text := RenderText([]Report{report})
+			for _, want := range append(tc.want, "Format                                   : AIFF\n", "Duration                                 : 1 s 0 ms\n") {
+				if !strings.Contains(text, want) {
+					t.Errorf("missing %q in:\n%s", want, text)
+				}
+			}
+		})
+	}
+}
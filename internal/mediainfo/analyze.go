@@ -1,14 +1,15 @@
 package mediainfo
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"math"
 	"os"
 	"path/filepath"
-	"sort"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 func AnalyzeFile(path string) (Report, error) {
@@ -16,21 +17,62 @@ func AnalyzeFile(path string) (Report, error) {
 }
 
 func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
-	opts = normalizeAnalyzeOptions(opts)
+	return AnalyzeFileContext(context.Background(), path, opts)
+}
+
+// AnalyzeFileContext is AnalyzeFileWithOptions with cancellation. When ctx is done (or
+// opts.Timeout elapses) parsing stops early and a *CanceledError is returned.
+func AnalyzeFileContext(ctx context.Context, path string, opts AnalyzeOptions) (Report, error) {
+	ctx, cancel := withAnalyzeTimeout(ctx, opts)
+	defer cancel()
+	if err := ctx.Err(); err != nil {
+		return Report{}, &CanceledError{Path: path, Err: err}
+	}
 	stat, err := os.Stat(path)
 	if err != nil {
 		return Report{}, err
 	}
-	fileSize := stat.Size()
-	var completeNameLast string
-
-	header := make([]byte, maxSniffBytes)
 	file, err := os.Open(path)
 	if err != nil {
 		return Report{}, err
 	}
 	defer file.Close()
 
+	return analyzeSource(ctx, file, stat.Size(), path, opts, true)
+}
+
+// AnalyzeReader analyzes size bytes of media read from r. name is used as the report
+// reference and for extension-based format hints; it may be empty.
+func AnalyzeReader(r io.ReaderAt, size int64, name string, opts AnalyzeOptions) (Report, error) {
+	return AnalyzeReaderContext(context.Background(), r, size, name, opts)
+}
+
+// AnalyzeReaderContext is AnalyzeReader with cancellation, see AnalyzeFileContext.
+func AnalyzeReaderContext(ctx context.Context, r io.ReaderAt, size int64, name string, opts AnalyzeOptions) (Report, error) {
+	if size < 0 {
+		return Report{}, fmt.Errorf("invalid size: %d", size)
+	}
+	ctx, cancel := withAnalyzeTimeout(ctx, opts)
+	defer cancel()
+	if err := ctx.Err(); err != nil {
+		return Report{}, &CanceledError{Path: name, Err: err}
+	}
+	return analyzeSource(ctx, io.NewSectionReader(r, 0, size), size, name, opts, false)
+}
+
+type mediaSource interface {
+	io.ReadSeeker
+	io.ReaderAt
+}
+
+// analyzeSource runs format detection and parsing. onDisk reports whether path names the
+// source on the local filesystem, which enables sibling-file lookups (continuous files).
+func analyzeSource(ctx context.Context, file mediaSource, size int64, path string, opts AnalyzeOptions, onDisk bool) (Report, error) {
+	opts = normalizeAnalyzeOptions(opts)
+	fileSize := size
+	var completeNameLast string
+
+	header := make([]byte, maxSniffBytes)
 	n, _ := io.ReadFull(file, header)
 	header = header[:n]
 
@@ -38,7 +80,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 
 	// MediaInfo CLI continuous file names behavior (File_TestContinuousFileNames=1) applies to both
 	// MPEG-TS and BDAV (M2TS) streams.
-	if opts.TestContinuousFileNames && (format == "MPEG-TS" || format == "BDAV") {
+	if onDisk && opts.TestContinuousFileNames && (format == "MPEG-TS" || format == "BDAV") {
 		if set, ok := detectContinuousFileSet(path); ok {
 			completeNameLast = set.LastPath
 			fileSize = set.TotalSize
@@ -46,8 +88,10 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 	}
 
 	general := Stream{Kind: StreamGeneral}
+	if path != "" {
+		general.Fields = append(general.Fields, Field{Name: "Complete name", Value: path})
+	}
 	general.Fields = append(general.Fields,
-		Field{Name: "Complete name", Value: path},
 		Field{Name: "Format", Value: format},
 		Field{Name: "File size", Value: formatBytes(fileSize)},
 	)
@@ -64,7 +108,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 	streams := []Stream{}
 	switch format {
 	case "MPEG-4", "QuickTime":
-		if parsed, ok := ParseMP4(file, stat.Size()); ok {
+		if parsed, ok := parseMP4(file, size, opts.ParseSpeed); ok {
 			info = parsed.Container
 			general.JSON = map[string]string{}
 			for _, field := range parsed.General {
@@ -80,8 +124,8 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				// Preserve fractional seconds in JSON (text Duration drops ms for long runtimes).
 				general.JSON["Duration"] = formatJSONSeconds(info.DurationSeconds)
 			}
-			setOverallBitRate(general.JSON, stat.Size(), info.DurationSeconds)
-			if headerSize, dataSize, footerSize, mdatCount, moovBeforeMdat, ok := mp4TopLevelSizes(file, stat.Size()); ok {
+			setOverallBitRate(general.JSON, size, info.DurationSeconds)
+			if headerSize, dataSize, footerSize, mdatCount, moovBeforeMdat, ok := mp4TopLevelSizes(file, size); ok {
 				general.JSON["HeaderSize"] = strconv.FormatInt(headerSize, 10)
 				general.JSON["DataSize"] = strconv.FormatInt(dataSize, 10)
 				general.JSON["FooterSize"] = strconv.FormatInt(footerSize, 10)
@@ -92,6 +136,9 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				}
 				_ = mdatCount
 			}
+			if parsed.Fragmented {
+				general.JSON["IsFragmented"] = "Yes"
+			}
 			var generalFrameCount string
 			for _, track := range parsed.Tracks {
 				fields := []Field{}
@@ -230,7 +277,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 							streamBytes = int64(math.Round((bitrate * displayDuration) / 8))
 						}
 					}
-					if streamSize := formatStreamSize(streamBytes, stat.Size()); streamSize != "" {
+					if streamSize := formatStreamSize(streamBytes, size); streamSize != "" {
 						fields = appendFieldUnique(fields, Field{Name: "Stream size", Value: streamSize})
 					}
 					if streamBytes > 0 {
@@ -255,7 +302,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 						}
 					}
 					if sourceDuration > 0 {
-						if sourceSize := formatStreamSize(int64(track.SampleBytes), stat.Size()); sourceSize != "" {
+						if sourceSize := formatStreamSize(int64(track.SampleBytes), size); sourceSize != "" {
 							fields = appendFieldUnique(fields, Field{Name: "Source stream size", Value: sourceSize})
 						}
 						jsonExtras["Source_StreamSize"] = strconv.FormatInt(int64(track.SampleBytes), 10)
@@ -291,11 +338,15 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 						generalFrameCount = strconv.FormatUint(track.SampleCount, 10)
 					}
 				}
+				// Fragmented tracks flag sync samples per sample; a constant distance is the GOP length.
+				if track.Kind == StreamVideo && track.SyncInterval > 0 && findField(fields, "Format settings, GOP") == "" {
+					fields = appendFieldUnique(fields, Field{Name: "Format settings, GOP", Value: fmt.Sprintf("N=%d", track.SyncInterval)})
+				}
 				// MP4 AC-3: probe first frame to match MediaInfo's codec details without scanning the whole file.
 				if track.Kind == StreamAudio && findField(fields, "Codec ID") == "ac-3" &&
 					track.FirstChunkOff > 0 && len(track.SampleSizeHead) > 0 {
 					sz := int(track.SampleSizeHead[0])
-					if sz > 0 && int64(track.FirstChunkOff) > 0 && int64(track.FirstChunkOff) < stat.Size() {
+					if sz > 0 && int64(track.FirstChunkOff) > 0 && int64(track.FirstChunkOff) < size {
 						if sz > 1<<16 {
 							sz = 1 << 16
 						}
@@ -445,10 +496,10 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			}
 			// MP4 General StreamSize: remaining bytes after summing track stream sizes.
 			streamSizeSum := sumStreamSizes(streams, true)
-			setRemainingStreamSize(general.JSON, stat.Size(), streamSizeSum)
+			setRemainingStreamSize(general.JSON, size, streamSizeSum)
 		}
 	case "Matroska":
-		if parsed, ok := ParseMatroskaWithOptions(file, stat.Size(), opts); ok {
+		if parsed, ok := ParseMatroskaWithOptions(ctx, file, size, opts); ok {
 			info = parsed.Container
 			general.JSON = map[string]string{}
 			var rawWritingApp string
@@ -473,13 +524,13 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			if info.DurationSeconds > 0 {
 				general.JSON["Duration"] = formatJSONFloat(info.DurationSeconds)
 			}
-			setOverallBitRate(general.JSON, stat.Size(), info.DurationSeconds)
+			setOverallBitRate(general.JSON, size, info.DurationSeconds)
 			general.JSON["IsStreamable"] = "Yes"
 			streamSizeSum := sumStreamSizes(streams, true)
 			// Official mediainfo does not expose large Matroska overhead as General StreamSize when
 			// it's dominated by attachments (fonts).
 			if len(parsed.attachments) == 0 {
-				setRemainingStreamSize(general.JSON, stat.Size(), streamSizeSum)
+				setRemainingStreamSize(general.JSON, size, streamSizeSum)
 			}
 			overallModeField := ""
 			for _, stream := range streams {
@@ -598,7 +649,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			}
 		}
 	case "MPEG-TS":
-		if parsedInfo, parsedStreams, generalFields, ok := ParseMPEGTS(file, stat.Size(), opts.ParseSpeed); ok {
+		if parsedInfo, parsedStreams, generalFields, ok := ParseMPEGTS(ctx, file, size, opts.ParseSpeed); ok {
 			info = parsedInfo
 			general.JSON = map[string]string{}
 			general.JSONRaw = map[string]string{}
@@ -729,7 +780,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			})
 		}
 	case "BDAV":
-		if parsedInfo, parsedStreams, generalFields, ok := ParseBDAV(file, stat.Size(), opts.ParseSpeed); ok {
+		if parsedInfo, parsedStreams, generalFields, ok := ParseBDAV(ctx, file, size, opts.ParseSpeed); ok {
 			info = parsedInfo
 			general.JSON = map[string]string{}
 			general.JSONRaw = map[string]string{}
@@ -801,7 +852,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				if f, err := os.Open(completeNameLast); err == nil {
 					if st, err := f.Stat(); err == nil {
 						lastSize = st.Size()
-						if li, ls, _, ok := ParseBDAV(f, st.Size(), opts.ParseSpeed); ok && li.DurationSeconds > 0 {
+						if li, ls, _, ok := ParseBDAV(ctx, f, st.Size(), opts.ParseSpeed); ok && li.DurationSeconds > 0 {
 							lastInfo = li
 							lastStreams = ls
 						}
@@ -1104,7 +1155,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			}
 		}
 	case "MPEG-PS":
-		psSize := stat.Size()
+		psSize := size
 		psPaths := []string{path}
 		var completeNameLast string
 		dvdExtras := false
@@ -1128,9 +1179,9 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 		var parsedStreams []Stream
 		var ok bool
 		if len(psPaths) > 1 {
-			parsedInfo, parsedStreams, ok = ParseMPEGPSFiles(psPaths, psSize, mpegPSOptions{dvdExtras: dvdExtras, dvdParsing: dvdParsing, parseSpeed: parseSpeed})
+			parsedInfo, parsedStreams, ok = ParseMPEGPSFiles(ctx, psPaths, psSize, mpegPSOptions{dvdExtras: dvdExtras, dvdParsing: dvdParsing, parseSpeed: parseSpeed})
 		} else {
-			parsedInfo, parsedStreams, ok = ParseMPEGPSWithOptions(file, psSize, mpegPSOptions{dvdExtras: dvdExtras, dvdParsing: dvdParsing, parseSpeed: parseSpeed})
+			parsedInfo, parsedStreams, ok = ParseMPEGPSWithOptions(ctx, file, psSize, mpegPSOptions{dvdExtras: dvdExtras, dvdParsing: dvdParsing, parseSpeed: parseSpeed})
 		}
 		if ok {
 			info = parsedInfo
@@ -1295,7 +1346,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			}
 		}
 	case "MPEG Audio":
-		if parsedInfo, parsedStreams, tagJSON, tagJSONRaw, ok := ParseMP3(file, stat.Size()); ok {
+		if parsedInfo, parsedStreams, tagJSON, tagJSONRaw, ok := ParseMP3(file, size); ok {
 			info = parsedInfo
 			streams = parsedStreams
 			// For audio-only formats, the Field-based duration formatting drops milliseconds
@@ -1305,9 +1356,9 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				general.JSON["Duration"] = formatJSONSeconds(info.DurationSeconds)
 			}
 			// Match official: overall bitrate uses audio payload (not trailing junk bytes).
-			payloadSize := stat.Size() - info.StreamOverheadBytes
+			payloadSize := size - info.StreamOverheadBytes
 			if payloadSize < 0 {
-				payloadSize = stat.Size()
+				payloadSize = size
 			}
 			for _, s := range streams {
 				if s.Kind != StreamAudio || s.JSON == nil {
@@ -1347,7 +1398,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			}
 		}
 	case "FLAC":
-		if parsedInfo, parsedStreams, tagJSON, tagJSONRaw, ok := ParseFLAC(file, stat.Size()); ok {
+		if parsedInfo, parsedStreams, tagJSON, tagJSONRaw, ok := ParseFLAC(file, size); ok {
 			info = parsedInfo
 			streams = parsedStreams
 			general.JSON = map[string]string{}
@@ -1358,7 +1409,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			if info.DurationSeconds > 0 {
 				durationMs := int64(math.Round(info.DurationSeconds * 1000))
 				if durationMs > 0 {
-					general.JSON["OverallBitRate"] = strconv.FormatInt((stat.Size()*8000+durationMs/2)/durationMs, 10)
+					general.JSON["OverallBitRate"] = strconv.FormatInt((size*8000+durationMs/2)/durationMs, 10)
 				}
 			}
 			// Official mediainfo sets General StreamSize=0 for FLAC.
@@ -1380,7 +1431,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			}
 		}
 	case "Wave":
-		if parsedInfo, parsedStreams, generalFields, generalJSON, ok := ParseWAV(file, stat.Size()); ok {
+		if parsedInfo, parsedStreams, generalFields, generalJSON, ok := ParseWAV(file, size); ok {
 			info = parsedInfo
 			streams = parsedStreams
 			if len(generalFields) > 0 {
@@ -1393,7 +1444,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				general.JSON = map[string]string{}
 			}
 			if info.DurationSeconds > 0 {
-				setOverallBitRate(general.JSON, stat.Size(), info.DurationSeconds)
+				setOverallBitRate(general.JSON, size, info.DurationSeconds)
 			}
 			if info.StreamOverheadBytes > 0 {
 				general.JSON["StreamSize"] = strconv.FormatInt(info.StreamOverheadBytes, 10)
@@ -1404,8 +1455,25 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				}
 			}
 		}
+	case "AIFF":
+		if parsedInfo, parsedStreams, generalJSON, ok := ParseAIFF(file, size); ok {
+			info = parsedInfo
+			streams = parsedStreams
+			// Like Wave: OverallBitRate uses the full file size; StreamSize is the chunk overhead.
+			if general.JSON == nil {
+				general.JSON = map[string]string{}
+			}
+			if info.DurationSeconds > 0 {
+				setOverallBitRate(general.JSON, size, info.DurationSeconds)
+			}
+			for k, v := range generalJSON {
+				if v != "" {
+					general.JSON[k] = v
+				}
+			}
+		}
 	case "Ogg":
-		if parsedInfo, parsedStreams, generalFields, generalJSON, ok := ParseOgg(file, stat.Size()); ok {
+		if parsedInfo, parsedStreams, generalFields, generalJSON, ok := ParseOgg(file, size); ok {
 			info = parsedInfo
 			streams = parsedStreams
 			if len(generalFields) > 0 {
@@ -1418,7 +1486,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				general.JSON = map[string]string{}
 			}
 			if info.DurationSeconds > 0 {
-				setOverallBitRate(general.JSON, stat.Size(), info.DurationSeconds)
+				setOverallBitRate(general.JSON, size, info.DurationSeconds)
 			}
 			for k, v := range generalJSON {
 				if v != "" {
@@ -1426,8 +1494,57 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				}
 			}
 		}
+	case "IVF", "AV1", "VVC", "AVC", "HEVC", "VC-1":
+		parse := ParseIVF
+		switch format {
+		case "AV1":
+			parse = ParseAV1OBU
+		case "VVC":
+			parse = ParseVVC
+		case "AVC":
+			parse = ParseAVC
+		case "HEVC":
+			parse = ParseHEVC
+		case "VC-1":
+			parse = ParseVC1
+		}
+		if parsedInfo, parsedStreams, ok := parse(file, size, opts.ParseSpeed); ok {
+			info = parsedInfo
+			streams = parsedStreams
+			general.JSON = map[string]string{}
+			if info.DurationSeconds > 0 {
+				setOverallBitRate(general.JSON, size, info.DurationSeconds)
+			}
+			if frameCount := streams[0].JSON["FrameCount"]; frameCount != "" {
+				general.JSON["FrameCount"] = frameCount
+			}
+			setRemainingStreamSize(general.JSON, size, sumStreamSizes(streams, false))
+		}
+	case "AC-3", "E-AC-3", "DTS", "MLP FBA", "ADTS", "LATM":
+		parse := ParseAAC
+		switch format {
+		case "AC-3", "E-AC-3":
+			parse = ParseAC3
+		case "DTS":
+			parse = ParseDTS
+		case "MLP FBA":
+			parse = ParseTrueHD
+		}
+		if parsedInfo, parsedStreams, ok := parse(file, size, opts.ParseSpeed); ok {
+			info = parsedInfo
+			streams = parsedStreams
+			general.JSON = map[string]string{}
+			if info.DurationSeconds > 0 {
+				general.JSON["Duration"] = formatJSONSeconds(info.DurationSeconds)
+				setOverallBitRate(general.JSON, size, info.DurationSeconds)
+			}
+			if frameCount := streams[0].JSON["FrameCount"]; frameCount != "" {
+				general.JSON["FrameCount"] = frameCount
+			}
+			setRemainingStreamSize(general.JSON, size, sumStreamSizes(streams, false))
+		}
 	case "MPEG Video":
-		if parsedInfo, parsedStreams, ok := ParseMPEGVideo(file, stat.Size()); ok {
+		if parsedInfo, parsedStreams, ok := ParseMPEGVideo(file, size); ok {
 			info = parsedInfo
 			streams = parsedStreams
 			general.JSON = map[string]string{}
@@ -1435,7 +1552,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			general.Fields = appendFieldUnique(general.Fields, Field{Name: "FileExtension_Invalid", Value: "mpgv mpv mp1v m1v mp2v m2v"})
 			if info.DurationSeconds > 0 {
 				jsonDuration := math.Round(info.DurationSeconds*1000) / 1000
-				setOverallBitRate(general.JSON, stat.Size(), jsonDuration)
+				setOverallBitRate(general.JSON, size, jsonDuration)
 			}
 			var frameCount string
 			for i := range streams {
@@ -1450,13 +1567,13 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				general.JSON["FrameCount"] = frameCount
 			}
 			streamSizeSum := sumStreamSizes(streams, false)
-			setRemainingStreamSize(general.JSON, stat.Size(), streamSizeSum)
+			setRemainingStreamSize(general.JSON, size, streamSizeSum)
 			general.JSONRaw = map[string]string{
 				"extra": "{\"FileExtension_Invalid\":\"mpgv mpv mp1v m1v mp2v m2v\"}",
 			}
 		}
 	case "AVI":
-		if parsedInfo, parsedStreams, generalFields, interleaved, ok := ParseAVIWithOptions(file, stat.Size(), opts); ok {
+		if parsedInfo, parsedStreams, generalFields, interleaved, ok := ParseAVIWithOptions(ctx, file, size, opts); ok {
 			info = parsedInfo
 			general.JSON = map[string]string{}
 			var rawWritingApp string
@@ -1508,7 +1625,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			if info.DurationSeconds > 0 {
 				jsonDuration := math.Round(info.DurationSeconds*1000) / 1000
 				general.JSON["Duration"] = formatJSONSeconds(jsonDuration)
-				setOverallBitRate(general.JSON, stat.Size(), jsonDuration)
+				setOverallBitRate(general.JSON, size, jsonDuration)
 			}
 			var frameCount string
 			hasVBR := false
@@ -1537,10 +1654,10 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				general.JSON["OverallBitRate_Mode"] = "VBR"
 			}
 			streamSizeSum := sumStreamSizes(streams, false)
-			setRemainingStreamSize(general.JSON, stat.Size(), streamSizeSum)
+			setRemainingStreamSize(general.JSON, size, streamSizeSum)
 		}
 	case "DVD Video":
-		if parsed, ok := parseDVDVideo(path, file, stat.Size(), opts); ok {
+		if parsed, ok := parseDVDVideo(ctx, path, file, size, opts); ok {
 			info = parsed.Container
 			if parsed.FileSize > 0 {
 				general.Fields = setFieldValue(general.Fields, "File size", formatBytes(parsed.FileSize))
@@ -1606,16 +1723,30 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 		}
 	}
 
+	// Parsers bail out with partial results on cancellation; never report those.
+	if err := ctx.Err(); err != nil {
+		return Report{}, &CanceledError{Path: path, Err: err}
+	}
+
 	sortFields(StreamGeneral, general.Fields)
 	for i := range streams {
 		sortFields(streams[i].Kind, streams[i].Fields)
 	}
 	sortStreams(streams)
-	return Report{
-		Ref:     path,
-		General: general,
-		Streams: streams,
-	}, nil
+	if !onDisk {
+		if general.JSON == nil {
+			general.JSON = map[string]string{}
+		}
+		general.JSON["FileSize"] = strconv.FormatInt(fileSize, 10)
+	}
+	report := Report{
+		Ref:        path,
+		General:    general,
+		Streams:    streams,
+		fromReader: !onDisk,
+	}
+	fillTypedInfo(&report)
+	return report, nil
 }
 
 func AnalyzeFiles(paths []string) ([]Report, int, error) {
@@ -1623,49 +1754,148 @@ func AnalyzeFiles(paths []string) ([]Report, int, error) {
 }
 
 func AnalyzeFilesWithOptions(paths []string, opts AnalyzeOptions) ([]Report, int, error) {
-	expanded, err := expandPaths(paths)
+	return AnalyzeFilesContext(context.Background(), paths, opts)
+}
+
+// AnalyzeFilesContext is AnalyzeFilesWithOptions with cancellation. opts.Timeout applies
+// to each file separately.
+//
+// A file that cannot be analyzed does not stop the batch: its Report only carries Ref and
+// Err, the count excludes it and a *BatchError listing every failure is returned along
+// with the reports. Canceling ctx aborts the batch with a *CanceledError.
+func AnalyzeFilesContext(ctx context.Context, paths []string, opts AnalyzeOptions) ([]Report, int, error) {
+	entries, err := expandPaths(paths, opts)
 	if err != nil {
 		return nil, 0, err
 	}
-	reports := make([]Report, 0, len(expanded))
-	for _, path := range expanded {
-		report, err := AnalyzeFileWithOptions(path, opts)
-		if err != nil {
-			return nil, 0, fmt.Errorf("%s: %w", path, err)
+	reports := analyzePaths(ctx, entries, opts)
+	if err := ctx.Err(); err != nil {
+		return nil, 0, &CanceledError{Err: err}
+	}
+	count := 0
+	var failures []*FileError
+	for _, report := range reports {
+		if report.Err != nil {
+			failures = append(failures, &FileError{Path: report.Ref, Err: report.Err})
+			continue
 		}
-		reports = append(reports, report)
+		count++
+	}
+	if len(failures) > 0 {
+		return reports, count, &BatchError{Failures: failures, Total: len(reports)}
 	}
-	return reports, len(reports), nil
+	return reports, count, nil
 }
 
-func expandPaths(paths []string) ([]string, error) {
-	expanded := make([]string, 0, len(paths))
-	for _, path := range paths {
-		info, err := os.Stat(path)
-		if err != nil {
-			return nil, err
-		}
-		if !info.IsDir() {
-			expanded = append(expanded, path)
-			continue
-		}
-		entries, err := os.ReadDir(path)
-		if err != nil {
-			return nil, err
+// AnalyzeFilesFunc is AnalyzeFilesContext for large batches: instead of collecting the
+// reports, it calls fn with each one, in input order, as soon as it and the files before it
+// are analyzed. Only reports finished ahead of an earlier, slower file are held in memory.
+//
+// fn is never called concurrently. An error from fn stops the batch and is returned as-is.
+// Failed files are passed to fn as reports carrying Err and are listed in the returned
+// *BatchError, as with AnalyzeFilesContext.
+func AnalyzeFilesFunc(ctx context.Context, paths []string, opts AnalyzeOptions, fn func(Report) error) (int, error) {
+	entries, err := expandPaths(paths, opts)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	var failures []*FileError
+	err = streamPaths(ctx, entries, opts, func(_ int, report Report) error {
+		if report.Err != nil {
+			failures = append(failures, &FileError{Path: report.Ref, Err: report.Err})
+		} else {
+			count++
 		}
-		names := make([]string, 0, len(entries))
-		for _, entry := range entries {
-			if entry.IsDir() {
+		return fn(report)
+	})
+	if err != nil {
+		return count, err
+	}
+	if err := ctx.Err(); err != nil {
+		return count, &CanceledError{Err: err}
+	}
+	if len(failures) > 0 {
+		return count, &BatchError{Failures: failures, Total: len(entries)}
+	}
+	return count, nil
+}
+
+// analyzePaths analyzes entries on up to opts.Concurrency workers and returns reports in
+// input order. Entries that failed expansion are passed through as failed reports.
+func analyzePaths(ctx context.Context, entries []pathEntry, opts AnalyzeOptions) []Report {
+	reports := make([]Report, len(entries))
+	_ = streamPaths(ctx, entries, opts, func(i int, report Report) error {
+		reports[i] = report
+		return nil
+	})
+	return reports
+}
+
+// streamPaths analyzes entries on up to opts.Concurrency workers and calls emit with each
+// report in input order, from the calling goroutine. It stops early when ctx is canceled
+// or emit fails, returning emit's error.
+func streamPaths(ctx context.Context, entries []pathEntry, opts AnalyzeOptions, emit func(int, Report) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type result struct {
+		index  int
+		report Report
+	}
+	workers := max(min(normalizeAnalyzeOptions(opts).Concurrency, len(entries)), 1)
+	jobs := make(chan int)
+	results := make(chan result, workers)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for i := range jobs {
+				report, err := AnalyzeFileContext(ctx, entries[i].path, opts)
+				if err != nil {
+					report = Report{Ref: entries[i].path, Err: err}
+				}
+				results <- result{index: i, report: report}
+			}
+		})
+	}
+	go func() {
+		defer close(results)
+		defer wg.Wait()
+		defer close(jobs)
+		for i, entry := range entries {
+			if entry.err != nil {
+				results <- result{index: i, report: Report{Ref: entry.path, Err: entry.err}}
 				continue
 			}
-			names = append(names, entry.Name())
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
 		}
-		sort.Strings(names)
-		for _, name := range names {
-			expanded = append(expanded, filepath.Join(path, name))
+	}()
+
+	pending := map[int]Report{}
+	next := 0
+	var emitErr error
+	for res := range results {
+		if emitErr != nil || ctx.Err() != nil {
+			continue // drain so the workers can exit
+		}
+		pending[res.index] = res.report
+		for {
+			report, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			if emitErr = emit(next, report); emitErr != nil {
+				cancel()
+				break
+			}
+			next++
 		}
 	}
-	return expanded, nil
+	return emitErr
 }
 
 func parsePixels(value string) (uint64, bool) {
@@ -0,0 +1,85 @@
+package mediainfo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeFileContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := AnalyzeFileContext(ctx, filepath.Join("samples", "sample.ts"), AnalyzeOptions{})
+	var canceled *CanceledError
+	if !errors.As(err, &canceled) {
+		t.Fatalf("err=%v, want *CanceledError", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err=%v, want context.Canceled", err)
+	}
+}
+
+func TestAnalyzeFileContextTimeout(t *testing.T) {
+	_, err := AnalyzeFileContext(context.Background(), filepath.Join("samples", "sample.mkv"), AnalyzeOptions{Timeout: time.Nanosecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestAnalyzeFilesContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	reports, count, err := AnalyzeFilesContext(ctx, []string{filepath.Join("samples", "sample.mp4")}, AnalyzeOptions{})
+	if !errors.Is(err, context.Canceled) || reports != nil || count != 0 {
+		t.Fatalf("reports=%d count=%d err=%v", len(reports), count, err)
+	}
+}
+
+func TestContainerParsersStopOnCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	open := func(t *testing.T, name string) (*os.File, int64) {
+		t.Helper()
+		file, err := os.Open(filepath.Join("samples", name))
+		if err != nil {
+			t.Fatalf("open: %v", err)
+		}
+		t.Cleanup(func() { _ = file.Close() })
+		stat, err := file.Stat()
+		if err != nil {
+			t.Fatalf("stat: %v", err)
+		}
+		return file, stat.Size()
+	}
+
+	t.Run("ts", func(t *testing.T) {
+		file, size := open(t, "sample.ts")
+		if _, _, _, ok := ParseMPEGTS(ctx, file, size, 0.5); ok {
+			t.Fatalf("ParseMPEGTS ok on canceled context")
+		}
+	})
+	t.Run("mkv", func(t *testing.T) {
+		file, size := open(t, "sample.mkv")
+		if _, ok := ParseMatroskaWithOptions(ctx, file, size, AnalyzeOptions{}); ok {
+			t.Fatalf("ParseMatroskaWithOptions ok on canceled context")
+		}
+	})
+	t.Run("ps", func(t *testing.T) {
+		file, size := open(t, "sample.vob")
+		if _, _, ok := ParseMPEGPSWithOptions(ctx, file, size, mpegPSOptions{parseSpeed: 1}); ok {
+			t.Fatalf("ParseMPEGPSWithOptions ok on canceled context")
+		}
+	})
+	t.Run("avi", func(t *testing.T) {
+		file, size := open(t, "sample.avi")
+		if _, _, _, _, ok := ParseAVIWithOptions(ctx, file, size, AnalyzeOptions{}); ok {
+			t.Fatalf("ParseAVIWithOptions ok on canceled context")
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package mediainfo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CanceledError is returned when analysis stops because its context was canceled or its
+// deadline (including AnalyzeOptions.Timeout) expired. It unwraps to the context error.
+type CanceledError struct {
+	Path string
+	Err  error
+}
+
+func (e *CanceledError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("analysis canceled: %v", e.Err)
+	}
+	return fmt.Sprintf("%s: analysis canceled: %v", e.Path, e.Err)
+}
+
+func (e *CanceledError) Unwrap() error {
+	return e.Err
+}
+
+func withAnalyzeTimeout(ctx context.Context, opts AnalyzeOptions) (context.Context, context.CancelFunc) {
+	if opts.Timeout > 0 {
+		return context.WithTimeout(ctx, opts.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// FileError describes one file of a batch that could not be analyzed.
+type FileError struct {
+	Path string
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError is returned by the batch entry points when some files could not be analyzed.
+// The reports of the other files are still returned.
+type BatchError struct {
+	Failures []*FileError
+	Total    int
+}
+
+func (e *BatchError) Error() string {
+	msgs := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		msgs = append(msgs, failure.Error())
+	}
+	return fmt.Sprintf("%d of %d files failed: %s", len(e.Failures), e.Total, strings.Join(msgs, "; "))
+}
+
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		errs = append(errs, failure)
+	}
+	return errs
+}
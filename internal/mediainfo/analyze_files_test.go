@@ -0,0 +1,80 @@
+package mediainfo
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeFilesConcurrencyKeepsOrder(t *testing.T) {
+	paths := []string{
+		"samples/sample.ts",
+		"samples/sample.mkv",
+		"samples/sample.mp4",
+		"samples/sample.vob",
+		"samples/sample.avi",
+		"samples/sample.flac",
+	}
+	sequential, count, err := AnalyzeFilesWithOptions(paths, AnalyzeOptions{Concurrency: 1})
+	if err != nil {
+		t.Fatalf("sequential: %v", err)
+	}
+	parallel, parallelCount, err := AnalyzeFilesWithOptions(paths, AnalyzeOptions{Concurrency: 4})
+	if err != nil {
+		t.Fatalf("parallel: %v", err)
+	}
+	if count != len(paths) || parallelCount != count {
+		t.Fatalf("count=%d parallel=%d, want %d", count, parallelCount, len(paths))
+	}
+	for i := range parallel {
+		if parallel[i].Ref != paths[i] {
+			t.Fatalf("report %d ref=%q, want %q", i, parallel[i].Ref, paths[i])
+		}
+	}
+	if RenderText(parallel) != RenderText(sequential) {
+		t.Fatalf("parallel output differs from sequential output")
+	}
+}
+
+func TestAnalyzeFilesKeepsGoingAfterFailure(t *testing.T) {
+	paths := []string{"samples/missing.mkv", "samples/sample.flac"}
+	reports, count, err := AnalyzeFilesWithOptions(paths, AnalyzeOptions{})
+	var batch *BatchError
+	if !errors.As(err, &batch) {
+		t.Fatalf("err=%v, want *BatchError", err)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("err=%v, want fs.ErrNotExist in chain", err)
+	}
+	if len(batch.Failures) != 1 || batch.Failures[0].Path != paths[0] || batch.Total != 2 {
+		t.Fatalf("unexpected failures: %+v", batch)
+	}
+	if count != 1 || len(reports) != 2 {
+		t.Fatalf("count=%d reports=%d, want 1/2", count, len(reports))
+	}
+	if reports[0].Err == nil || reports[0].Ref != paths[0] {
+		t.Fatalf("first report should be the failed entry: %+v", reports[0])
+	}
+	if reports[1].Err != nil || findField(reports[1].General.Fields, "Format") != "FLAC" {
+		t.Fatalf("second report should be analyzed: %+v", reports[1])
+	}
+
+	for name, render := range map[string]func([]Report) string{
+		"text": RenderText,
+		"json": RenderJSON,
+		"xml":  RenderXML,
+		"csv":  RenderCSV,
+		"html": RenderHTML,
+	} {
+		out := render(reports)
+		if !strings.Contains(out, "missing.mkv") || !strings.Contains(strings.ToLower(out), "error") {
+			t.Fatalf("%s output does not show the failed entry:\n%s", name, out)
+		}
+	}
+	var root []map[string]any
+	if err := json.Unmarshal([]byte(RenderJSON(reports)), &root); err != nil {
+		t.Fatalf("parse json: %v", err)
+	}
+}
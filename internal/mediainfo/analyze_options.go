@@ -1,12 +1,48 @@
 package mediainfo
 
+import (
+	"runtime"
+	"time"
+)
+
 type AnalyzeOptions struct {
 	ParseSpeed                 float64
 	HasParseSpeed              bool
 	TestContinuousFileNames    bool
 	HasTestContinuousFileNames bool
+	// Timeout bounds the analysis of each file; zero means no limit.
+	Timeout time.Duration
+	// Concurrency is the number of files analyzed in parallel by the batch entry points;
+	// zero means GOMAXPROCS.
+	Concurrency int
+	// Recursive makes directory arguments expand to the files of all subdirectories.
+	Recursive bool
+	// MaxDepth limits recursive expansion; 1 only lists the directory itself. Zero means
+	// no limit.
+	MaxDepth int
+	// Include and Exclude filter files found by directory expansion (explicit file
+	// arguments are always analyzed). Patterns use path.Match syntax and are matched
+	// against the base name and the slash-separated path relative to the directory
+	// argument. Exclude patterns also prune directories; a trailing "/" restricts a
+	// pattern to directories.
+	Include []string
+	Exclude []string
+	// Symlinks selects how directory expansion treats symbolic links.
+	Symlinks SymlinkPolicy
 }
 
+// SymlinkPolicy selects how directory expansion treats symbolic links.
+type SymlinkPolicy int
+
+const (
+	// SymlinkFiles analyzes links to files but does not descend into linked directories.
+	SymlinkFiles SymlinkPolicy = iota
+	// SymlinkFollow also descends into linked directories (each real directory once).
+	SymlinkFollow
+	// SymlinkSkip ignores symbolic links found in directories.
+	SymlinkSkip
+)
+
 func defaultAnalyzeOptions() AnalyzeOptions {
 	return AnalyzeOptions{ParseSpeed: 0.5, TestContinuousFileNames: false}
 }
@@ -24,5 +60,8 @@ func normalizeAnalyzeOptions(opts AnalyzeOptions) AnalyzeOptions {
 	if opts.ParseSpeed > 1 {
 		opts.ParseSpeed = 1
 	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = runtime.GOMAXPROCS(0)
+	}
 	return opts
 }
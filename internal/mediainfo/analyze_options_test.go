@@ -1,6 +1,9 @@
 package mediainfo
 
-import "testing"
+import (
+	"runtime"
+	"testing"
+)
 
 func TestDefaultAnalyzeOptions(t *testing.T) {
 	opts := defaultAnalyzeOptions()
@@ -21,3 +24,12 @@ func TestNormalizeAnalyzeOptionsDefaults(t *testing.T) {
 		t.Fatalf("TestContinuousFileNames=%v, want false", opts.TestContinuousFileNames)
 	}
 }
+
+func TestNormalizeAnalyzeOptionsConcurrency(t *testing.T) {
+	if got := normalizeAnalyzeOptions(AnalyzeOptions{}).Concurrency; got != runtime.GOMAXPROCS(0) {
+		t.Fatalf("Concurrency=%d, want GOMAXPROCS", got)
+	}
+	if got := normalizeAnalyzeOptions(AnalyzeOptions{Concurrency: 3}).Concurrency; got != 3 {
+		t.Fatalf("Concurrency=%d, want 3", got)
+	}
+}
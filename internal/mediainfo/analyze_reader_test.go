@@ -0,0 +1,57 @@
+package mediainfo
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeReaderMatchesFile(t *testing.T) {
+	for _, name := range []string{"sample.mkv", "sample.mp4", "sample.ts", "sample.vob"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join("samples", name)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read sample: %v", err)
+			}
+			fromFile, err := AnalyzeFile(path)
+			if err != nil {
+				t.Fatalf("analyze file: %v", err)
+			}
+			fromReader, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), path, defaultAnalyzeOptions())
+			if err != nil {
+				t.Fatalf("analyze reader: %v", err)
+			}
+			if got, want := RenderText([]Report{fromReader}), RenderText([]Report{fromFile}); got != want {
+				t.Fatalf("text mismatch:\n%s\nwant:\n%s", got, want)
+			}
+			jsonOut := RenderJSON([]Report{fromReader})
+			if strings.Contains(jsonOut, "File_Modified_Date") {
+				t.Fatalf("reader report should not carry file times: %s", jsonOut)
+			}
+			if !strings.Contains(jsonOut, "\"FileSize\":\""+strconv.Itoa(len(data))+"\"") {
+				t.Fatalf("missing FileSize: %s", jsonOut)
+			}
+		})
+	}
+}
+
+func TestAnalyzeReaderWithoutName(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("samples", "sample.flac"))
+	if err != nil {
+		t.Fatalf("read sample: %v", err)
+	}
+	report, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), "", AnalyzeOptions{})
+	if err != nil {
+		t.Fatalf("analyze reader: %v", err)
+	}
+	if got := findField(report.General.Fields, "Format"); got != "FLAC" {
+		t.Fatalf("Format=%q, want FLAC", got)
+	}
+	if got := findField(report.General.Fields, "Complete name"); got != "" {
+		t.Fatalf("Complete name=%q, want empty", got)
+	}
+}
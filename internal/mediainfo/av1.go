@@ -0,0 +1,511 @@
+package mediainfo
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+// AV1 OBU types (AV1 specification, 6.2.2).
+const (
+	av1OBUSequenceHeader    = 1
+	av1OBUTemporalDelimiter = 2
+	av1OBUMetadata          = 5
+)
+
+// AV1 metadata types (6.7.1).
+const (
+	av1MetadataHDRCLL  = 1
+	av1MetadataHDRMDCV = 2
+	av1MetadataITUTT35 = 4
+)
+
+// av1SequenceHeader is what MediaInfo reports from an AV1 sequence header OBU, or from the
+// fixed fields of an av1C box when it carries none.
+type av1SequenceHeader struct {
+	profile      byte
+	level        byte
+	tier         byte
+	bitDepth     int
+	monochrome   bool
+	subsamplingX bool
+	subsamplingY bool
+	// colorDescription is set when the primaries, transfer and matrix are signaled.
+	colorDescription bool
+	primaries        uint64
+	transfer         uint64
+	matrix           uint64
+	fullRange        bool
+	hasColorRange    bool
+	filmGrain        bool
+	width            uint64
+	height           uint64
+	// frameRate is the display rate of timing_info, when present and constant.
+	frameRate float64
+}
+
+func av1ProfileName(profile byte) string {
+	switch profile {
+	case 0:
+		return "Main"
+	case 1:
+		return "High"
+	case 2:
+		return "Professional"
+	default:
+		return ""
+	}
+}
+
+// av1LevelName maps seq_level_idx to its level: 2.0 to 7.3, four levels per major.
+func av1LevelName(idx byte) string {
+	if idx >= 24 {
+		return ""
+	}
+	return fmt.Sprintf("%d.%d", 2+idx>>2, idx&3)
+}
+
+func (seq av1SequenceHeader) chromaSubsampling() string {
+	switch {
+	case seq.monochrome:
+		return "4:0:0"
+	case seq.subsamplingX && seq.subsamplingY:
+		return "4:2:0"
+	case seq.subsamplingX:
+		return "4:2:2"
+	default:
+		return "4:4:4"
+	}
+}
+
+// readLEB128 decodes an unsigned LEB128 value and returns it with its length; the length
+// is 0 when the value is truncated or longer than 8 bytes.
+func readLEB128(buf []byte) (uint64, int) {
+	var value uint64
+	for i := 0; i < 8 && i < len(buf); i++ {
+		value |= uint64(buf[i]&0x7F) << (7 * i)
+		if buf[i]&0x80 == 0 {
+			return value, i + 1
+		}
+	}
+	return 0, 0
+}
+
+// parseAV1OBUHeader reads an OBU header and returns the OBU type, the header length
+// (including the size field) and the payload size, which may exceed buf. Without a size
+// field the OBU spans the rest of buf.
+func parseAV1OBUHeader(buf []byte) (obuType byte, headerSize int, payloadSize int, ok bool) {
+	if len(buf) < 1 || buf[0]&0x80 != 0 {
+		return 0, 0, 0, false
+	}
+	obuType = (buf[0] >> 3) & 0x0F
+	headerSize = 1
+	if buf[0]&0x04 != 0 { // obu_extension_flag
+		headerSize++
+	}
+	if len(buf) < headerSize {
+		return 0, 0, 0, false
+	}
+	if buf[0]&0x02 == 0 { // obu_has_size_field
+		return obuType, headerSize, len(buf) - headerSize, true
+	}
+	size, n := readLEB128(buf[headerSize:])
+	if n == 0 || size > 1<<31 {
+		return 0, 0, 0, false
+	}
+	return obuType, headerSize + n, int(size), true
+}
+
+// forEachAV1OBU calls fn for every OBU of a low overhead bitstream (Section 5) until fn
+// returns false or the data ends.
+func forEachAV1OBU(buf []byte, fn func(obuType byte, payload []byte) bool) {
+	for len(buf) > 0 {
+		obuType, headerSize, payloadSize, ok := parseAV1OBUHeader(buf)
+		if !ok || headerSize+payloadSize > len(buf) {
+			return
+		}
+		if !fn(obuType, buf[headerSize:headerSize+payloadSize]) {
+			return
+		}
+		buf = buf[headerSize+payloadSize:]
+	}
+}
+
+// readAV1UVLC reads the uvlc() code of timing_info.
+func readAV1UVLC(br *bitReader) uint64 {
+	zeros := 0
+	for {
+		bit := br.readBitsValue(1)
+		if bit == ^uint64(0) || zeros >= 32 {
+			return ^uint64(0)
+		}
+		if bit == 1 {
+			break
+		}
+		zeros++
+	}
+	if zeros == 0 {
+		return 0
+	}
+	return br.readBitsValue(uint8(zeros)) + (1 << zeros) - 1
+}
+
+// parseAV1SequenceHeader parses a sequence_header_obu payload (5.5) up to
+// film_grain_params_present.
+func parseAV1SequenceHeader(payload []byte) (av1SequenceHeader, bool) {
+	br := newBitReader(payload)
+	seq := av1SequenceHeader{profile: byte(br.readBitsValue(3))}
+	_ = br.readBitsValue(1) // still_picture
+	reduced := br.readBitsValue(1) == 1
+	if reduced {
+		seq.level = byte(br.readBitsValue(5))
+	} else {
+		decoderModelInfo := false
+		bufferDelayLength := 0
+		if br.readBitsValue(1) == 1 { // timing_info_present_flag
+			ticks := br.readBitsValue(32)
+			timeScale := br.readBitsValue(32)
+			if br.readBitsValue(1) == 1 { // equal_picture_interval
+				perPicture := readAV1UVLC(br)
+				if ticks > 0 && perPicture != ^uint64(0) {
+					seq.frameRate = float64(timeScale) / (float64(ticks) * float64(perPicture+1))
+				}
+			}
+			decoderModelInfo = br.readBitsValue(1) == 1
+			if decoderModelInfo {
+				bufferDelayLength = int(br.readBitsValue(5)) + 1
+				_ = br.readBitsValue(32) // num_units_in_decoding_tick
+				_ = br.readBitsValue(10) // buffer_removal_time_length_minus_1, frame_presentation_time_length_minus_1
+			}
+		}
+		initialDisplayDelay := br.readBitsValue(1) == 1
+		operatingPoints := int(br.readBitsValue(5)) + 1
+		for i := 0; i < operatingPoints; i++ {
+			_ = br.readBitsValue(12) // operating_point_idc
+			level := byte(br.readBitsValue(5))
+			tier := byte(0)
+			if level > 7 {
+				tier = byte(br.readBitsValue(1))
+			}
+			if i == 0 {
+				seq.level, seq.tier = level, tier
+			}
+			if decoderModelInfo && br.readBitsValue(1) == 1 {
+				_ = br.readBitsValue(uint8(bufferDelayLength)) // decoder_buffer_delay
+				_ = br.readBitsValue(uint8(bufferDelayLength)) // encoder_buffer_delay
+				_ = br.readBitsValue(1)                        // low_delay_mode_flag
+			}
+			if initialDisplayDelay && br.readBitsValue(1) == 1 {
+				_ = br.readBitsValue(4)
+			}
+		}
+	}
+	widthBits := uint8(br.readBitsValue(4)) + 1
+	heightBits := uint8(br.readBitsValue(4)) + 1
+	seq.width = br.readBitsValue(widthBits) + 1
+	seq.height = br.readBitsValue(heightBits) + 1
+	if !reduced && br.readBitsValue(1) == 1 { // frame_id_numbers_present_flag
+		_ = br.readBitsValue(7)
+	}
+	_ = br.readBitsValue(3) // use_128x128_superblock, enable_filter_intra, enable_intra_edge_filter
+	if !reduced {
+		_ = br.readBitsValue(4) // interintra, masked compound, warped motion, dual filter
+		orderHint := br.readBitsValue(1) == 1
+		if orderHint {
+			_ = br.readBitsValue(2) // enable_jnt_comp, enable_ref_frame_mvs
+		}
+		forceScreenContentTools := uint64(2)
+		if br.readBitsValue(1) == 0 { // seq_choose_screen_content_tools
+			forceScreenContentTools = br.readBitsValue(1)
+		}
+		if forceScreenContentTools > 0 && br.readBitsValue(1) == 0 { // seq_choose_integer_mv
+			_ = br.readBitsValue(1)
+		}
+		if orderHint {
+			_ = br.readBitsValue(3)
+		}
+	}
+	_ = br.readBitsValue(3) // enable_superres, enable_cdef, enable_restoration
+
+	// color_config (5.5.2)
+	highBitDepth := br.readBitsValue(1) == 1
+	seq.bitDepth = 8
+	switch {
+	case seq.profile == 2 && highBitDepth:
+		seq.bitDepth = 10
+		if br.readBitsValue(1) == 1 {
+			seq.bitDepth = 12
+		}
+	case highBitDepth:
+		seq.bitDepth = 10
+	}
+	if seq.profile != 1 {
+		seq.monochrome = br.readBitsValue(1) == 1
+	}
+	seq.primaries, seq.transfer, seq.matrix = 2, 2, 2
+	if br.readBitsValue(1) == 1 {
+		seq.colorDescription = true
+		seq.primaries = br.readBitsValue(8)
+		seq.transfer = br.readBitsValue(8)
+		seq.matrix = br.readBitsValue(8)
+	}
+	seq.hasColorRange = true
+	switch {
+	case seq.monochrome:
+		seq.fullRange = br.readBitsValue(1) == 1
+		seq.subsamplingX, seq.subsamplingY = true, true
+	case seq.primaries == 1 && seq.transfer == 13 && seq.matrix == 0:
+		// sRGB: always full range 4:4:4.
+		seq.fullRange = true
+	default:
+		seq.fullRange = br.readBitsValue(1) == 1
+		switch seq.profile {
+		case 0:
+			seq.subsamplingX, seq.subsamplingY = true, true
+		case 1:
+		default:
+			if seq.bitDepth == 12 {
+				seq.subsamplingX = br.readBitsValue(1) == 1
+				if seq.subsamplingX {
+					seq.subsamplingY = br.readBitsValue(1) == 1
+				}
+			} else {
+				seq.subsamplingX = true
+			}
+		}
+		if seq.subsamplingX && seq.subsamplingY {
+			_ = br.readBitsValue(2) // chroma_sample_position
+		}
+	}
+	if !seq.monochrome {
+		_ = br.readBitsValue(1) // separate_uv_delta_q
+	}
+	grain := br.readBitsValue(1)
+	if grain == ^uint64(0) {
+		return av1SequenceHeader{}, false
+	}
+	seq.filmGrain = grain == 1
+	return seq, true
+}
+
+// parseAV1Config reads an AV1CodecConfigurationRecord (av1C, Matroska V_AV1 CodecPrivate).
+// The sequence header in configOBUs wins over the fixed fields; metadata OBUs there are
+// added to hdr.
+func parseAV1Config(payload []byte, hdr *hevcHDRInfo) (av1SequenceHeader, bool) {
+	if len(payload) < 4 || payload[0] != 0x81 {
+		return av1SequenceHeader{}, false
+	}
+	seq := av1SequenceHeader{
+		profile:      payload[1] >> 5,
+		level:        payload[1] & 0x1F,
+		tier:         payload[2] >> 7,
+		bitDepth:     8,
+		monochrome:   payload[2]&0x10 != 0,
+		subsamplingX: payload[2]&0x08 != 0,
+		subsamplingY: payload[2]&0x04 != 0,
+	}
+	if payload[2]&0x40 != 0 {
+		seq.bitDepth = 10
+		if payload[2]&0x20 != 0 {
+			seq.bitDepth = 12
+		}
+	}
+	parseAV1OBUs(payload[4:], &seq, hdr)
+	return seq, true
+}
+
+// parseAV1OBUs reads the sequence header and HDR metadata of a low overhead OBU sequence
+// into seq and hdr. It reports whether a sequence header was found.
+func parseAV1OBUs(buf []byte, seq *av1SequenceHeader, hdr *hevcHDRInfo) bool {
+	found := false
+	forEachAV1OBU(buf, func(obuType byte, payload []byte) bool {
+		switch obuType {
+		case av1OBUSequenceHeader:
+			if parsed, ok := parseAV1SequenceHeader(payload); ok {
+				*seq = parsed
+				found = true
+			}
+		case av1OBUMetadata:
+			if hdr != nil {
+				parseAV1Metadata(payload, hdr)
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// parseAV1Metadata adds HDR metadata OBUs to hdr: content light level, mastering display
+// color volume and HDR10+ (ITU-T T.35), which share the HEVC SEI payload layouts.
+func parseAV1Metadata(payload []byte, hdr *hevcHDRInfo) {
+	metadataType, n := readLEB128(payload)
+	if n == 0 {
+		return
+	}
+	payload = payload[n:]
+	switch metadataType {
+	case av1MetadataHDRCLL:
+		parseContentLightLevel(payload, hdr)
+	case av1MetadataHDRMDCV:
+		if len(payload) < 24 {
+			return
+		}
+		// AV1 chromaticities are 0.16 fixed point, HEVC ones are in 0.00002 units; the
+		// luminances are 24.8 and 18.14 fixed point. The primaries are in R, G, B order,
+		// which masteringDisplayPrimariesName matches like any other.
+		var primaries [8]uint16
+		for i := range primaries {
+			primaries[i] = uint16(uint32(binary.BigEndian.Uint16(payload[i*2:i*2+2])) * 50000 >> 16)
+		}
+		if hdr.masteringPrimaries == "" {
+			hdr.masteringPrimaries = masteringDisplayPrimariesName(primaries)
+		}
+		hdr.masteringLuminanceMax = float64(binary.BigEndian.Uint32(payload[16:20])) / 256
+		hdr.masteringLuminanceMin = float64(binary.BigEndian.Uint32(payload[20:24])) / 16384
+		hdr.hasMastering = true
+	case av1MetadataITUTT35:
+		parseHEVCUserDataRegistered(payload, hdr)
+	}
+}
+
+// parseAV1SampleHDR reads the HDR metadata OBUs of a temporal unit.
+func parseAV1SampleHDR(sample []byte, hdr *hevcHDRInfo) {
+	forEachAV1OBU(sample, func(obuType byte, payload []byte) bool {
+		if obuType == av1OBUMetadata {
+			parseAV1Metadata(payload, hdr)
+		}
+		return !hdr.complete()
+	})
+}
+
+// av1FormatFields returns the profile, tier, chroma, bit depth and film grain fields of a
+// sequence header.
+func av1FormatFields(seq av1SequenceHeader) []Field {
+	var fields []Field
+	if profile := av1ProfileName(seq.profile); profile != "" {
+		if level := av1LevelName(seq.level); level != "" {
+			profile += "@L" + level
+		}
+		fields = append(fields, Field{Name: "Format profile", Value: profile})
+	}
+	if seq.tier == 1 {
+		fields = append(fields, Field{Name: "Format tier", Value: "High"})
+	}
+	if seq.filmGrain {
+		fields = append(fields, Field{Name: "Format settings, Film grain", Value: "Yes"})
+	}
+	fields = append(fields, Field{Name: "Chroma subsampling", Value: seq.chromaSubsampling()})
+	if seq.bitDepth > 0 {
+		fields = append(fields, Field{Name: "Bit depth", Value: formatBitDepth(uint8(seq.bitDepth))})
+	}
+	return fields
+}
+
+// spsInfo returns the color description of seq in the form the AVC and HEVC parsers use.
+func (seq av1SequenceHeader) spsInfo() h264SPSInfo {
+	info := h264SPSInfo{
+		ChromaFormat:  seq.chromaSubsampling(),
+		BitDepth:      seq.bitDepth,
+		HasColorRange: seq.hasColorRange,
+		Width:         seq.width,
+		Height:        seq.height,
+	}
+	if seq.hasColorRange {
+		info.ColorRange = "Limited"
+		if seq.fullRange {
+			info.ColorRange = "Full"
+		}
+	}
+	if seq.colorDescription {
+		info.HasColorDescription = true
+		info.ColorPrimaries = matroskaColorPrimariesName(seq.primaries)
+		info.TransferCharacteristics = matroskaTransferName(seq.transfer)
+		info.MatrixCoefficients = matroskaMatrixName(seq.matrix)
+	}
+	return info
+}
+
+// buildAV1Fields returns the video fields of an AV1 stream: format, color and HDR
+// metadata. The color and HDR JSON keys go to jsonExtras.
+func buildAV1Fields(seq av1SequenceHeader, hdr hevcHDRInfo, jsonExtras map[string]string) []Field {
+	fields := av1FormatFields(seq)
+	color := seq.spsInfo()
+	space := "YUV"
+	if seq.monochrome {
+		space = "Y"
+	}
+	fields = append(fields, Field{Name: "Color space", Value: space})
+	fields = appendColorInfoFields(fields, color, jsonExtras)
+	return appendHDRInfoFields(fields, hdr, jsonExtras)
+}
+
+// appendColorInfoFields adds the color range, primaries, transfer and matrix signaled in
+// a bitstream.
+func appendColorInfoFields(fields []Field, color h264SPSInfo, jsonExtras map[string]string) []Field {
+	if color.HasColorRange {
+		fields = appendFieldUnique(fields, Field{Name: "Color range", Value: color.ColorRange})
+	}
+	for _, c := range []struct{ name, key, value string }{
+		{"Color primaries", "colour_primaries", color.ColorPrimaries},
+		{"Transfer characteristics", "transfer_characteristics", color.TransferCharacteristics},
+		{"Matrix coefficients", "matrix_coefficients", color.MatrixCoefficients},
+	} {
+		if c.value != "" {
+			fields = appendFieldUnique(fields, Field{Name: c.name, Value: c.value})
+			jsonExtras[c.key] = c.value
+			jsonExtras[c.key+"_Source"] = "Stream"
+		}
+	}
+	if color.HasColorRange {
+		jsonExtras["colour_range"] = color.ColorRange
+		jsonExtras["colour_range_Source"] = "Stream"
+	}
+	if color.HasColorDescription {
+		jsonExtras["colour_description_present"] = "Yes"
+		jsonExtras["colour_description_present_Source"] = "Stream"
+	}
+	return fields
+}
+
+// appendHDRInfoFields adds the HDR format, mastering display and content light level
+// fields of stream HDR metadata.
+func appendHDRInfoFields(fields []Field, hdr hevcHDRInfo, jsonExtras map[string]string) []Field {
+	switch {
+	case hdr.hdr10Plus:
+		fields = mergeHDRFormatField(fields, formatHDR10Plus(hdr))
+		jsonExtras["HDR_Format"] = "SMPTE ST 2094 App 4"
+		jsonExtras["HDR_Format_Version"] = strconv.Itoa(hdr.hdr10PlusVersion)
+		jsonExtras["HDR_Format_Compatibility"] = "HDR10+ Profile A"
+		if hdr.hdr10PlusToneMapping {
+			jsonExtras["HDR_Format_Compatibility"] = "HDR10+ Profile B"
+		}
+	case hdr.hasMastering:
+		fields = mergeHDRFormatField(fields, "SMPTE ST 2086, HDR10 compatible")
+		jsonExtras["HDR_Format"] = "SMPTE ST 2086"
+		jsonExtras["HDR_Format_Compatibility"] = "HDR10"
+	}
+	if hdr.masteringPrimaries != "" {
+		fields = append(fields, Field{Name: "Mastering display color primaries", Value: hdr.masteringPrimaries})
+		jsonExtras["MasteringDisplay_ColorPrimaries"] = hdr.masteringPrimaries
+		jsonExtras["MasteringDisplay_ColorPrimaries_Source"] = "Stream"
+	}
+	if hdr.masteringLuminanceMin > 0 && hdr.masteringLuminanceMax > 0 {
+		lum := formatMasteringLuminance(hdr.masteringLuminanceMin, hdr.masteringLuminanceMax)
+		fields = append(fields, Field{Name: "Mastering display luminance", Value: lum})
+		jsonExtras["MasteringDisplay_Luminance"] = lum
+		jsonExtras["MasteringDisplay_Luminance_Source"] = "Stream"
+	}
+	if hdr.maxCLL > 0 {
+		value := fmt.Sprintf("%d cd/m2", hdr.maxCLL)
+		fields = append(fields, Field{Name: "Maximum Content Light Level", Value: value})
+		jsonExtras["MaxCLL"] = value
+		jsonExtras["MaxCLL_Source"] = "Stream"
+	}
+	if hdr.maxFALL > 0 {
+		value := fmt.Sprintf("%d cd/m2", hdr.maxFALL)
+		fields = append(fields, Field{Name: "Maximum Frame-Average Light Level", Value: value})
+		jsonExtras["MaxFALL"] = value
+		jsonExtras["MaxFALL_Source"] = "Stream"
+	}
+	return fields
+}
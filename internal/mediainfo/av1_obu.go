@@ -0,0 +1,150 @@
+package mediainfo
+
+import "io"
+
+// av1OBUProbeUnits is the number of temporal units read for HDR metadata below
+// ParseSpeed 1.
+const av1OBUProbeUnits = 30
+
+// isAV1LowOverhead reports whether buf starts like a low overhead AV1 bitstream (Section 5,
+// .obu): a temporal delimiter OBU with an empty size field, then a sequence header.
+func isAV1LowOverhead(buf []byte) bool {
+	return len(buf) >= 3 && buf[0] == 0x12 && buf[1] == 0x00 && buf[2]&0xFA == 0x0A
+}
+
+// isAV1AnnexB reports whether buf starts like an Annex B AV1 bitstream: the
+// temporal_unit_size, frame_unit_size and obu_length of a temporal delimiter OBU.
+func isAV1AnnexB(buf []byte) bool {
+	pos := 0
+	var sizes [3]uint64
+	for i := range sizes {
+		value, n := readLEB128(buf[pos:])
+		if n == 0 {
+			return false
+		}
+		sizes[i] = value
+		pos += n
+	}
+	if pos >= len(buf) || sizes[2] == 0 || sizes[1] < sizes[2] || sizes[0] < sizes[1] {
+		return false
+	}
+	obuType, _, _, ok := parseAV1OBUHeader(buf[pos : pos+1])
+	return ok && obuType == av1OBUTemporalDelimiter
+}
+
+// forEachAV1AnnexBOBU calls fn for every OBU of an Annex B temporal unit payload.
+func forEachAV1AnnexBOBU(unit []byte, fn func(obuType byte, payload []byte) bool) {
+	for len(unit) > 0 {
+		frameSize, n := readLEB128(unit)
+		if n == 0 || uint64(len(unit)-n) < frameSize {
+			return
+		}
+		frame := unit[n : n+int(frameSize)]
+		unit = unit[n+int(frameSize):]
+		for len(frame) > 0 {
+			obuLength, n := readLEB128(frame)
+			if n == 0 || uint64(len(frame)-n) < obuLength {
+				return
+			}
+			obuType, headerSize, payloadSize, ok := parseAV1OBUHeader(frame[n : n+int(obuLength)])
+			if ok && uint64(headerSize+payloadSize) > obuLength {
+				return
+			}
+			if ok && !fn(obuType, frame[n+headerSize:n+headerSize+payloadSize]) {
+				return
+			}
+			frame = frame[n+int(obuLength):]
+		}
+	}
+}
+
+// ParseAV1OBU reads raw AV1 bitstreams in the low overhead (.obu) or Annex B format. Every
+// temporal unit is counted; the frame rate comes from the timing info of the sequence
+// header, when present.
+func ParseAV1OBU(r io.ReaderAt, size int64, parseSpeed float64) (ContainerInfo, []Stream, bool) {
+	head := make([]byte, min(size, 16))
+	if _, err := r.ReadAt(head, 0); err != nil && err != io.EOF {
+		return ContainerInfo{}, nil, false
+	}
+	var (
+		units  int64
+		seq    av1SequenceHeader
+		hasSeq bool
+		hdr    hevcHDRInfo
+	)
+	probing := func() bool {
+		return !hasSeq || (units <= av1OBUProbeUnits || parseSpeed >= 1) && !hdr.complete()
+	}
+	visit := func(obuType byte, payload []byte) bool {
+		switch obuType {
+		case av1OBUSequenceHeader:
+			if !hasSeq {
+				seq, hasSeq = parseAV1SequenceHeader(payload)
+			}
+		case av1OBUMetadata:
+			parseAV1Metadata(payload, &hdr)
+		}
+		return true
+	}
+
+	switch {
+	case isAV1LowOverhead(head):
+		var obuHeader [10]byte
+		for offset := int64(0); offset < size; {
+			n, _ := r.ReadAt(obuHeader[:min(int64(len(obuHeader)), size-offset)], offset)
+			obuType, headerSize, payloadSize, ok := parseAV1OBUHeader(obuHeader[:n])
+			if !ok || obuHeader[0]&0x02 == 0 {
+				break
+			}
+			if obuType == av1OBUTemporalDelimiter {
+				units++
+			}
+			payloadOffset := offset + int64(headerSize)
+			if payloadOffset+int64(payloadSize) > size {
+				break
+			}
+			if (obuType == av1OBUSequenceHeader || obuType == av1OBUMetadata) && probing() && payloadSize <= maxIVFFrameSize {
+				payload := make([]byte, payloadSize)
+				if _, err := r.ReadAt(payload, payloadOffset); err == nil || err == io.EOF {
+					visit(obuType, payload)
+				}
+			}
+			offset = payloadOffset + int64(payloadSize)
+		}
+	case isAV1AnnexB(head):
+		var sizeField [8]byte
+		for offset := int64(0); offset < size; {
+			n, _ := r.ReadAt(sizeField[:min(int64(len(sizeField)), size-offset)], offset)
+			unitSize, m := readLEB128(sizeField[:n])
+			if m == 0 || offset+int64(m)+int64(unitSize) > size {
+				break
+			}
+			units++
+			if probing() && unitSize <= maxIVFFrameSize {
+				unit := make([]byte, unitSize)
+				if _, err := r.ReadAt(unit, offset+int64(m)); err == nil || err == io.EOF {
+					forEachAV1AnnexBOBU(unit, visit)
+				}
+			}
+			offset += int64(m) + int64(unitSize)
+		}
+	default:
+		return ContainerInfo{}, nil, false
+	}
+	if !hasSeq {
+		return ContainerInfo{}, nil, false
+	}
+
+	fields := []Field{
+		{Name: "Format", Value: "AV1"},
+		{Name: "Format/Info", Value: "AOMedia Video 1"},
+	}
+	jsonExtras := map[string]string{}
+	fields = append(fields, buildAV1Fields(seq, hdr, jsonExtras)...)
+	fields = appendVideoStreamStats(fields, jsonExtras, units, seq.frameRate, size, size, seq.width, seq.height)
+	info := ContainerInfo{}
+	if seq.frameRate > 0 {
+		info.DurationSeconds = float64(units) / seq.frameRate
+	}
+	return info, []Stream{{Kind: StreamVideo, Fields: fields, JSON: jsonExtras, JSONSkipStreamOrder: true}}, true
+}
@@ -0,0 +1,271 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+type testBitWriter struct {
+	buf  []byte
+	bits int
+}
+
+func (w *testBitWriter) put(value uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if w.bits%8 == 0 {
+			w.buf = append(w.buf, 0)
+		}
+		if value>>i&1 == 1 {
+			w.buf[len(w.buf)-1] |= 0x80 >> (w.bits % 8)
+		}
+		w.bits++
+	}
+}
+
+// testAV1SequenceHeader is a Main 4.1, 10-bit 4:2:0 1920x1080 24 fps BT.2020 PQ sequence
+// header with film grain.
+func testAV1SequenceHeader() []byte {
+	w := &testBitWriter{}
+	w.put(0, 3)  // seq_profile
+	w.put(0, 2)  // still_picture, reduced_still_picture_header
+	w.put(1, 1)  // timing_info_present_flag
+	w.put(1, 32) // num_units_in_display_tick
+	w.put(24, 32)
+	w.put(1, 1) // equal_picture_interval
+	w.put(1, 1) // num_ticks_per_picture_minus_1 = 0
+	w.put(0, 1) // decoder_model_info_present_flag
+	w.put(0, 1) // initial_display_delay_present_flag
+	w.put(0, 5) // operating_points_cnt_minus_1
+	w.put(0, 12)
+	w.put(9, 5) // seq_level_idx 4.1
+	w.put(0, 1) // seq_tier
+	w.put(11, 4)
+	w.put(10, 4)
+	w.put(1919, 12)
+	w.put(1079, 11)
+	w.put(0, 1) // frame_id_numbers_present_flag
+	w.put(0, 3)
+	w.put(0, 4)
+	w.put(1, 1) // enable_order_hint
+	w.put(0, 2)
+	w.put(1, 1) // seq_choose_screen_content_tools
+	w.put(1, 1) // seq_choose_integer_mv
+	w.put(6, 3) // order_hint_bits_minus_1
+	w.put(0, 3)
+	w.put(1, 1) // high_bitdepth
+	w.put(0, 1) // mono_chrome
+	w.put(1, 1) // color_description_present_flag
+	w.put(9, 8)
+	w.put(16, 8)
+	w.put(9, 8)
+	w.put(0, 1) // color_range
+	w.put(0, 2) // chroma_sample_position
+	w.put(0, 1) // separate_uv_delta_q
+	w.put(1, 1) // film_grain_params_present
+	w.put(1, 1) // trailing bit
+	return w.buf
+}
+
+func testAV1OBU(obuType byte, payload []byte) []byte {
+	out := []byte{obuType<<3 | 0x02}
+	size := uint64(len(payload))
+	for {
+		b := byte(size & 0x7F)
+		size >>= 7
+		if size == 0 {
+			out = append(out, b)
+			break
+		}
+		out = append(out, b|0x80)
+	}
+	return append(out, payload...)
+}
+
+// testAV1HDRMetadata returns BT.2020 mastering display, content light level and HDR10+
+// metadata OBUs.
+func testAV1HDRMetadata() []byte {
+	mdcv := []byte{av1MetadataHDRMDCV}
+	for _, v := range []float64{0.708, 0.292, 0.170, 0.797, 0.131, 0.046, 0.3127, 0.3290} {
+		mdcv = binary.BigEndian.AppendUint16(mdcv, uint16(v*65536+0.5))
+	}
+	mdcv = binary.BigEndian.AppendUint32(mdcv, 1000*256)
+	mdcv = binary.BigEndian.AppendUint32(mdcv, 82)
+	cll := []byte{av1MetadataHDRCLL, 0x03, 0xE8, 0x01, 0x90}
+	t35 := append([]byte{av1MetadataITUTT35, 0xB5, 0x00, 0x3C, 0x00, 0x01, 0x04, 0x01}, make([]byte, 16)...)
+	out := testAV1OBU(av1OBUMetadata, mdcv)
+	out = append(out, testAV1OBU(av1OBUMetadata, cll)...)
+	return append(out, testAV1OBU(av1OBUMetadata, t35)...)
+}
+
+func TestParseAV1SequenceHeader(t *testing.T) {
+	seq, ok := parseAV1SequenceHeader(testAV1SequenceHeader())
+	if !ok {
+		t.Fatal("expected sequence header")
+	}
+	want := av1SequenceHeader{
+		level: 9, bitDepth: 10, subsamplingX: true, subsamplingY: true,
+		colorDescription: true, primaries: 9, transfer: 16, matrix: 9, hasColorRange: true,
+		filmGrain: true, width: 1920, height: 1080, frameRate: 24,
+	}
+	if seq != want {
+		t.Fatalf("got %+v\nwant %+v", seq, want)
+	}
+	fields := av1FormatFields(seq)
+	for _, f := range []Field{{"Format profile", "Main@L4.1"}, {"Format settings, Film grain", "Yes"}, {"Chroma subsampling", "4:2:0"}, {"Bit depth", "10 bits"}} {
+		if got := findField(fields, f.Name); got != f.Value {
+			t.Errorf("%s = %q, want %q", f.Name, got, f.Value)
+		}
+	}
+}
+
+func TestParseMP4AV1SampleEntry(t *testing.T) {
+	config := append([]byte{0x81, 0x09, 0x4C, 0x00}, testAV1OBU(av1OBUSequenceHeader, testAV1SequenceHeader())...)
+	var av1C bytes.Buffer
+	writeMP4Box(&av1C, "av1C", config)
+	entry := make([]byte, 86)
+	copy(entry[4:8], "av01")
+	binary.BigEndian.PutUint16(entry[32:34], 1920)
+	binary.BigEndian.PutUint16(entry[34:36], 1080)
+	entry = append(entry, av1C.Bytes()...)
+	binary.BigEndian.PutUint32(entry[0:4], uint32(len(entry)))
+
+	result := parseVisualSampleEntry(entry, "av01")
+	for _, f := range []Field{
+		{"Format/Info", "AOMedia Video 1"},
+		{"Format profile", "Main@L4.1"},
+		{"Color primaries", "BT.2020"},
+		{"Transfer characteristics", "PQ"},
+		{"Codec configuration box", "av1C"},
+	} {
+		if got := findField(result.Fields, f.Name); got != f.Value {
+			t.Errorf("%s = %q, want %q", f.Name, got, f.Value)
+		}
+	}
+	if result.JSON["colour_range"] != "Limited" {
+		t.Errorf("colour_range = %q", result.JSON["colour_range"])
+	}
+}
+
+// buildAV1Units returns count temporal units; the first carries the sequence header and
+// the HDR metadata.
+func buildAV1Units(count int) [][]byte {
+	units := make([][]byte, count)
+	for i := range units {
+		unit := testAV1OBU(av1OBUTemporalDelimiter, nil)
+		if i == 0 {
+			unit = append(unit, testAV1OBU(av1OBUSequenceHeader, testAV1SequenceHeader())...)
+			unit = append(unit, testAV1HDRMetadata()...)
+		}
+		units[i] = append(unit, testAV1OBU(6, make([]byte, 100))...) // OBU_FRAME
+	}
+	return units
+}
+
+func TestAnalyzeIVF(t *testing.T) {
+	units := buildAV1Units(48)
+	header := make([]byte, 32)
+	copy(header, "DKIF")
+	binary.LittleEndian.PutUint16(header[6:8], 32)
+	copy(header[8:12], "AV01")
+	binary.LittleEndian.PutUint16(header[12:14], 1920)
+	binary.LittleEndian.PutUint16(header[14:16], 1080)
+	binary.LittleEndian.PutUint32(header[16:20], 24)
+	binary.LittleEndian.PutUint32(header[20:24], 1)
+	binary.LittleEndian.PutUint32(header[24:28], uint32(len(units)))
+	data := header
+	for i, unit := range units {
+		frame := binary.LittleEndian.AppendUint32(nil, uint32(len(unit)))
+		frame = binary.LittleEndian.AppendUint64(frame, uint64(i))
+		data = append(data, append(frame, unit...)...)
+	}
+
+	report, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), "clip.ivf", defaultAnalyzeOptions())
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	text := RenderText([]Report{report})
+	for _, want := range []string{
+		"Format                                   : IVF\n",
+		"Format                                   : AV1\n",
+		"Format profile                           : Main@L4.1\n",
+		"HDR format                               : SMPTE ST 2094 App 4, Version 1, HDR10+ Profile A compatible\n",
+		"Format settings, Film grain              : Yes\n",
+		"Codec ID                                 : AV01\n",
+		"Duration                                 : 2 s 0 ms\n",
+		"Frame rate                               : 24.000 FPS\n",
+		"Mastering display color primaries        : BT.2020\n",
+		"Mastering display luminance              : min: 0.0050 cd/m2, max: 1000 cd/m2\n",
+		"Maximum Content Light Level              : 1000 cd/m2\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestAnalyzeAV1OBU(t *testing.T) {
+	units := buildAV1Units(24)
+	lowOverhead := bytes.Join(units, nil)
+	var annexB []byte
+	for _, unit := range units {
+		var frame []byte
+		forEachAV1OBU(unit, func(obuType byte, payload []byte) bool {
+			// Annex B OBUs carry obu_length instead of obu_size.
+			obu := []byte{obuType << 3}
+			obu = append(obu, payload...)
+			frame = append(frame, testLEB128(len(obu))...)
+			frame = append(frame, obu...)
+			return true
+		})
+		frame = append(testLEB128(len(frame)), frame...)
+		annexB = append(annexB, append(testLEB128(len(frame)), frame...)...)
+	}
+
+	for name, data := range map[string][]byte{"low overhead": lowOverhead, "Annex B": annexB} {
+		t.Run(name, func(t *testing.T) {
+			if got := DetectFormat(data, "clip.obu"); got != "AV1" {
+				t.Fatalf("DetectFormat = %q", got)
+			}
+			report, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), "clip.obu", defaultAnalyzeOptions())
+			if err != nil {
+				t.Fatalf("analyze: %v", err)
+			}
+			text := RenderText([]Report{report})
+			for _, want := range []string{
+				"Format profile                           : Main@L4.1\n",
+				"Duration                                 : 1 s 0 ms\n",
+				"Width                                    : 1 920 pixels\n",
+				"Maximum Frame-Average Light Level        : 400 cd/m2\n",
+			} {
+				if !strings.Contains(text, want) {
+					t.Errorf("missing %q in:\n%s", want, text)
+				}
+			}
+			if jsonOut := RenderJSON([]Report{report}); !strings.Contains(jsonOut, `"FrameCount":"24"`) {
+				t.Errorf("missing FrameCount in %s", jsonOut)
+			}
+		})
+	}
+}
+
+func testLEB128(value int) []byte {
+	var out []byte
+	for {
+		b := byte(value & 0x7F)
+		value >>= 7
+		if value == 0 {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func TestAnalyzeAV1AnnexBRejectsOversizedOBU(t *testing.T) {
+	// The temporal delimiter's obu_size of 16 overruns its obu_length of 4.
+	data := []byte{0x03, 0x02, 0x01, 0x10, 0x04, 0x03, 0x02, 0x0A, 0x7F}
+	if _, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), "clip.obu", defaultAnalyzeOptions()); err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+}
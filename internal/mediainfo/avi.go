@@ -1,6 +1,7 @@
 package mediainfo
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -97,11 +98,11 @@ func (s *vopScanner) feed(data []byte) {
 }
 
 func ParseAVI(file io.ReadSeeker, size int64) (ContainerInfo, []Stream, []Field, bool) {
-	info, streams, fields, _, ok := ParseAVIWithOptions(file, size, defaultAnalyzeOptions())
+	info, streams, fields, _, ok := ParseAVIWithOptions(context.Background(), file, size, defaultAnalyzeOptions())
 	return info, streams, fields, ok
 }
 
-func ParseAVIWithOptions(file io.ReadSeeker, size int64, opts AnalyzeOptions) (ContainerInfo, []Stream, []Field, string, bool) {
+func ParseAVIWithOptions(ctx context.Context, file io.ReadSeeker, size int64, opts AnalyzeOptions) (ContainerInfo, []Stream, []Field, string, bool) {
 	opts = normalizeAnalyzeOptions(opts)
 	if size < 12 {
 		return ContainerInfo{}, nil, nil, "", false
@@ -173,7 +174,7 @@ func ParseAVIWithOptions(file io.ReadSeeker, size int64, opts AnalyzeOptions) (C
 				}
 			case "movi":
 				if opts.ParseSpeed >= 1 {
-					parseAVIMovi(file, listDataStart, dataEnd, streams, &videoData, &audioData, &vopScan, 0, true)
+					parseAVIMovi(ctx, file, listDataStart, dataEnd, streams, &videoData, &audioData, &vopScan, 0, true)
 				} else {
 					moviStart = listDataStart
 					moviEnd = dataEnd
@@ -203,10 +204,10 @@ func ParseAVIWithOptions(file io.ReadSeeker, size int64, opts AnalyzeOptions) (C
 		if collectBytes {
 			maxScanBytes = 0
 		}
-		parseAVIMovi(file, moviStart, moviEnd, streams, &videoData, &audioData, &vopScan, maxScanBytes, collectBytes)
+		parseAVIMovi(ctx, file, moviStart, moviEnd, streams, &videoData, &audioData, &vopScan, maxScanBytes, collectBytes)
 	}
 
-	if len(streams) == 0 {
+	if len(streams) == 0 || ctx.Err() != nil {
 		return ContainerInfo{}, nil, nil, "", false
 	}
 
@@ -689,7 +690,7 @@ type aviIndexStats struct {
 	audioFirstBytes uint64
 }
 
-func parseAVIMovi(file io.ReadSeeker, start, end int64, streams []*aviStream, videoData *[]byte, audioData *[]byte, vopScan *vopScanner, maxScanBytes int64, collectBytes bool) {
+func parseAVIMovi(ctx context.Context, file io.ReadSeeker, start, end int64, streams []*aviStream, videoData *[]byte, audioData *[]byte, vopScan *vopScanner, maxScanBytes int64, collectBytes bool) {
 	const aviScanChunk = 256 << 10
 	scanBuf := make([]byte, aviScanChunk)
 	vopScanned := 0
@@ -699,6 +700,9 @@ func parseAVIMovi(file io.ReadSeeker, start, end int64, streams []*aviStream, vi
 		if maxScanBytes > 0 && offset-start >= maxScanBytes {
 			break
 		}
+		if ctx.Err() != nil {
+			break
+		}
 		var header [8]byte
 		if _, err := readAt(file, offset, header[:]); err != nil {
 			break
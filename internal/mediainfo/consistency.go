@@ -0,0 +1,270 @@
+package mediainfo
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ConsistencyReport is the cross-file comparison of a batch, such as the episodes of a
+// season pack. See CheckConsistency.
+type ConsistencyReport struct {
+	// Files is the number of reports, including those that failed to parse.
+	Files    int
+	Outliers []ConsistencyOutlier
+}
+
+// ConsistencyOutlier is one attribute of one file that differs from the other files.
+type ConsistencyOutlier struct {
+	Ref string
+	// Attribute is "Video codec", "Resolution", "HDR", "Encoder", "Encoder settings",
+	// "Audio tracks", "Audio languages" or "Subtitle languages", or "Error" for a file
+	// that failed to parse, with the error as Value.
+	Attribute string
+	Value     string
+	// Majority is the value most files have, or empty when no value is more common than
+	// all others; every file is then an outlier. It is always empty for "Error".
+	Majority string
+}
+
+// consistencyIgnoredSettings are encoder options expected to vary between the files of a
+// batch: threading and per-file rate control targets.
+var consistencyIgnoredSettings = map[string]bool{
+	"threads":           true,
+	"lookahead_threads": true,
+	"frame-threads":     true,
+	"pools":             true,
+	"numa-pools":        true,
+	"total-frames":      true,
+	"bitrate":           true,
+	"stats":             true,
+}
+
+type consistencyFile struct {
+	ref      string
+	err      error
+	values   map[string]string
+	settings map[string]string
+}
+
+var consistencyAttributes = []string{"Video codec", "Resolution", "HDR", "Encoder", "Audio tracks", "Audio languages", "Subtitle languages"}
+
+// CheckConsistency compares the video codec, resolution, HDR formats, encoder, x264 and
+// x265 encoder settings, audio track count and languages and subtitle languages of the
+// reports, and lists every file that differs from the majority. Encoder settings are
+// compared option by option, among the files that carry them; options that vary by
+// design, such as threads, are ignored. Reports that failed to parse are outliers with
+// the Attribute "Error"; the others are compared only when there are at least two.
+func CheckConsistency(reports []Report) ConsistencyReport {
+	var files, parsed []consistencyFile
+	for _, report := range reports {
+		if report.Err != nil {
+			files = append(files, consistencyFile{ref: report.Ref, err: report.Err})
+			continue
+		}
+		file := consistencyValues(report)
+		files = append(files, file)
+		parsed = append(parsed, file)
+	}
+	result := ConsistencyReport{Files: len(files)}
+
+	majorities := map[string]consistencyValue{}
+	for _, attribute := range consistencyAttributes {
+		values := make([]string, len(parsed))
+		for i, file := range parsed {
+			values[i] = file.values[attribute]
+		}
+		majorities[attribute] = consistencyMajority(values)
+	}
+	var keys []string
+	withSettings := 0
+	for _, file := range parsed {
+		if file.settings == nil {
+			continue
+		}
+		withSettings++
+		for key := range file.settings {
+			if !consistencyIgnoredSettings[key] && !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	slices.Sort(keys)
+	settingMajorities := map[string]consistencyValue{}
+	for _, key := range keys {
+		var values []string
+		for _, file := range parsed {
+			if file.settings != nil {
+				values = append(values, consistencySetting(file.settings, key))
+			}
+		}
+		settingMajorities[key] = consistencyMajority(values)
+	}
+
+	for _, file := range files {
+		if file.err != nil {
+			// The majority of a batch parses; a failed file is always an outlier.
+			result.Outliers = append(result.Outliers, ConsistencyOutlier{Ref: file.ref, Attribute: "Error", Value: file.err.Error()})
+			continue
+		}
+		if len(parsed) < 2 {
+			continue
+		}
+		for _, attribute := range consistencyAttributes {
+			if value, majority := file.values[attribute], majorities[attribute]; !majority.ok || value != majority.value {
+				outlier := ConsistencyOutlier{Ref: file.ref, Attribute: attribute, Value: consistencyDisplay(value)}
+				if majority.ok {
+					outlier.Majority = consistencyDisplay(majority.value)
+				}
+				result.Outliers = append(result.Outliers, outlier)
+			}
+		}
+		if file.settings == nil || withSettings < 2 {
+			continue
+		}
+		var differing, expected []string
+		for _, key := range keys {
+			if value, majority := consistencySetting(file.settings, key), settingMajorities[key]; !majority.ok || value != majority.value {
+				differing = append(differing, key+"="+value)
+				if majority.ok {
+					expected = append(expected, key+"="+majority.value)
+				}
+			}
+		}
+		if len(differing) > 0 {
+			result.Outliers = append(result.Outliers, ConsistencyOutlier{Ref: file.ref, Attribute: "Encoder settings", Value: strings.Join(differing, " / "), Majority: strings.Join(expected, " / ")})
+		}
+	}
+	return result
+}
+
+// consistencyValues collects the compared attributes of one report.
+func consistencyValues(report Report) consistencyFile {
+	attrs := ExtractReleaseAttributes(report)
+	file := consistencyFile{ref: report.Ref, values: map[string]string{
+		"Video codec":        attrs.VideoCodec,
+		"Resolution":         attrs.Resolution,
+		"HDR":                attrs.HDRString(),
+		"Encoder":            attrs.VideoEncoder,
+		"Audio tracks":       strconv.Itoa(len(attrs.Audio)),
+		"Audio languages":    strings.Join(attrs.AudioLanguages, ", "),
+		"Subtitle languages": strings.Join(attrs.SubtitleLanguages, ", "),
+	}}
+	if attrs.Resolution != "" {
+		file.values["Resolution"] = fmt.Sprintf("%s (%dx%d)", attrs.Resolution, attrs.Width, attrs.Height)
+	}
+	containerFormat := findField(report.General.Fields, "Format")
+	video := false
+	forEachStreamWithKindIndex(orderTracks(report.Streams), func(stream Stream, _, _, order int) {
+		if stream.Kind != StreamVideo || video {
+			return
+		}
+		video = true
+		fields := typedFieldsFromJSON(expandJSONExtra(buildJSONStreamFields(stream, order, 0, containerFormat)))
+		if settings := fields["Encoded_Library_Settings"]; settings != "" {
+			file.settings = parseX264Settings(settings)
+		}
+	})
+	return file
+}
+
+func consistencySetting(settings map[string]string, key string) string {
+	value, ok := settings[key]
+	switch {
+	case !ok:
+		return "unset"
+	case value == "":
+		return "set"
+	}
+	return value
+}
+
+type consistencyValue struct {
+	value string
+	ok    bool
+}
+
+// consistencyMajority returns the most common value; ok is false when several values tie.
+func consistencyMajority(values []string) consistencyValue {
+	counts := map[string]int{}
+	for _, value := range values {
+		counts[value]++
+	}
+	best, bestCount, tied := "", 0, false
+	for _, value := range values {
+		switch count := counts[value]; {
+		case count > bestCount:
+			best, bestCount, tied = value, count, false
+		case count == bestCount && value != best:
+			tied = true
+		}
+	}
+	return consistencyValue{value: best, ok: !tied}
+}
+
+func consistencyDisplay(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
+// RenderConsistency renders the outliers of a ConsistencyReport as a table, with file
+// names relative to the directory all files share.
+func RenderConsistency(report ConsistencyReport) string {
+	if len(report.Outliers) == 0 {
+		return fmt.Sprintf("No outliers in %d files.", report.Files)
+	}
+	refs := make([]string, len(report.Outliers))
+	for i, outlier := range report.Outliers {
+		refs[i] = filepath.ToSlash(outlier.Ref)
+	}
+	prefix := commonDirPrefix(refs)
+	rows := [][]string{{"File", "Attribute", "Value", "Majority"}}
+	for i, outlier := range report.Outliers {
+		majority := outlier.Majority
+		switch {
+		case outlier.Attribute == "Error":
+			majority = "none"
+		case majority == "":
+			majority = "no majority"
+		}
+		rows = append(rows, []string{strings.TrimPrefix(refs[i], prefix), outlier.Attribute, outlier.Value, majority})
+	}
+	widths := make([]int, 4)
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	var b strings.Builder
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(row)-1 {
+				b.WriteString(cell + "\n")
+				continue
+			}
+			b.WriteString(cell + strings.Repeat(" ", widths[i]-len(cell)+2))
+		}
+	}
+	files := map[string]bool{}
+	for _, outlier := range report.Outliers {
+		files[outlier.Ref] = true
+	}
+	fmt.Fprintf(&b, "%d of %d files differ from the majority.", len(files), report.Files)
+	return b.String()
+}
+
+// commonDirPrefix returns the longest directory prefix, with its trailing slash, that all
+// paths share.
+func commonDirPrefix(paths []string) string {
+	prefix := paths[0][:strings.LastIndexByte(paths[0], '/')+1]
+	for _, path := range paths[1:] {
+		for !strings.HasPrefix(path, prefix) {
+			prefix = prefix[:strings.LastIndexByte(strings.TrimSuffix(prefix, "/"), '/')+1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,68 @@
+package mediainfo
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckConsistency(t *testing.T) {
+	report, err := AnalyzeFile("samples/sample.mkv")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	episode := func(ref string, edit func(*Stream) bool) Report {
+		copied := report
+		copied.Ref = ref
+		copied.Streams = nil
+		for _, stream := range report.Streams {
+			stream.Fields = append([]Field(nil), stream.Fields...)
+			if edit == nil || edit(&stream) {
+				copied.Streams = append(copied.Streams, stream)
+			}
+		}
+		return copied
+	}
+	reports := []Report{
+		episode("/pack/S01E01.mkv", nil),
+		episode("/pack/S01E02.mkv", func(stream *Stream) bool {
+			for i, field := range stream.Fields {
+				if field.Name == "Encoding settings" {
+					stream.Fields[i].Value = strings.Replace(field.Value, "crf=28.0", "crf=20.0", 1)
+				}
+			}
+			return true
+		}),
+		episode("/pack/S01E03.mkv", func(stream *Stream) bool { return stream.Kind != StreamAudio }),
+		{Ref: "/pack/S01E04.mkv", Err: os.ErrNotExist},
+	}
+
+	got := CheckConsistency(reports)
+	want := ConsistencyReport{Files: 4, Outliers: []ConsistencyOutlier{
+		{Ref: "/pack/S01E02.mkv", Attribute: "Encoder settings", Value: "crf=20.0", Majority: "crf=28.0"},
+		{Ref: "/pack/S01E03.mkv", Attribute: "Audio tracks", Value: "0", Majority: "1"},
+		{Ref: "/pack/S01E04.mkv", Attribute: "Error", Value: "file does not exist"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("consistency:\n got %+v\nwant %+v", got, want)
+	}
+
+	table := RenderConsistency(got)
+	wantTable := "" +
+		"File        Attribute         Value                Majority\n" +
+		"S01E02.mkv  Encoder settings  crf=20.0             crf=28.0\n" +
+		"S01E03.mkv  Audio tracks      0                    1\n" +
+		"S01E04.mkv  Error             file does not exist  none\n" +
+		"3 of 4 files differ from the majority."
+	if table != wantTable {
+		t.Fatalf("table:\n%s\nwant\n%s", table, wantTable)
+	}
+	if got := RenderConsistency(CheckConsistency([]Report{reports[0], reports[0]})); got != "No outliers in 2 files." {
+		t.Fatalf("identical files: %q", got)
+	}
+	lone := CheckConsistency([]Report{reports[0], reports[3]})
+	if len(lone.Outliers) != 1 || lone.Outliers[0].Attribute != "Error" || lone.Files != 2 {
+		t.Fatalf("failed file next to a single parsed one: %+v", lone)
+	}
+}
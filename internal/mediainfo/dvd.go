@@ -1,6 +1,7 @@
 package mediainfo
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -67,7 +68,7 @@ type dvdMenuLists struct {
 	subPanScan string
 }
 
-func parseDVDVideo(path string, file *os.File, size int64, opts AnalyzeOptions) (dvdInfo, bool) {
+func parseDVDVideo(ctx context.Context, path string, file io.ReadSeeker, size int64, opts AnalyzeOptions) (dvdInfo, bool) {
 	if _, err := file.Seek(0, io.SeekStart); err != nil {
 		return dvdInfo{}, false
 	}
@@ -145,7 +146,7 @@ func parseDVDVideo(path string, file *os.File, size int64, opts AnalyzeOptions)
 			if ifoInfo, err := os.Stat(path); err == nil {
 				info.FileSize += ifoInfo.Size()
 			}
-			if parsedInfo, parsedStreams, ok := ParseMPEGPSFiles(vobPaths, info.FileSize, mpegPSOptions{dvdExtras: true, dvdParsing: true, parseSpeed: opts.ParseSpeed}); ok {
+			if parsedInfo, parsedStreams, ok := ParseMPEGPSFiles(ctx, vobPaths, info.FileSize, mpegPSOptions{dvdExtras: true, dvdParsing: true, parseSpeed: opts.ParseSpeed}); ok {
 				streams = mergeDVDTitleSetStreams(parsedStreams, dvdTitleSetSource(base))
 				titleSetParsed = len(streams) > 0
 				if parsedInfo.DurationSeconds > 0 {
@@ -414,7 +415,7 @@ func parseDVDVideo(path string, file *os.File, size int64, opts AnalyzeOptions)
 	return info, true
 }
 
-func readSizedFile(file *os.File, size int64) ([]byte, error) {
+func readSizedFile(file io.Reader, size int64) ([]byte, error) {
 	if size <= 0 {
 		return io.ReadAll(file)
 	}
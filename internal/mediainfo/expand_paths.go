@@ -0,0 +1,152 @@
+package mediainfo
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type pathEntry struct {
+	path string
+	err  error
+}
+
+type pathFilter struct {
+	include []string
+	exclude []string
+}
+
+func newPathFilter(opts AnalyzeOptions) (pathFilter, error) {
+	for _, pattern := range append(append([]string(nil), opts.Include...), opts.Exclude...) {
+		if _, err := path.Match(strings.TrimSuffix(pattern, "/"), ""); err != nil {
+			return pathFilter{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return pathFilter{include: opts.Include, exclude: opts.Exclude}, nil
+}
+
+func (f pathFilter) skipDir(rel string) bool {
+	return matchPathPatterns(f.exclude, rel, true)
+}
+
+func (f pathFilter) skipFile(rel string) bool {
+	if matchPathPatterns(f.exclude, rel, false) {
+		return true
+	}
+	return len(f.include) > 0 && !matchPathPatterns(f.include, rel, false)
+}
+
+func matchPathPatterns(patterns []string, rel string, isDir bool) bool {
+	base := path.Base(rel)
+	for _, pattern := range patterns {
+		dirOnly := strings.HasSuffix(pattern, "/")
+		if dirOnly && !isDir {
+			continue
+		}
+		pattern = strings.TrimSuffix(pattern, "/")
+		if ok, _ := path.Match(pattern, base); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// expandPaths turns the path arguments into the list of files to analyze. Files are kept
+// as given; directories are listed in lexical order (depth-first when recursive). Paths
+// that cannot be read become entries carrying the error.
+func expandPaths(paths []string, opts AnalyzeOptions) ([]pathEntry, error) {
+	filter, err := newPathFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+	maxDepth := 1
+	if opts.Recursive {
+		maxDepth = opts.MaxDepth
+	}
+	expanded := make([]pathEntry, 0, len(paths))
+	for _, root := range paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			expanded = append(expanded, pathEntry{path: root, err: err})
+			continue
+		}
+		if !info.IsDir() {
+			expanded = append(expanded, pathEntry{path: root})
+			continue
+		}
+		w := dirWalker{filter: filter, symlinks: opts.Symlinks, maxDepth: maxDepth, visited: map[string]bool{}}
+		w.markVisited(root)
+		expanded = w.walk(expanded, root, "", 1)
+	}
+	return expanded, nil
+}
+
+type dirWalker struct {
+	filter   pathFilter
+	symlinks SymlinkPolicy
+	maxDepth int
+	// visited holds resolved directories, so followed links cannot loop.
+	visited map[string]bool
+}
+
+func (w *dirWalker) markVisited(dir string) bool {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		real = dir
+	}
+	if abs, err := filepath.Abs(real); err == nil {
+		real = abs
+	}
+	if w.visited[real] {
+		return false
+	}
+	w.visited[real] = true
+	return true
+}
+
+func (w *dirWalker) walk(out []pathEntry, dir, rel string, depth int) []pathEntry {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return append(out, pathEntry{path: dir, err: err})
+	}
+	for _, entry := range entries {
+		full := filepath.Join(dir, entry.Name())
+		entryRel := path.Join(rel, entry.Name())
+		isDir := entry.IsDir()
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if w.symlinks == SymlinkSkip {
+				continue
+			}
+			info, err := os.Stat(full)
+			if err != nil {
+				// Dangling link: nothing to analyze.
+				continue
+			}
+			isDir = info.IsDir()
+			if isDir && w.symlinks != SymlinkFollow {
+				continue
+			}
+		}
+		if isDir {
+			if w.maxDepth > 0 && depth >= w.maxDepth {
+				continue
+			}
+			if w.filter.skipDir(entryRel) || !w.markVisited(full) {
+				continue
+			}
+			out = w.walk(out, full, entryRel, depth+1)
+			continue
+		}
+		if w.filter.skipFile(entryRel) {
+			continue
+		}
+		out = append(out, pathEntry{path: full})
+	}
+	return out
+}
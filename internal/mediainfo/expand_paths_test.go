@@ -0,0 +1,146 @@
+package mediainfo
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func makeReleaseTree(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, name := range []string{
+		"release.nfo",
+		"release.sfv",
+		"Season 01/e01.mkv",
+		"Season 01/e02.mkv",
+		"Season 01/Subs/e01.srt",
+		"Sample/sample.mkv",
+		"BDMV/STREAM/00000.m2ts",
+		"a.mkv",
+	} {
+		full := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(full, []byte("x"), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	return root
+}
+
+func relEntries(t *testing.T, root string, entries []pathEntry) []string {
+	t.Helper()
+	out := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.err != nil {
+			t.Fatalf("unexpected entry error: %v", entry.err)
+		}
+		rel, err := filepath.Rel(root, entry.path)
+		if err != nil {
+			t.Fatalf("rel: %v", err)
+		}
+		out = append(out, filepath.ToSlash(rel))
+	}
+	return out
+}
+
+func TestExpandPathsDefaultListsTopLevelFiles(t *testing.T) {
+	root := makeReleaseTree(t)
+	entries, err := expandPaths([]string{root}, AnalyzeOptions{})
+	if err != nil {
+		t.Fatalf("expand: %v", err)
+	}
+	got := relEntries(t, root, entries)
+	want := []string{"a.mkv", "release.nfo", "release.sfv"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestExpandPathsRecursiveWithFilters(t *testing.T) {
+	root := makeReleaseTree(t)
+	entries, err := expandPaths([]string{root}, AnalyzeOptions{
+		Recursive: true,
+		Exclude:   []string{"*.nfo", "*.sfv", "Sample/"},
+	})
+	if err != nil {
+		t.Fatalf("expand: %v", err)
+	}
+	got := relEntries(t, root, entries)
+	want := []string{"BDMV/STREAM/00000.m2ts", "Season 01/Subs/e01.srt", "Season 01/e01.mkv", "Season 01/e02.mkv", "a.mkv"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	entries, err = expandPaths([]string{root}, AnalyzeOptions{Recursive: true, Include: []string{"*.mkv"}, MaxDepth: 2})
+	if err != nil {
+		t.Fatalf("expand: %v", err)
+	}
+	got = relEntries(t, root, entries)
+	want = []string{"Sample/sample.mkv", "Season 01/e01.mkv", "Season 01/e02.mkv", "a.mkv"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestExpandPathsRelativePattern(t *testing.T) {
+	root := makeReleaseTree(t)
+	entries, err := expandPaths([]string{root}, AnalyzeOptions{Recursive: true, Include: []string{"BDMV/STREAM/*.m2ts"}})
+	if err != nil {
+		t.Fatalf("expand: %v", err)
+	}
+	got := relEntries(t, root, entries)
+	if !slices.Equal(got, []string{"BDMV/STREAM/00000.m2ts"}) {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestExpandPathsInvalidPattern(t *testing.T) {
+	if _, err := expandPaths([]string{"."}, AnalyzeOptions{Exclude: []string{"["}}); err == nil {
+		t.Fatalf("expected invalid pattern error")
+	}
+}
+
+func TestExpandPathsSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on windows")
+	}
+	root := makeReleaseTree(t)
+	if err := os.Symlink(filepath.Join(root, "Season 01"), filepath.Join(root, "linked")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(root, "a.mkv"), filepath.Join(root, "b.mkv")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	// A link back to the root must not loop.
+	if err := os.Symlink(root, filepath.Join(root, "Season 01", "loop")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	cases := []struct {
+		policy SymlinkPolicy
+		want   []string
+	}{
+		{SymlinkFiles, []string{"a.mkv", "b.mkv"}},
+		{SymlinkSkip, []string{"a.mkv"}},
+		{SymlinkFollow, []string{"a.mkv", "b.mkv", "linked/e01.mkv", "linked/e02.mkv"}},
+	}
+	for _, tc := range cases {
+		entries, err := expandPaths([]string{root}, AnalyzeOptions{
+			Recursive: true,
+			Symlinks:  tc.policy,
+			Include:   []string{"*.mkv"},
+			Exclude:   []string{"Season 01/", "Sample/"},
+		})
+		if err != nil {
+			t.Fatalf("expand: %v", err)
+		}
+		if got := relEntries(t, root, entries); !slices.Equal(got, tc.want) {
+			t.Fatalf("policy %d: got %v, want %v", tc.policy, got, tc.want)
+		}
+	}
+}
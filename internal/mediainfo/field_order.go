@@ -41,6 +41,7 @@ var streamFieldOrder = map[string]int{
 	"Format settings, GMC":              10,
 	"Format settings, Matrix":           11,
 	"Format settings, GOP":              12,
+	"Format settings, Film grain":       12,
 	"Format settings, CABAC":            13,
 	"Format settings, Reference frames": 14,
 	"Format settings, Slice count":      14,
@@ -0,0 +1,416 @@
+package mediainfo
+
+import (
+	"strings"
+)
+
+// FieldInfo describes a field the parsers can emit.
+type FieldInfo struct {
+	// Name is the internal name, used as JSON/XML key and by templates.
+	Name string
+	// Display is the label of the field in text, HTML and CSV output; empty when only
+	// JSON and XML carry the field.
+	Display string
+	// Unit is the unit of the JSON value, if any.
+	Unit string
+	// Description says what the field holds.
+	Description string
+}
+
+type kindSet uint8
+
+const (
+	inGeneral kindSet = 1 << iota
+	inVideo
+	inAudio
+	inText
+	inImage
+	inMenu
+
+	inStreams = inVideo | inAudio | inText | inImage | inMenu
+	inAll     = inGeneral | inStreams
+	inAV      = inVideo | inAudio
+	inAVT     = inVideo | inAudio | inText
+)
+
+var kindSets = map[StreamKind]kindSet{
+	StreamGeneral: inGeneral,
+	StreamVideo:   inVideo,
+	StreamAudio:   inAudio,
+	StreamText:    inText,
+	StreamImage:   inImage,
+	StreamMenu:    inMenu,
+}
+
+type registeredField struct {
+	kinds kindSet
+	FieldInfo
+}
+
+// fieldRegistry declares every field the parsers can emit, in --Info-Parameters order.
+// JSON and CSV output are tested against it: a field missing here is a bug.
+var fieldRegistry = []registeredField{
+	// Stream description.
+	{inAll, FieldInfo{"Count", "Count", "", "Number of fields of the stream (--full)"}},
+	{inAll, FieldInfo{"StreamCount", "Count of stream of this kind", "", "Number of streams of this kind"}},
+	{inAll, FieldInfo{"StreamKind", "Kind of stream", "", "Kind of the stream (General, Video, Audio, Text, Image, Menu)"}},
+	{inAll, FieldInfo{"StreamKindID", "Stream identifier", "", "Position of the stream among the streams of its kind, base 0"}},
+	{inStreams, FieldInfo{"StreamKindPos", "", "", "Position of the stream among the streams of its kind, base 1, when there are several"}},
+	{inStreams, FieldInfo{"StreamOrder", "", "", "Order of the stream in the container"}},
+	{inVideo | inAudio | inText | inMenu, FieldInfo{"FirstPacketOrder", "", "", "Order of the first packet of the stream in the file"}},
+	{inAll, FieldInfo{"ID", "ID", "", "Identifier of the stream in the container (track number, PID...)"}},
+	{inVideo | inAudio | inText | inMenu, FieldInfo{"MenuID", "Menu ID", "", "Program the stream belongs to"}},
+	{inGeneral | inAVT, FieldInfo{"UniqueID", "Unique ID", "", "Unique identifier of the file or track"}},
+
+	// Stream counts.
+	{inGeneral, FieldInfo{"VideoCount", "Count of video streams", "", "Number of video streams"}},
+	{inGeneral, FieldInfo{"AudioCount", "Count of audio streams", "", "Number of audio streams"}},
+	{inGeneral, FieldInfo{"TextCount", "Count of text streams", "", "Number of text streams"}},
+	{inGeneral, FieldInfo{"ImageCount", "Count of image streams", "", "Number of image streams"}},
+	{inGeneral, FieldInfo{"MenuCount", "Count of menu streams", "", "Number of menu streams"}},
+	{inGeneral, FieldInfo{"Video_Format_List", "Video_Format_List", "", "Formats of the video streams"}},
+	{inGeneral, FieldInfo{"Audio_Format_List", "Audio_Format_List", "", "Formats of the audio streams"}},
+	{inGeneral, FieldInfo{"Text_Format_List", "Text_Format_List", "", "Formats of the text streams"}},
+	{inGeneral, FieldInfo{"Image_Format_List", "Image_Format_List", "", "Formats of the image streams"}},
+	{inGeneral, FieldInfo{"Menu_Format_List", "Menu_Format_List", "", "Formats of the menu streams"}},
+
+	// File.
+	{inGeneral, FieldInfo{"CompleteName", "Complete name", "", "Path of the file as given"}},
+	{inGeneral, FieldInfo{"FolderName", "Folder name", "", "Directory of the file"}},
+	{inGeneral, FieldInfo{"FileNameExtension", "File name extension", "", "File name with its extension"}},
+	{inGeneral, FieldInfo{"FileName", "File name", "", "File name without its extension"}},
+	{inGeneral, FieldInfo{"FileExtension", "File extension", "", "File extension"}},
+	{inGeneral, FieldInfo{"CompleteName_Last", "CompleteName_Last", "", "Path of the last file of a sequence of continuous files"}},
+	{inGeneral, FieldInfo{"FileExtension_Invalid", "FileExtension_Invalid", "", "Extensions usually used for this format, when the file has another one"}},
+	{inGeneral, FieldInfo{"Error", "Error", "", "Why the file could not be analyzed"}},
+
+	// Format.
+	{inAll, FieldInfo{"Format", "Format", "", "Format used"}},
+	{inAll, FieldInfo{"Format_Info", "Format/Info", "", "Long name of the format"}},
+	{inAV, FieldInfo{"Format_Commercial_IfAny", "Commercial name", "", "Commercial name of the format, if any"}},
+	{inGeneral | inAV, FieldInfo{"Format_Version", "Format version", "", "Version of the format"}},
+	{inGeneral | inAV, FieldInfo{"Format_Profile", "Format profile", "", "Profile of the format"}},
+	{inVideo, FieldInfo{"Format_Level", "Format level", "", "Level of the format"}},
+	{inVideo, FieldInfo{"Format_Tier", "Format tier", "", "Tier of the format (HEVC)"}},
+	{inGeneral | inAV, FieldInfo{"Format_Settings", "Format settings", "", "Settings of the format"}},
+	{inAudio, FieldInfo{"Format_AdditionalFeatures", "", "", "Features in addition to the profile (e.g. JOC, LC)"}},
+	{inImage, FieldInfo{"Format_Compression", "", "", "Compression of the image format"}},
+	{inVideo, FieldInfo{"Format_Settings_CABAC", "Format settings, CABAC", "", "Whether CABAC entropy coding is used (AVC)"}},
+	{inVideo, FieldInfo{"Format_Settings_RefFrames", "Format settings, Reference frames", "", "Number of reference frames"}},
+	{inVideo, FieldInfo{"Format_Settings_BVOP", "Format settings, BVOP", "", "Whether B-VOPs are used (MPEG-4 Visual)"}},
+	{inVideo, FieldInfo{"Format_Settings_QPel", "Format settings, QPel", "", "Whether quarter pixel motion is used (MPEG-4 Visual)"}},
+	{inVideo, FieldInfo{"Format_Settings_GMC", "Format settings, GMC", "", "Number of global motion compensation warp points (MPEG-4 Visual)"}},
+	{inVideo, FieldInfo{"Format_Settings_Matrix", "Format settings, Matrix", "", "Quantization matrix (Default or Custom)"}},
+	{inVideo, FieldInfo{"Format_Settings_Matrix_Data", "", "", "Quantization matrix values"}},
+	{inVideo, FieldInfo{"Format_Settings_GOP", "Format settings, GOP", "", "GOP structure (M=, N=)"}},
+	{inVideo, FieldInfo{"Format_Settings_FilmGrain", "Format settings, Film grain", "", "Whether film grain synthesis parameters are present (AV1)"}},
+	{inVideo, FieldInfo{"Format_Settings_PictureStructure", "Format settings, Picture structure", "", "Picture structure (Frame or Field)"}},
+	{inVideo, FieldInfo{"Format_Settings_SliceCount", "Format settings, Slice count", "", "Number of slices per frame"}},
+	{inAudio, FieldInfo{"Format_Settings_Endianness", "Format settings, Endianness", "", "Byte order of the samples"}},
+	{inAudio, FieldInfo{"Format_Settings_Sign", "Format settings, Sign", "", "Whether the samples are signed"}},
+	{inAudio, FieldInfo{"Format_Settings_SBR", "", "", "Whether spectral band replication is used (AAC)"}},
+	{inAudio, FieldInfo{"Format_Settings_Mode", "", "", "Channel mode (MPEG Audio)"}},
+	{inAudio, FieldInfo{"Format_Settings_ModeExtension", "", "", "Channel mode extension (MPEG Audio)"}},
+	{inGeneral | inAVT, FieldInfo{"CodecID", "Codec ID", "", "Codec identifier as stored in the container"}},
+	{inAVT, FieldInfo{"CodecID_Info", "Codec ID/Info", "", "Description of the codec identifier"}},
+	{inGeneral, FieldInfo{"CodecID_Compatible", "", "", "Compatible brands of the container"}},
+	{inAVT, FieldInfo{"CodecConfigurationBox", "Codec configuration box", "", "Codec configuration boxes of the sample entry (MP4)"}},
+	{inAVT, FieldInfo{"MuxingMode", "Muxing mode", "", "How the stream is muxed in the container"}},
+	{inAVT, FieldInfo{"MuxingMode_MoreInfo", "Muxing mode, more info", "", "More information about the muxing mode"}},
+
+	// Sizes and durations.
+	{inGeneral, FieldInfo{"FileSize", "File size", "byte", "Size of the file"}},
+	{inAll, FieldInfo{"Duration", "Duration", "s", "Play time of the stream"}},
+	{inAV, FieldInfo{"Source_Duration", "Source duration", "s", "Play time of the source stream, before the container's edit list"}},
+	{inAudio, FieldInfo{"Source_Duration_LastFrame", "Source_Duration_LastFrame", "s", "Difference between the source duration and the last frame"}},
+	{inText, FieldInfo{"Duration_Start", "Start time", "s", "Time of the first caption event"}},
+	{inText, FieldInfo{"Duration_End", "End time", "s", "Time of the last caption event"}},
+	{inText, FieldInfo{"Duration_Start_Command", "", "s", "Time of the first caption command"}},
+	{inText, FieldInfo{"Duration_End_Command", "", "s", "Time of the last caption command"}},
+	{inText, FieldInfo{"Duration_Start2End", "Duration of the visible content", "s", "Time between the first and the last caption event"}},
+	{inText, FieldInfo{"FirstDisplay_Delay_Frames", "Count of frames before first event", "", "Frames before the first caption event"}},
+	{inText, FieldInfo{"FirstDisplay_Type", "Type of the first event", "", "Kind of the first caption event (PopOn, RollUp...)"}},
+	{inGeneral, FieldInfo{"OverallBitRate_Mode", "Overall bit rate mode", "", "Bit rate mode of the file (CBR, VBR)"}},
+	{inGeneral, FieldInfo{"OverallBitRate", "Overall bit rate", "bps", "Bit rate of all streams together"}},
+	{inGeneral, FieldInfo{"OverallBitRate_Maximum", "", "bps", "Maximum bit rate of all streams together"}},
+	{inGeneral, FieldInfo{"OverallBitRate_Precision_Min", "", "bps", "Lowest possible overall bit rate, when it is not exact"}},
+	{inGeneral, FieldInfo{"OverallBitRate_Precision_Max", "", "bps", "Highest possible overall bit rate, when it is not exact"}},
+	{inAVT, FieldInfo{"BitRate_Mode", "Bit rate mode", "", "Bit rate mode (CBR, VBR)"}},
+	{inAVT, FieldInfo{"BitRate", "Bit rate", "bps", "Bit rate of the stream"}},
+	{inAV, FieldInfo{"BitRate_Nominal", "Nominal bit rate", "bps", "Nominal bit rate of the stream"}},
+	{inAV, FieldInfo{"BitRate_Maximum", "Maximum bit rate", "bps", "Maximum bit rate of the stream"}},
+	{inAV, FieldInfo{"BitRate_Encoded", "", "bps", "Bit rate of the stream as encoded, before container overhead"}},
+	{inVideo | inAudio, FieldInfo{"BufferSize", "", "byte", "Decoder buffer size"}},
+
+	// Frames.
+	{inVideo, FieldInfo{"FrameRate_Mode", "Frame rate mode", "", "Frame rate mode (CFR, VFR)"}},
+	{inVideo, FieldInfo{"FrameRate_Mode_Original", "", "", "Frame rate mode of the bitstream, when the container differs"}},
+	{inAll, FieldInfo{"FrameRate", "Frame rate", "fps", "Frames per second"}},
+	{inVideo | inMenu, FieldInfo{"FrameRate_Num", "", "", "Numerator of the frame rate"}},
+	{inVideo | inMenu, FieldInfo{"FrameRate_Den", "", "", "Denominator of the frame rate"}},
+	{inAll, FieldInfo{"FrameCount", "", "", "Number of frames"}},
+	{inAV, FieldInfo{"Source_FrameCount", "", "", "Number of frames of the source stream"}},
+
+	// Picture.
+	{inVideo | inImage, FieldInfo{"Width", "Width", "pixel", "Width of the picture"}},
+	{inVideo | inImage, FieldInfo{"Height", "Height", "pixel", "Height of the picture"}},
+	{inVideo, FieldInfo{"Sampled_Width", "", "pixel", "Width of the decoded picture"}},
+	{inVideo, FieldInfo{"Sampled_Height", "", "pixel", "Height of the decoded picture"}},
+	{inVideo, FieldInfo{"Stored_Width", "", "pixel", "Width of the coded picture, padding included"}},
+	{inVideo, FieldInfo{"Stored_Height", "", "pixel", "Height of the coded picture, padding included"}},
+	{inVideo, FieldInfo{"PixelAspectRatio", "", "", "Pixel aspect ratio"}},
+	{inVideo, FieldInfo{"DisplayAspectRatio", "Display aspect ratio", "", "Display aspect ratio"}},
+	{inVideo, FieldInfo{"DisplayAspectRatio_Original", "", "", "Display aspect ratio of the bitstream, when the container differs"}},
+	{inVideo, FieldInfo{"Rotation", "", "degree", "Rotation of the picture"}},
+	{inVideo | inImage, FieldInfo{"ColorSpace", "Color space", "", "Color space (YUV, RGB)"}},
+	{inVideo | inImage, FieldInfo{"ChromaSubsampling", "Chroma subsampling", "", "Chroma subsampling (4:2:0...)"}},
+	{inVideo, FieldInfo{"ChromaSubsampling_Position", "Chroma subsampling position", "", "Position of the chroma samples"}},
+	{inAVT | inImage, FieldInfo{"BitDepth", "Bit depth", "bit", "Bits per sample"}},
+	{inVideo, FieldInfo{"ScanType", "Scan type", "", "Progressive or interlaced"}},
+	{inVideo, FieldInfo{"ScanOrder", "Scan order", "", "Field order of interlaced content"}},
+	{inVideo, FieldInfo{"Standard", "Standard", "", "Analog video standard (PAL, NTSC)"}},
+	{inVideo, FieldInfo{"Bits-(Pixel*Frame)", "Bits/(Pixel*Frame)", "", "Bits per pixel and frame"}},
+	{inVideo, FieldInfo{"Gop_OpenClosed", "GOP, Open/Closed", "", "Whether GOPs are open or closed"}},
+	{inVideo, FieldInfo{"Gop_OpenClosed_FirstFrame", "GOP, Open/Closed of first frame", "", "Whether the first GOP is open or closed"}},
+	{inVideo, FieldInfo{"TimeCode_FirstFrame", "Time code of first frame", "", "Time code of the first frame (HH:MM:SS:FF)"}},
+	{inVideo, FieldInfo{"TimeCode_Source", "Time code source", "", "Where the time code comes from"}},
+	{inVideo, FieldInfo{"intra_dc_precision", "", "", "Intra DC precision (MPEG Video)"}},
+
+	// Color description.
+	{inVideo, FieldInfo{"colour_description_present", "", "", "Whether the bitstream describes its colors"}},
+	{inVideo, FieldInfo{"colour_description_present_Source", "", "", "Where the color description comes from"}},
+	{inVideo, FieldInfo{"colour_range", "Color range", "", "Limited or Full range"}},
+	{inVideo, FieldInfo{"colour_range_Source", "", "", "Where the color range comes from"}},
+	{inVideo, FieldInfo{"colour_primaries", "Color primaries", "", "Color primaries (BT.709...)"}},
+	{inVideo, FieldInfo{"colour_primaries_Source", "", "", "Where the color primaries come from"}},
+	{inVideo, FieldInfo{"transfer_characteristics", "Transfer characteristics", "", "Transfer characteristics (BT.709, PQ, HLG...)"}},
+	{inVideo, FieldInfo{"transfer_characteristics_Source", "", "", "Where the transfer characteristics come from"}},
+	{inVideo, FieldInfo{"matrix_coefficients", "Matrix coefficients", "", "Matrix coefficients (BT.709...)"}},
+	{inVideo, FieldInfo{"matrix_coefficients_Source", "", "", "Where the matrix coefficients come from"}},
+	{inVideo, FieldInfo{"HDR_Format", "HDR format", "", "HDR format (Dolby Vision, SMPTE ST 2086...)"}},
+	{inVideo, FieldInfo{"HDR_Format_Version", "", "", "Version of the HDR format"}},
+	{inVideo, FieldInfo{"HDR_Format_Profile", "", "", "Profile of the HDR format"}},
+	{inVideo, FieldInfo{"HDR_Format_Level", "", "", "Level of the HDR format"}},
+	{inVideo, FieldInfo{"HDR_Format_Settings", "", "", "Settings of the HDR format (Dolby Vision layers)"}},
+	{inVideo, FieldInfo{"HDR_Format_Compatibility", "", "", "Formats an HDR decoder can fall back to"}},
+	{inVideo, FieldInfo{"MasteringDisplay_ColorPrimaries", "Mastering display color primaries", "", "Color primaries of the mastering display"}},
+	{inVideo, FieldInfo{"MasteringDisplay_ColorPrimaries_Source", "", "", "Where the mastering display color primaries come from"}},
+	{inVideo, FieldInfo{"MasteringDisplay_Luminance", "Mastering display luminance", "cd/m2", "Luminance range of the mastering display"}},
+	{inVideo, FieldInfo{"MasteringDisplay_Luminance_Source", "", "", "Where the mastering display luminance comes from"}},
+	{inVideo, FieldInfo{"MaxCLL", "Maximum Content Light Level", "cd/m2", "Maximum content light level"}},
+	{inVideo, FieldInfo{"MaxCLL_Source", "", "", "Where the maximum content light level comes from"}},
+	{inVideo, FieldInfo{"MaxFALL", "Maximum Frame-Average Light Level", "cd/m2", "Maximum frame-average light level"}},
+	{inVideo, FieldInfo{"MaxFALL_Source", "", "", "Where the maximum frame-average light level comes from"}},
+
+	// Timing.
+	{inStreams, FieldInfo{"Delay", "Delay", "s", "Delay of the first frame from the start of the file"}},
+	{inVideo, FieldInfo{"Delay_Settings", "", "", "Settings of the delay (drop frame...)"}},
+	{inVideo, FieldInfo{"Delay_DropFrame", "", "", "Whether the delay time code is drop frame"}},
+	{inAVT, FieldInfo{"Delay_Source", "", "", "Where the delay comes from"}},
+	{inVideo, FieldInfo{"Delay_Original", "", "s", "Delay found in the bitstream, when the container differs"}},
+	{inVideo, FieldInfo{"Delay_Original_DropFrame", "", "", "Whether the bitstream delay time code is drop frame"}},
+	{inVideo, FieldInfo{"Delay_Original_Source", "", "", "Where the bitstream delay comes from"}},
+	{inAudio | inText, FieldInfo{"Video_Delay", "Delay relative to video", "s", "Delay relative to the first video stream"}},
+	{inAudio, FieldInfo{"Source_Delay", "", "ms", "Delay of the source stream, before the container's edit list"}},
+	{inAudio, FieldInfo{"Source_Delay_Source", "", "", "Where the source delay comes from"}},
+
+	// Audio.
+	{inAudio, FieldInfo{"Channels", "Channel(s)", "channel", "Number of channels"}},
+	{inAudio, FieldInfo{"ChannelPositions", "", "", "Position of the channels"}},
+	{inAudio, FieldInfo{"ChannelLayout", "Channel layout", "", "Layout of the channels, in order"}},
+	{inAudio, FieldInfo{"SamplesPerFrame", "", "", "Samples per frame"}},
+	{inAudio, FieldInfo{"SamplingRate", "Sampling rate", "Hz", "Samples per second"}},
+	{inAudio, FieldInfo{"SamplingCount", "", "", "Number of samples"}},
+	{inAV, FieldInfo{"Compression_Mode", "Compression mode", "", "Lossy or Lossless"}},
+	{inAudio, FieldInfo{"ServiceKind", "Service kind", "", "Kind of audio service (Complete Main, Commentary...)"}},
+	{inAudio, FieldInfo{"Alignment", "", "", "Alignment of audio frames in the container (AVI)"}},
+	{inAudio, FieldInfo{"Interleave_Duration", "", "s", "Duration of an interleave chunk (AVI)"}},
+	{inAudio, FieldInfo{"Interleave_VideoFrames", "", "", "Video frames per interleave chunk (AVI)"}},
+	{inAudio, FieldInfo{"Interleave_Preload", "", "s", "Audio preload (AVI)"}},
+	{inAudio, FieldInfo{"MD5_Unencoded", "", "", "MD5 of the decoded audio (FLAC)"}},
+	{inAudio, FieldInfo{"BedChannelCount", "Bed channel count", "channel", "Number of bed channels (object audio)"}},
+	{inAudio, FieldInfo{"BedChannelConfiguration", "Bed channel configuration", "", "Layout of the bed channels (object audio)"}},
+	{inAudio, FieldInfo{"ComplexityIndex", "Complexity index", "", "Complexity index of the object audio"}},
+	{inAudio, FieldInfo{"NumberOfDynamicObjects", "Number of dynamic objects", "", "Number of dynamic objects (object audio)"}},
+	{inAudio, FieldInfo{"format_identifier", "", "", "Registration descriptor format identifier (MPEG-TS)"}},
+	{inAudio, FieldInfo{"bsid", "bsid", "", "Bit stream identification (AC-3)"}},
+	{inAudio, FieldInfo{"acmod", "acmod", "", "Audio coding mode (AC-3)"}},
+	{inAudio, FieldInfo{"lfeon", "lfeon", "", "Whether the LFE channel is on (AC-3)"}},
+	{inAudio, FieldInfo{"dsurmod", "dsurmod", "", "Dolby Surround mode (AC-3)"}},
+	{inAudio, FieldInfo{"cmixlev", "cmixlev", "dB", "Center mix level (AC-3)"}},
+	{inAudio, FieldInfo{"cmixlev_String", "", "", "Center mix level, as text (AC-3)"}},
+	{inAudio, FieldInfo{"surmixlev", "surmixlev", "dB", "Surround mix level (AC-3)"}},
+	{inAudio, FieldInfo{"surmixlev_String", "", "", "Surround mix level, as text (AC-3)"}},
+	{inAudio, FieldInfo{"mixlevel", "mixlevel", "dB", "Mixing level (AC-3)"}},
+	{inAudio, FieldInfo{"roomtyp", "roomtyp", "", "Room type (AC-3)"}},
+	{inAudio, FieldInfo{"dialnorm", "Dialog Normalization", "dB", "Dialog normalization (AC-3)"}},
+	{inAudio, FieldInfo{"dialnorm_String", "", "", "Dialog normalization, as text (AC-3)"}},
+	{inAudio, FieldInfo{"dialnorm_Average", "dialnorm_Average", "dB", "Average dialog normalization over the scanned frames"}},
+	{inAudio, FieldInfo{"dialnorm_Average_String", "", "", "Average dialog normalization, as text"}},
+	{inAudio, FieldInfo{"dialnorm_Minimum", "dialnorm_Minimum", "dB", "Minimum dialog normalization over the scanned frames"}},
+	{inAudio, FieldInfo{"dialnorm_Minimum_String", "", "", "Minimum dialog normalization, as text"}},
+	{inAudio, FieldInfo{"dialnorm_Maximum", "dialnorm_Maximum", "dB", "Maximum dialog normalization over the scanned frames"}},
+	{inAudio, FieldInfo{"dialnorm_Maximum_String", "", "", "Maximum dialog normalization, as text"}},
+	{inAudio, FieldInfo{"dialnorm_Count", "dialnorm_Count", "", "Frames the dialog normalization statistics cover"}},
+	{inAudio, FieldInfo{"compr", "compr", "dB", "Compression gain word (AC-3)"}},
+	{inAudio, FieldInfo{"compr_String", "", "", "Compression gain word, as text"}},
+	{inAudio, FieldInfo{"compr_Average", "compr_Average", "dB", "Average compression gain over the scanned frames"}},
+	{inAudio, FieldInfo{"compr_Average_String", "", "", "Average compression gain, as text"}},
+	{inAudio, FieldInfo{"compr_Minimum", "compr_Minimum", "dB", "Minimum compression gain over the scanned frames"}},
+	{inAudio, FieldInfo{"compr_Minimum_String", "", "", "Minimum compression gain, as text"}},
+	{inAudio, FieldInfo{"compr_Maximum", "compr_Maximum", "dB", "Maximum compression gain over the scanned frames"}},
+	{inAudio, FieldInfo{"compr_Maximum_String", "", "", "Maximum compression gain, as text"}},
+	{inAudio, FieldInfo{"compr_Count", "compr_Count", "", "Frames the compression gain statistics cover"}},
+	{inAudio, FieldInfo{"dynrng", "dynrng", "dB", "Dynamic range gain word (AC-3)"}},
+	{inAudio, FieldInfo{"dynrng_Average", "", "dB", "Average dynamic range gain over the scanned frames"}},
+	{inAudio, FieldInfo{"dynrng_Minimum", "", "dB", "Minimum dynamic range gain over the scanned frames"}},
+	{inAudio, FieldInfo{"dynrng_Maximum", "", "dB", "Maximum dynamic range gain over the scanned frames"}},
+	{inAudio, FieldInfo{"dynrng_Count", "", "", "Frames the dynamic range statistics cover"}},
+
+	// Text.
+	{inText, FieldInfo{"ElementCount", "Count of elements", "", "Number of subtitle elements"}},
+	{inText, FieldInfo{"Lines_Count", "", "", "Number of caption lines"}},
+	{inText, FieldInfo{"CaptionServiceName", "Caption service name", "", "Name of the caption service (CEA-708)"}},
+	{inText, FieldInfo{"CaptionServiceDescriptor_IsPresent", "", "", "Whether a caption service descriptor is present (MPEG-TS)"}},
+	{inText, FieldInfo{"subtitle_stream_id", "", "", "Subtitle stream identifier (DVB subtitles)"}},
+	{inText, FieldInfo{"page_id", "", "", "Page identifier (DVB subtitles)"}},
+	{inText, FieldInfo{"region_id", "", "", "Region identifier (DVB subtitles)"}},
+	{inText, FieldInfo{"region_horizontal_address", "", "pixel", "Horizontal position of the region (DVB subtitles)"}},
+	{inText, FieldInfo{"region_vertical_address", "", "pixel", "Vertical position of the region (DVB subtitles)"}},
+	{inText, FieldInfo{"region_width", "", "pixel", "Width of the region (DVB subtitles)"}},
+	{inText, FieldInfo{"region_height", "", "pixel", "Height of the region (DVB subtitles)"}},
+	{inText, FieldInfo{"region_depth", "", "bit", "Color depth of the region (DVB subtitles)"}},
+
+	// Sizes.
+	{inAll, FieldInfo{"StreamSize", "Stream size", "byte", "Size of the stream"}},
+	{inStreams, FieldInfo{"StreamSize_Proportion", "Proportion of this stream", "", "Share of the file size taken by the stream"}},
+	{inAV, FieldInfo{"StreamSize_Encoded", "", "byte", "Size of the stream as encoded, before container overhead"}},
+	{inAV, FieldInfo{"Source_StreamSize", "Source stream size", "byte", "Size of the source stream"}},
+	{inGeneral, FieldInfo{"HeaderSize", "", "byte", "Size of the container header"}},
+	{inGeneral, FieldInfo{"DataSize", "", "byte", "Size of the container payload"}},
+	{inGeneral, FieldInfo{"FooterSize", "", "byte", "Size of the container footer"}},
+	{inGeneral, FieldInfo{"IsStreamable", "", "", "Whether the file can be played while downloading"}},
+	{inGeneral, FieldInfo{"IsFragmented", "", "", "Whether the samples are stored in movie fragments (fragmented MP4, CMAF)"}},
+	{inGeneral, FieldInfo{"Interleaved", "", "", "Whether audio and video are interleaved"}},
+
+	// Encoding and dates.
+	{inGeneral, FieldInfo{"File_Created_Date", "", "", "Creation time of the file (UTC)"}},
+	{inGeneral, FieldInfo{"File_Created_Date_Local", "", "", "Creation time of the file (local time)"}},
+	{inGeneral, FieldInfo{"File_Modified_Date", "", "", "Modification time of the file (UTC)"}},
+	{inGeneral, FieldInfo{"File_Modified_Date_Local", "", "", "Modification time of the file (local time)"}},
+	{inGeneral | inAVT, FieldInfo{"Encoded_Date", "Encoded date", "", "Time of the encoding"}},
+	{inGeneral | inAVT, FieldInfo{"Tagged_Date", "Tagged date", "", "Time of the tagging"}},
+	{inGeneral | inAVT, FieldInfo{"Encoded_Application", "Writing application", "", "Software that muxed the file"}},
+	{inGeneral | inAVT, FieldInfo{"Encoded_Library", "Writing library", "", "Library that encoded the stream"}},
+	{inGeneral | inAVT, FieldInfo{"Encoded_Library_Name", "", "", "Name of the encoding library"}},
+	{inGeneral | inAVT, FieldInfo{"Encoded_Library_Version", "", "", "Version of the encoding library"}},
+	{inGeneral | inAVT, FieldInfo{"Encoded_Library_Date", "", "", "Release date of the encoding library"}},
+	{inGeneral | inAVT, FieldInfo{"Encoded_Library_Settings", "Encoding settings", "", "Parameters used by the encoder"}},
+	{inGeneral, FieldInfo{"ErrorDetectionType", "ErrorDetectionType", "", "Error detection used by the container (Per level 1...)"}},
+	{inGeneral, FieldInfo{"ConformanceWarnings", "Conformance warnings", "", "Whether the file breaks a rule of its format"}},
+	{inGeneral, FieldInfo{"GeneralCompliance", " General compliance", "", "Rule of the format the file breaks"}},
+
+	// Description and tags.
+	{inAll, FieldInfo{"Title", "Title", "", "Title of the file or stream"}},
+	{inGeneral, FieldInfo{"Movie", "Movie name", "", "Name of the movie"}},
+	{inAll, FieldInfo{"Language", "Language", "", "Language of the stream"}},
+	{inAVT, FieldInfo{"Default", "Default", "", "Whether the stream is selected by default"}},
+	{inAVT, FieldInfo{"Forced", "Forced", "", "Whether the stream is forced"}},
+	{inAVT, FieldInfo{"AlternateGroup", "Alternate group", "", "Group of alternative streams (MP4)"}},
+	{inAll, FieldInfo{"Description", "Description", "", "Description of the file or stream"}},
+	{inGeneral, FieldInfo{"LawRating", "Law rating", "", "Legal rating of the content"}},
+	{inVideo | inAudio | inMenu, FieldInfo{"Source", "Source", "", "File the stream comes from (DVD)"}},
+	{inGeneral, FieldInfo{"Album", "", "", "Album name"}},
+	{inGeneral, FieldInfo{"Album_Performer", "", "", "Album artist"}},
+	{inGeneral, FieldInfo{"Part", "", "", "Part (disc) name"}},
+	{inGeneral, FieldInfo{"Part_Position", "", "", "Part (disc) number"}},
+	{inGeneral, FieldInfo{"Part_Position_Total", "", "", "Number of parts (discs)"}},
+	{inGeneral, FieldInfo{"Track", "", "", "Track name"}},
+	{inGeneral, FieldInfo{"Track_Position", "", "", "Track number"}},
+	{inGeneral, FieldInfo{"Track_Position_Total", "", "", "Number of tracks"}},
+	{inGeneral, FieldInfo{"Performer", "", "", "Main artist"}},
+	{inGeneral, FieldInfo{"Original_Performer", "", "", "Original artist"}},
+	{inGeneral, FieldInfo{"Composer", "", "", "Composer"}},
+	{inGeneral, FieldInfo{"Conductor", "", "", "Conductor"}},
+	{inGeneral, FieldInfo{"Lyricist", "", "", "Lyricist"}},
+	{inGeneral, FieldInfo{"Original_Lyricist", "", "", "Original lyricist"}},
+	{inGeneral, FieldInfo{"RemixedBy", "", "", "Remixer"}},
+	{inGeneral, FieldInfo{"EncodedBy", "", "", "Person or organization that encoded the file"}},
+	{inGeneral, FieldInfo{"Publisher", "", "", "Publisher"}},
+	{inGeneral, FieldInfo{"Label", "", "", "Record label"}},
+	{inGeneral, FieldInfo{"Genre", "", "", "Genre"}},
+	{inGeneral, FieldInfo{"Recorded_Date", "", "", "Recording date"}},
+	{inGeneral, FieldInfo{"Copyright", "", "", "Copyright notice"}},
+	{inGeneral, FieldInfo{"ISRC", "", "", "International Standard Recording Code"}},
+	{inGeneral, FieldInfo{"Comment", "", "", "Comment"}},
+	{inGeneral, FieldInfo{"Lyrics", "", "", "Lyrics"}},
+	{inGeneral, FieldInfo{"URL", "", "", "Web address"}},
+	{inGeneral, FieldInfo{"Cover", "", "", "Whether a cover picture is embedded"}},
+	{inGeneral, FieldInfo{"Cover_Description", "", "", "Description of the cover picture"}},
+	{inGeneral, FieldInfo{"Cover_Type", "", "", "Kind of the cover picture"}},
+	{inGeneral, FieldInfo{"Cover_Mime", "", "", "MIME type of the cover picture"}},
+
+	// Menu.
+	{inVideo | inMenu, FieldInfo{"ServiceName", "Service name", "", "Name of the broadcast service"}},
+	{inVideo | inMenu, FieldInfo{"ServiceProvider", "Service provider", "", "Provider of the broadcast service"}},
+	{inVideo | inMenu, FieldInfo{"ServiceType", "Service type", "", "Type of the broadcast service"}},
+	{inVideo | inMenu, FieldInfo{"List_StreamKind", "", "", "Kinds of the streams of the program (MediaInfo kind numbers)"}},
+	{inVideo | inMenu, FieldInfo{"List_StreamPos", "", "", "Positions of the streams of the program, base 0"}},
+	{inMenu, FieldInfo{"List", "List", "", "Streams of the program (ID (format))"}},
+	{inMenu, FieldInfo{"List_Audio", "List (Audio)", "", "Audio streams of the DVD menu"}},
+	{inMenu, FieldInfo{"List_Subtitles_4_3", "List (Subtitles 4/3)", "", "4/3 subtitle streams of the DVD menu"}},
+	{inMenu, FieldInfo{"List_Subtitles_Wide", "List (Subtitles Wide)", "", "Widescreen subtitle streams of the DVD menu"}},
+	{inMenu, FieldInfo{"List_Subtitles_Letterbox", "List (Subtitles Letterbox)", "", "Letterbox subtitle streams of the DVD menu"}},
+	{inMenu, FieldInfo{"List_Subtitles_PanScan", "List (Subtitles Pan&Scan)", "", "Pan&scan subtitle streams of the DVD menu"}},
+	{inMenu, FieldInfo{"pointer_field", "", "", "Pointer field of the program map section (MPEG-TS)"}},
+	{inMenu, FieldInfo{"section_length", "", "byte", "Length of the program map section (MPEG-TS)"}},
+}
+
+// RegisteredFields returns the fields kind can carry.
+func RegisteredFields(kind StreamKind) []FieldInfo {
+	set := kindSets[kind]
+	var out []FieldInfo
+	for _, field := range fieldRegistry {
+		if field.kinds&set != 0 {
+			out = append(out, field.FieldInfo)
+		}
+	}
+	return out
+}
+
+// lookupField returns the field of kind with the given internal or display name.
+func lookupField(kind StreamKind, name string) (FieldInfo, bool) {
+	set := kindSets[kind]
+	for _, field := range fieldRegistry {
+		if field.kinds&set != 0 && (field.Name == name || field.Display == name) {
+			return field.FieldInfo, true
+		}
+	}
+	return FieldInfo{}, false
+}
+
+// isChapterField reports whether name is a menu chapter entry, whose name is its start
+// time: "00:01:30.000" in text output, "_00_01_30_000" in JSON.
+func isChapterField(name string) bool {
+	name = strings.TrimPrefix(name, "_")
+	if len(name) != len("00:00:00.000") {
+		return false
+	}
+	for i, r := range name {
+		switch i {
+		case 2, 5:
+			if r != ':' && r != '_' {
+				return false
+			}
+		case 8:
+			if r != '.' && r != '_' {
+				return false
+			}
+		default:
+			if r < '0' || r > '9' {
+				return false
+			}
+		}
+	}
+	return true
+}
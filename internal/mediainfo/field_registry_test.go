@@ -0,0 +1,173 @@
+package mediainfo
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFieldRegistryCoversSampleOutput(t *testing.T) {
+	paths, err := filepath.Glob("samples/sample.*")
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no samples: %v", err)
+	}
+	for _, path := range paths {
+		report, err := AnalyzeFile(path)
+		if err != nil {
+			continue
+		}
+		// JSON keys, extra members included.
+		for _, track := range buildJSONMedia(report).Tracks {
+			kind := StreamKind(jsonFieldValue(track.Fields, "@type"))
+			for _, kv := range expandJSONExtra(track.Fields) {
+				if kv.Key == "@type" || kv.Key == "@typeorder" || kind == StreamMenu && isChapterField(kv.Key) {
+					continue
+				}
+				if _, ok := lookupField(kind, kv.Key); !ok {
+					t.Errorf("%s: JSON %s field %q is not registered", path, kind, kv.Key)
+				}
+			}
+		}
+		// Display names, as written by the text, HTML and CSV renderers, in both modes.
+		for _, full := range []Report{report, fullReports([]Report{report})[0]} {
+			for _, stream := range append([]Stream{full.General}, full.Streams...) {
+				for _, field := range stream.Fields {
+					if stream.Kind == StreamMenu && isChapterField(field.Name) {
+						continue
+					}
+					if _, ok := lookupField(stream.Kind, field.Name); !ok {
+						t.Errorf("%s: %s field %q is not registered", path, stream.Kind, field.Name)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestFieldRegistryCoversSourceNames checks the field names the parsers spell out as
+// literals: jsonKV keys, JSON extra map keys, JSON order tables, tag mappings and display
+// names of Field literals.
+func TestFieldRegistryCoversSourceNames(t *testing.T) {
+	registered := map[string]bool{}
+	for _, field := range fieldRegistry {
+		registered[field.Name] = true
+		registered[field.Display] = true
+	}
+	paths, err := filepath.Glob("internal/mediainfo/*.go")
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no sources: %v", err)
+	}
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		base := filepath.Base(path)
+		// Renderers build document structure, not fields.
+		if strings.HasSuffix(base, "_test.go") || strings.HasPrefix(base, "output_") || strings.HasPrefix(base, "xml_") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		check := func(expr ast.Expr) {
+			lit, ok := expr.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return
+			}
+			name, err := strconv.Unquote(lit.Value)
+			if err != nil || name == "" || name == "extra" || strings.HasPrefix(name, "@") || registered[name] {
+				return
+			}
+			t.Errorf("%s: field %q is not registered", fset.Position(lit.Pos()), name)
+		}
+		checkLiteral := func(lit *ast.CompositeLit, typeName string) {
+			field := map[string]string{"Field": "Name", "jsonKV": "Key"}[typeName]
+			for _, elt := range lit.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if id, ok := kv.Key.(*ast.Ident); ok && id.Name == field {
+						check(kv.Value)
+					}
+				}
+			}
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.CompositeLit:
+				switch typ := n.Type.(type) {
+				case *ast.Ident:
+					checkLiteral(n, typ.Name)
+				case *ast.ArrayType:
+					if id, ok := typ.Elt.(*ast.Ident); ok {
+						for _, elt := range n.Elts {
+							if lit, ok := elt.(*ast.CompositeLit); ok && lit.Type == nil {
+								checkLiteral(lit, id.Name)
+							}
+						}
+					}
+				}
+			case *ast.ValueSpec:
+				for i, name := range n.Names {
+					if strings.HasPrefix(name.Name, "json") && strings.HasSuffix(name.Name, "FieldOrder") && i < len(n.Values) {
+						if lit, ok := n.Values[i].(*ast.CompositeLit); ok {
+							for _, elt := range lit.Elts {
+								check(elt.(*ast.KeyValueExpr).Key)
+							}
+						}
+					}
+				}
+			case *ast.AssignStmt:
+				for _, lhs := range n.Lhs {
+					index, ok := lhs.(*ast.IndexExpr)
+					if !ok {
+						continue
+					}
+					var target string
+					switch x := index.X.(type) {
+					case *ast.Ident:
+						target = x.Name
+					case *ast.SelectorExpr:
+						target = x.Sel.Name
+					}
+					if lower := strings.ToLower(target); strings.Contains(lower, "json") || strings.Contains(lower, "extra") {
+						check(index.Index)
+					}
+				}
+			case *ast.CallExpr:
+				switch fn := n.Fun.(type) {
+				case *ast.Ident:
+					switch {
+					case fn.Name == "setFieldValue" || fn.Name == "insertFieldBefore" || fn.Name == "appendJSONExtra" || fn.Name == "setJSONField":
+						if len(n.Args) > 1 {
+							check(n.Args[1])
+						}
+					case fn.Obj != nil && fn.Obj.Kind == ast.Var && fn.Name == "set" && len(n.Args) > 0:
+						// Tag mapping closures: set(key, value).
+						check(n.Args[0])
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+func TestInfoParametersListsRegistry(t *testing.T) {
+	out := InfoParameters()
+	for _, want := range []string{
+		"General\n",
+		"\nVideo\n",
+		"Format_Settings_CABAC                    : Whether CABAC entropy coding is used (AVC) [Format settings, CABAC]\n",
+		"SamplingRate                             : Samples per second, in Hz [Sampling rate]\n",
+		"StreamSize_Proportion                    : Share of the file size taken by the stream [Proportion of this stream]\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	if len(RegisteredFields(StreamAudio)) < 100 {
+		t.Fatalf("audio registry has %d fields", len(RegisteredFields(StreamAudio)))
+	}
+}
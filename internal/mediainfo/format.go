@@ -47,7 +47,7 @@ func DetectFormat(header []byte, filename string) string {
 				return "Wave"
 			}
 		}
-		if sig == "FORM" && string(header[8:12]) == "AIFF" {
+		if sig == "FORM" && (string(header[8:12]) == "AIFF" || string(header[8:12]) == "AIFC") {
 			return "AIFF"
 		}
 	}
@@ -57,6 +57,21 @@ func DetectFormat(header []byte, filename string) string {
 	if bytes.HasPrefix(header, []byte("OggS")) {
 		return "Ogg"
 	}
+	if bytes.HasPrefix(header, []byte("DKIF")) {
+		return "IVF"
+	}
+	if isAV1LowOverhead(header) || ext == ".obu" && isAV1AnnexB(header) {
+		return "AV1"
+	}
+	if (ext == ".266" || ext == ".vvc" || ext == ".h266") && isVVCAnnexB(header) {
+		return "VVC"
+	}
+	if format := rawVideoFormat(header); format != "" {
+		return format
+	}
+	if format := rawAudioFormat(header); format != "" {
+		return format
+	}
 	if bytes.HasPrefix(header, []byte("ID3")) {
 		return "MPEG Audio"
 	}
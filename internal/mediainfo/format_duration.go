@@ -36,6 +36,12 @@ func formatDuration(seconds float64) string {
 	return fmt.Sprintf("%d min %d s", minutes, secondsOnly)
 }
 
+// formatClockDuration formats seconds as HH:MM:SS.mmm.
+func formatClockDuration(seconds float64) string {
+	ms := int64(math.Round(seconds * 1000))
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
 func formatBitrate(bitsPerSecond float64) string {
 	if bitsPerSecond <= 0 {
 		return ""
@@ -48,6 +54,19 @@ func formatBitrate(bitsPerSecond float64) string {
 	return formatThousands(kbps) + " kb/s"
 }
 
+// formatBitrateFraction is formatBitrate keeping one decimal when the rate is not a whole
+// number of kb/s, as MediaInfo shows PCM rates: "1 411.2 kb/s".
+func formatBitrateFraction(bitsPerSecond float64) string {
+	if bitsPerSecond <= 0 || bitsPerSecond >= 10_000_000 {
+		return formatBitrate(bitsPerSecond)
+	}
+	tenths := int64(math.Round(bitsPerSecond / 100))
+	if tenths%10 == 0 {
+		return formatBitrate(bitsPerSecond)
+	}
+	return fmt.Sprintf("%s.%d kb/s", formatThousands(tenths/10), tenths%10)
+}
+
 func formatBitrateKbps(kbps int64) string {
 	if kbps <= 0 {
 		return ""
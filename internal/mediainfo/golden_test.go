@@ -25,6 +25,8 @@ func TestGoldenOutputs(t *testing.T) {
 		{name: "xml", render: RenderXML, ext: ".xml", norm: normalizeXMLLike},
 		{name: "html", render: RenderHTML, ext: ".html", norm: normalizeText},
 		{name: "csv", render: RenderCSV, ext: ".csv", norm: normalizeText},
+		{name: "ebucore", render: RenderEBUCore, ext: ".ebucore.xml", norm: normalizeEBUCore},
+		{name: "ebucore_json", render: RenderEBUCoreJSON, ext: ".ebucore.json", norm: normalizeEBUCore},
 	}
 
 	samples := []string{
@@ -95,6 +97,9 @@ var (
 	reXMLCreatedLocal = regexp.MustCompile(`<File_Created_Date_Local>[^<]*</File_Created_Date_Local>`)
 	reXMLModUTC       = regexp.MustCompile(`<File_Modified_Date>[^<]*</File_Modified_Date>`)
 	reXMLModLocal     = regexp.MustCompile(`<File_Modified_Date_Local>[^<]*</File_Modified_Date_Local>`)
+
+	reEBUCoreXMLDate  = regexp.MustCompile(`startDate="[^"]*" startTime="[^"]*"`)
+	reEBUCoreJSONDate = regexp.MustCompile(`"@start(Date|Time)": "[^"]*"`)
 )
 
 func normalizeJSONLike(s string) string {
@@ -114,3 +119,10 @@ func normalizeXMLLike(s string) string {
 	s = reXMLModLocal.ReplaceAllString(s, `<File_Modified_Date_Local><redacted></File_Modified_Date_Local>`)
 	return s
 }
+
+func normalizeEBUCore(s string) string {
+	s = normalizeText(s)
+	s = reEBUCoreXMLDate.ReplaceAllString(s, `startDate="<redacted>" startTime="<redacted>"`)
+	s = reEBUCoreJSONDate.ReplaceAllString(s, `"@start$1": "<redacted>"`)
+	return s
+}
@@ -27,7 +27,7 @@ type h264SPSInfo struct {
 	HasColorDescription     bool
 	ProfileID               byte
 	LevelID                 byte
-	// HEVC-only: tier name ("Main" or "High") when available.
+	// HEVC/VVC-only: tier name ("Main" or "High") when available.
 	HEVCTier          string
 	Width             uint64
 	Height            uint64
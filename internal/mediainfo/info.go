@@ -1,5 +1,29 @@
 package mediainfo
 
+import "strings"
+
+// InfoParameters lists the fields of every stream kind, as mediainfo --Info-Parameters
+// does: internal name, description, unit and, when it differs, the text output label.
 func InfoParameters() string {
-	return "General\nComplete name\nFormat\nFile size\nDuration\nOverall bit rate mode\nOverall bit rate\n\nVideo\nID\nFormat\nWidth\nHeight\nFrame rate\nBit rate mode\nBit rate\nDuration\n\nAudio\nID\nFormat\nChannel(s)\nChannel layout\nSampling rate\nBit rate mode\nBit rate\nDuration\n\nText\nID\nFormat\nDuration\n\nMenu\n"
+	var b strings.Builder
+	for i, kind := range templateKinds {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(string(kind))
+		b.WriteString("\n")
+		for _, field := range RegisteredFields(kind) {
+			b.WriteString(padRight(field.Name, 41))
+			b.WriteString(": ")
+			b.WriteString(field.Description)
+			if field.Unit != "" {
+				b.WriteString(", in " + field.Unit)
+			}
+			if field.Display != "" && field.Display != field.Name {
+				b.WriteString(" [" + strings.TrimSpace(field.Display) + "]")
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
 }
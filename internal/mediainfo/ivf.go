@@ -0,0 +1,137 @@
+package mediainfo
+
+import (
+	"encoding/binary"
+	"io"
+	"strconv"
+)
+
+// ivfProbeFrames is the number of frames read for HDR metadata below ParseSpeed 1.
+const ivfProbeFrames = 30
+
+// maxIVFFrameSize bounds the frame payloads read for probing.
+const maxIVFFrameSize = 4 << 20
+
+var ivfCodecs = map[string]string{
+	"AV01": "AV1",
+	"VP90": "VP9",
+	"VP80": "VP8",
+}
+
+// ParseIVF reads IVF files, the frame container of the AOM and libvpx tools: the 32-byte
+// header gives the codec, size and time base, and the 12-byte frame headers the frame
+// count and stream size. AV1 frames are probed for the sequence header and HDR metadata.
+func ParseIVF(r io.ReaderAt, size int64, parseSpeed float64) (ContainerInfo, []Stream, bool) {
+	var header [32]byte
+	if _, err := r.ReadAt(header[:], 0); err != nil || string(header[0:4]) != "DKIF" {
+		return ContainerInfo{}, nil, false
+	}
+	headerSize := int64(binary.LittleEndian.Uint16(header[6:8]))
+	fourcc := string(header[8:12])
+	width := uint64(binary.LittleEndian.Uint16(header[12:14]))
+	height := uint64(binary.LittleEndian.Uint16(header[14:16]))
+	rate := binary.LittleEndian.Uint32(header[16:20])
+	scale := binary.LittleEndian.Uint32(header[20:24])
+	if headerSize < 32 {
+		headerSize = 32
+	}
+
+	var (
+		frames      int64
+		streamBytes int64
+		seq         av1SequenceHeader
+		hasSeq      bool
+		hdr         hevcHDRInfo
+	)
+	isAV1 := fourcc == "AV01"
+	for offset := headerSize; offset+12 <= size; {
+		var frameHeader [12]byte
+		if _, err := r.ReadAt(frameHeader[:], offset); err != nil {
+			break
+		}
+		frameSize := int64(binary.LittleEndian.Uint32(frameHeader[0:4]))
+		if offset+12+frameSize > size {
+			break
+		}
+		probe := !hasSeq || (frames < ivfProbeFrames || parseSpeed >= 1) && !hdr.complete()
+		if isAV1 && probe && frameSize <= maxIVFFrameSize {
+			frame := make([]byte, frameSize)
+			if _, err := r.ReadAt(frame, offset+12); err == nil {
+				var frameSeq av1SequenceHeader
+				if parseAV1OBUs(frame, &frameSeq, &hdr) && !hasSeq {
+					seq, hasSeq = frameSeq, true
+				}
+			}
+		}
+		frames++
+		streamBytes += frameSize
+		offset += 12 + frameSize
+	}
+	if frames == 0 {
+		return ContainerInfo{}, nil, false
+	}
+
+	format := ivfCodecs[fourcc]
+	if format == "" {
+		format = fourcc
+	}
+	fields := []Field{{Name: "Format", Value: format}}
+	if info := mapMatroskaFormatInfo(format); info != "" {
+		fields = append(fields, Field{Name: "Format/Info", Value: info})
+	}
+	fields = append(fields, Field{Name: "Codec ID", Value: fourcc})
+	jsonExtras := map[string]string{}
+	if hasSeq {
+		fields = append(fields, buildAV1Fields(seq, hdr, jsonExtras)...)
+	}
+	frameRate := 0.0
+	if rate > 0 && scale > 0 {
+		frameRate = float64(rate) / float64(scale)
+	}
+	fields = appendVideoStreamStats(fields, jsonExtras, frames, frameRate, streamBytes, size, width, height)
+
+	info := ContainerInfo{}
+	if frameRate > 0 {
+		info.DurationSeconds = float64(frames) / frameRate
+	}
+	return info, []Stream{{Kind: StreamVideo, Fields: fields, JSON: jsonExtras, JSONSkipStreamOrder: true}}, true
+}
+
+// appendVideoStreamStats adds the dimensions, frame rate, duration, bit rate, frame count
+// and stream size of a video elementary stream.
+func appendVideoStreamStats(fields []Field, jsonExtras map[string]string, frames int64, frameRate float64, streamBytes, fileSize int64, width, height uint64) []Field {
+	if width > 0 && height > 0 {
+		fields = append(fields, Field{Name: "Width", Value: formatPixels(width)})
+		fields = append(fields, Field{Name: "Height", Value: formatPixels(height)})
+		if ar := formatAspectRatio(width, height); ar != "" {
+			fields = append(fields, Field{Name: "Display aspect ratio", Value: ar})
+		}
+	}
+	if frameRate > 0 {
+		fields = append(fields, Field{Name: "Frame rate mode", Value: "Constant"})
+		fields = append(fields, Field{Name: "Frame rate", Value: formatFrameRateWithRatio(frameRate)})
+	}
+	if frames > 0 {
+		jsonExtras["FrameCount"] = strconv.FormatInt(frames, 10)
+	}
+	if frameRate > 0 && frames > 0 {
+		duration := float64(frames) / frameRate
+		fields = addStreamDuration(fields, duration)
+		jsonExtras["Duration"] = formatJSONSeconds(duration)
+		if streamBytes > 0 {
+			bitrate := float64(streamBytes) * 8 / duration
+			fields = append(fields, Field{Name: "Bit rate mode", Value: "Variable"})
+			fields = addStreamBitrate(fields, bitrate)
+			if bits := formatBitsPerPixelFrame(bitrate, width, height, frameRate); bits != "" && width > 0 && height > 0 {
+				fields = append(fields, Field{Name: "Bits/(Pixel*Frame)", Value: bits})
+			}
+		}
+	}
+	if streamBytes > 0 {
+		if streamSize := formatStreamSize(streamBytes, fileSize); streamSize != "" {
+			fields = append(fields, Field{Name: "Stream size", Value: streamSize})
+		}
+		jsonExtras["StreamSize"] = strconv.FormatInt(streamBytes, 10)
+	}
+	return fields
+}
@@ -30,16 +30,17 @@ var jsonGeneralFieldOrder = map[string]int{
 	"DataSize":                 22,
 	"FooterSize":               23,
 	"IsStreamable":             24,
-	"File_Created_Date":        25,
-	"File_Created_Date_Local":  26,
-	"File_Modified_Date":       27,
-	"File_Modified_Date_Local": 28,
-	"Encoded_Application":      29,
-	"Encoded_Library":          30,
-	"Encoded_Library_Name":     31,
-	"Encoded_Library_Version":  32,
-	"Encoded_Library_Settings": 33,
-	"extra":                    34,
+	"IsFragmented":             25,
+	"File_Created_Date":        26,
+	"File_Created_Date_Local":  27,
+	"File_Modified_Date":       28,
+	"File_Modified_Date_Local": 29,
+	"Encoded_Application":      30,
+	"Encoded_Library":          31,
+	"Encoded_Library_Name":     32,
+	"Encoded_Library_Version":  33,
+	"Encoded_Library_Settings": 34,
+	"extra":                    35,
 }
 
 var jsonVideoFieldOrder = map[string]int{
@@ -62,6 +63,7 @@ var jsonVideoFieldOrder = map[string]int{
 	"Format_Settings_Matrix":            14,
 	"Format_Settings_Matrix_Data":       15,
 	"Format_Settings_GOP":               16,
+	"Format_Settings_FilmGrain":         16,
 	"Format_Settings_PictureStructure":  16,
 	"CodecID":                           17,
 	"Duration":                          18,
@@ -17,6 +17,9 @@ type jsonKV struct {
 }
 
 func buildJSONMedia(report Report) jsonMediaOut {
+	if report.Err != nil {
+		return jsonMediaOut{Ref: report.Ref, Error: report.Err.Error()}
+	}
 	tracks := make([]jsonTrackOut, 0, len(report.Streams)+1)
 	tracks = append(tracks, jsonTrackOut{Fields: buildJSONGeneralFields(report)})
 	containerFormat := findField(report.General.Fields, "Format")
@@ -53,6 +56,8 @@ func buildJSONGeneralFields(report Report) []jsonKV {
 		if ext != "" {
 			fields = append(fields, jsonKV{Key: "FileExtension", Val: ext})
 		}
+	}
+	if report.Ref != "" && !report.fromReader {
 		if size := fileSizeBytes(report.Ref); size > 0 {
 			fields = append(fields, jsonKV{Key: "FileSize", Val: strconv.FormatInt(size, 10)})
 		}
@@ -170,6 +175,8 @@ func mapStreamFieldsToJSON(kind StreamKind, fields []Field) []jsonKV {
 			out = append(out, jsonKV{Key: "Format_Settings_Matrix", Val: field.Value})
 		case "Format settings, GOP":
 			out = append(out, jsonKV{Key: "Format_Settings_GOP", Val: field.Value})
+		case "Format settings, Film grain":
+			out = append(out, jsonKV{Key: "Format_Settings_FilmGrain", Val: field.Value})
 		case "Format settings, Picture structure":
 			out = append(out, jsonKV{Key: "Format_Settings_PictureStructure", Val: field.Value})
 		case "Format settings, Reference frames":
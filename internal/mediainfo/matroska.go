@@ -2,6 +2,7 @@ package mediainfo
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -125,6 +126,9 @@ const matroskaEAC3QuickProbePacketsJOC = 198
 const matroskaHEVCQuickProbePackets = 300
 const matroskaAVCQuickProbePackets = 8
 
+// AV1 HDR metadata OBUs repeat with every key frame; the first ones are enough.
+const matroskaAV1QuickProbePackets = 30
+
 type MatroskaInfo struct {
 	Container     ContainerInfo
 	General       []Field
@@ -138,10 +142,10 @@ type MatroskaInfo struct {
 }
 
 func ParseMatroska(r io.ReaderAt, size int64) (MatroskaInfo, bool) {
-	return ParseMatroskaWithOptions(r, size, defaultAnalyzeOptions())
+	return ParseMatroskaWithOptions(context.Background(), r, size, defaultAnalyzeOptions())
 }
 
-func ParseMatroskaWithOptions(r io.ReaderAt, size int64, opts AnalyzeOptions) (MatroskaInfo, bool) {
+func ParseMatroskaWithOptions(ctx context.Context, r io.ReaderAt, size int64, opts AnalyzeOptions) (MatroskaInfo, bool) {
 	opts = normalizeAnalyzeOptions(opts)
 	scanSize := min(size, mkvMaxScan)
 	if scanSize <= 0 {
@@ -368,7 +372,18 @@ func ParseMatroskaWithOptions(r io.ReaderAt, size int64, opts AnalyzeOptions) (M
 						videoProbes[id] = probe
 						continue
 					}
-					if format == "HEVC" && stream.nalLengthSize > 0 {
+					if format == "AV1" {
+						probe := &matroskaVideoProbe{
+							codec:       format,
+							headerStrip: stream.mkvHeaderStripBytes,
+						}
+						if opts.ParseSpeed < 1 {
+							probe.targetPackets = matroskaAV1QuickProbePackets
+						}
+						videoProbes[id] = probe
+						continue
+					}
+					if (format == "HEVC" || format == "VVC") && stream.nalLengthSize > 0 {
 						probe := &matroskaVideoProbe{
 							codec:         format,
 							nalLengthSize: stream.nalLengthSize,
@@ -410,7 +425,11 @@ func ParseMatroskaWithOptions(r io.ReaderAt, size int64, opts AnalyzeOptions) (M
 			if len(needFirstTimes) == 0 {
 				needFirstTimes = nil
 			}
-			if stats, ok := scanMatroskaClusters(r, info.SegmentOffset, info.SegmentSize, info.TimecodeScale, audioProbes, videoProbes, applyScan, applyStats, opts.ParseSpeed, trackCount, needFirstTimes); ok {
+			stats, ok := scanMatroskaClusters(ctx, r, info.SegmentOffset, info.SegmentSize, info.TimecodeScale, audioProbes, videoProbes, applyScan, applyStats, opts.ParseSpeed, trackCount, needFirstTimes)
+			if ctx.Err() != nil {
+				return MatroskaInfo{}, false
+			}
+			if ok {
 				if applyScan {
 					applyMatroskaStats(&info, stats, size)
 				}
@@ -1334,6 +1353,19 @@ func parseMatroskaTrackEntry(buf []byte, segmentDuration float64, durationPrec i
 			}
 		}
 	}
+	if kind == StreamVideo && codecID == "V_MPEGI/ISO/VVC" && len(codecPrivate) > 0 {
+		if vvcSPS, lengthSize, ok := parseVVCConfig(codecPrivate, nil); ok {
+			fields = append(fields, vvcFormatFields(vvcSPS)...)
+			nalLengthSize = lengthSize
+			spsInfo = vvcSPS
+		}
+	}
+	if kind == StreamVideo && codecID == "V_AV1" && len(codecPrivate) > 0 {
+		if seq, ok := parseAV1Config(codecPrivate, nil); ok {
+			fields = append(fields, av1FormatFields(seq)...)
+			spsInfo = seq.spsInfo()
+		}
+	}
 	if spsInfo.CodedWidth > 0 {
 		videoInfo.codedWidth = spsInfo.CodedWidth
 	}
@@ -1492,7 +1524,7 @@ func parseMatroskaTrackEntry(buf []byte, segmentDuration float64, durationPrec i
 			fields = insertFieldBefore(fields, Field{Name: "HDR format", Value: hdrFormat}, "Codec ID")
 		}
 		if findField(fields, "Color space") == "" {
-			if codecID == "V_MPEG4/ISO/AVC" || codecID == "V_MPEGH/ISO/HEVC" {
+			if codecID == "V_MPEG4/ISO/AVC" || codecID == "V_MPEGH/ISO/HEVC" || codecID == "V_MPEGI/ISO/VVC" || codecID == "V_AV1" {
 				fields = append(fields, Field{Name: "Color space", Value: "YUV"})
 			} else if videoInfo.colorRange != "" || videoInfo.colorPrimaries != "" || videoInfo.transferCharacteristics != "" || videoInfo.matrixCoefficients != "" {
 				if matroskaHasStreamColor(videoInfo) {
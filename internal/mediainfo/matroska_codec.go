@@ -6,6 +6,10 @@ func mapMatroskaCodecID(codecID string, trackType uint64) (StreamKind, string) {
 		return StreamVideo, "AVC"
 	case "V_MPEGH/ISO/HEVC":
 		return StreamVideo, "HEVC"
+	case "V_MPEGI/ISO/VVC":
+		return StreamVideo, "VVC"
+	case "V_AV1":
+		return StreamVideo, "AV1"
 	case "V_VP9":
 		return StreamVideo, "VP9"
 	case "V_VP8":
@@ -45,6 +49,10 @@ func mapMatroskaFormatInfo(format string) string {
 		return "Advanced Video Codec"
 	case "HEVC":
 		return "High Efficiency Video Coding"
+	case "VVC":
+		return "Versatile Video Coding"
+	case "AV1":
+		return "AOMedia Video 1"
 	case "VP9":
 		return "Google VP9"
 	case "VP8":
@@ -3,6 +3,7 @@ package mediainfo
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -263,7 +264,7 @@ func readMatroskaElementHeader(er *ebmlReader, size int64, start int64) (uint64,
 
 var errMatroskaScanLimit = errors.New("matroska scan limit reached")
 
-func scanMatroskaClusters(r io.ReaderAt, offset int64, size int64, timecodeScale uint64, audioProbes map[uint64]*matroskaAudioProbe, videoProbes map[uint64]*matroskaVideoProbe, applyScan bool, collectBytes bool, parseSpeed float64, trackCount int, needFirstTimes map[uint64]struct{}) (map[uint64]*matroskaTrackStats, bool) {
+func scanMatroskaClusters(ctx context.Context, r io.ReaderAt, offset int64, size int64, timecodeScale uint64, audioProbes map[uint64]*matroskaAudioProbe, videoProbes map[uint64]*matroskaVideoProbe, applyScan bool, collectBytes bool, parseSpeed float64, trackCount int, needFirstTimes map[uint64]struct{}) (map[uint64]*matroskaTrackStats, bool) {
 	if size <= 0 {
 		return nil, false
 	}
@@ -288,6 +289,9 @@ func scanMatroskaClusters(r io.ReaderAt, offset int64, size int64, timecodeScale
 	}
 
 	for er.pos < size {
+		if ctx.Err() != nil {
+			return stats, false
+		}
 		id, elemSize, err := readMatroskaElementHeader(er, size, 0)
 		if err != nil {
 			break
@@ -730,7 +734,7 @@ func videoProbeNeedsSample(probe *matroskaVideoProbe) bool {
 		return false
 	}
 	switch probe.codec {
-	case "HEVC":
+	case "HEVC", "VVC", "AV1":
 		return !probe.hdrInfo.complete()
 	case "AVC":
 		return probe.writingLib == "" || probe.encoding == ""
@@ -1581,6 +1585,10 @@ func applyMatroskaVideoProbes(info *MatroskaInfo, probes map[uint64]*matroskaVid
 		}
 		if hdr.hdr10Plus {
 			stream.Fields = mergeHDRFormatField(stream.Fields, formatHDR10Plus(hdr))
+		} else if hdr.hasMastering && !stream.mkvHasDolbyVision && findField(stream.Fields, "HDR format") == "" {
+			stream.Fields = mergeHDRFormatField(stream.Fields, "SMPTE ST 2086, HDR10 compatible")
+			stream.JSON["HDR_Format"] = "SMPTE ST 2086"
+			stream.JSON["HDR_Format_Compatibility"] = "HDR10"
 		}
 		if stream.mkvHasDolbyVision || hdr.hdr10Plus {
 			parts := []string{}
@@ -1862,6 +1870,14 @@ func probeMatroskaVideo(probes map[uint64]*matroskaVideoProbe, track uint64, pay
 		parseHEVCSampleHDR(payload, probe.nalLengthSize, &probe.hdrInfo)
 		return
 	}
+	if probe.codec == "VVC" {
+		parseVVCSampleHDR(payload, probe.nalLengthSize, &probe.hdrInfo)
+		return
+	}
+	if probe.codec == "AV1" {
+		parseAV1SampleHDR(payload, &probe.hdrInfo)
+		return
+	}
 	if probe.codec == "AVC" {
 		// Cheap x264 metadata extraction: SEI user_data_unregistered carries ASCII settings.
 		// We can match official output without a full stream parse.
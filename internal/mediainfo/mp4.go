@@ -33,6 +33,9 @@ type MP4Track struct {
 	Timescale        uint32
 	Width            uint64
 	Height           uint64
+	// SyncInterval is the constant distance between sync samples of a fragmented track,
+	// or 0 when it is unknown or varies.
+	SyncInterval int64
 }
 
 type MP4Info struct {
@@ -43,6 +46,12 @@ type MP4Info struct {
 	MovieCreation  uint64
 	MovieModified  uint64
 	Chapters       []mp4Chapter
+	// Fragmented is set when moov carries an mvex box; sample statistics then come from
+	// the moof boxes.
+	Fragmented bool
+
+	trackDefaults    map[uint32]mp4TrackDefaults
+	fragmentDuration uint64
 }
 
 type mp4Chapter struct {
@@ -50,24 +59,37 @@ type mp4Chapter struct {
 	title   string
 }
 
+// ParseMP4 reads the moov box of an MP4 or QuickTime file and, for fragmented files,
+// every moof box after it.
 func ParseMP4(r io.ReaderAt, size int64) (MP4Info, bool) {
-	info := MP4Info{}
-	var offset int64
+	return parseMP4(r, size, 1)
+}
+
+// parseMP4 is ParseMP4 with a ParseSpeed budget: below 1, at most mp4FragmentBudget
+// fragments are read and the rest is extrapolated.
+func parseMP4(r io.ReaderAt, size int64, parseSpeed float64) (MP4Info, bool) {
+	var (
+		ftyp   []Field
+		info   MP4Info
+		frags  *mp4Fragments
+		moofs  int
+		offset int64
+	)
 	for offset+8 <= size {
 		boxSize, boxType, headerSize, ok := readMP4BoxHeader(r, offset, size)
 		if !ok || boxSize <= 0 {
 			break
 		}
 		dataOffset := offset + headerSize
-		if boxType == "ftyp" {
+		switch boxType {
+		case "ftyp":
 			payload := make([]byte, boxSize-headerSize)
 			if _, err := r.ReadAt(payload, dataOffset); err == nil || err == io.EOF {
 				if fields := parseFtyp(payload); len(fields) > 0 {
-					info.General = append(info.General, fields...)
+					ftyp = append(ftyp, fields...)
 				}
 			}
-		}
-		if boxType == "moov" {
+		case "moov":
 			moovSize := boxSize - headerSize
 			if moovSize > maxMoovSize {
 				return MP4Info{}, false
@@ -76,16 +98,67 @@ func ParseMP4(r io.ReaderAt, size int64) (MP4Info, bool) {
 			if _, err := r.ReadAt(buf, dataOffset); err != nil && err != io.EOF {
 				return MP4Info{}, false
 			}
-			if moovInfo, ok := parseMoov(buf); ok {
-				if len(info.General) > 0 {
-					moovInfo.General = append(info.General, moovInfo.General...)
-				}
+			moovInfo, ok := parseMoov(buf)
+			if !ok {
+				break
+			}
+			if len(ftyp) > 0 {
+				moovInfo.General = append(ftyp, moovInfo.General...)
+			}
+			if !moovInfo.Fragmented {
 				return moovInfo, true
 			}
+			info = moovInfo
+			frags = &mp4Fragments{
+				tracks:       map[uint32]*mp4FragmentTrack{},
+				sidxDuration: map[uint32]uint64{},
+				tfraTime:     map[uint32]uint64{},
+			}
+		case "moof":
+			if frags == nil {
+				break
+			}
+			if parseSpeed < 1 && moofs >= mp4FragmentBudget {
+				frags.truncated = true
+				offset = size
+				continue
+			}
+			moofSize := boxSize - headerSize
+			if moofSize > maxMoofSize {
+				break
+			}
+			buf := make([]byte, moofSize)
+			if _, err := r.ReadAt(buf, dataOffset); err != nil && err != io.EOF {
+				break
+			}
+			frags.parseMoof(buf, offset, info.trackDefaults)
+			moofs++
+		case "sidx":
+			if frags == nil || boxSize-headerSize > maxMoofSize {
+				break
+			}
+			buf := make([]byte, boxSize-headerSize)
+			if _, err := r.ReadAt(buf, dataOffset); err != nil && err != io.EOF {
+				break
+			}
+			timescales := map[uint32]uint32{}
+			for _, track := range info.Tracks {
+				timescales[track.ID] = track.Timescale
+			}
+			frags.parseSidx(buf, timescales)
 		}
 		offset += boxSize
 	}
-	return MP4Info{}, false
+	if frags == nil {
+		return MP4Info{}, false
+	}
+	if frags.truncated {
+		if mfra := readMP4Mfra(r, size); mfra != nil {
+			frags.parseMfra(mfra)
+		}
+	}
+	applyMP4Fragments(&info, frags, info.fragmentDuration)
+	return info, true
 }
 
 func readMP4BoxHeader(r io.ReaderAt, offset, fileSize int64) (boxSize int64, boxType string, headerSize int64, ok bool) {
@@ -152,6 +225,11 @@ func parseMoov(buf []byte) (MP4Info, bool) {
 				info.Tracks = append(info.Tracks, track)
 			}
 		}
+		if boxType == "mvex" {
+			payload := sliceBox(buf, dataOffset, boxSize-headerSize)
+			info.trackDefaults, info.fragmentDuration = parseMvex(payload)
+			info.Fragmented = true
+		}
 		offset += boxSize
 	}
 	if info.Container.HasDuration() || len(info.Tracks) > 0 {
@@ -99,6 +99,10 @@ func mapMP4SampleEntry(sample string) string {
 		return "AVC"
 	case "hvc1", "hev1":
 		return "HEVC"
+	case "vvc1", "vvi1":
+		return "VVC"
+	case "av01":
+		return "AV1"
 	case "mp4v":
 		return "MPEG-4 Visual"
 	case "mp4a":
@@ -126,7 +130,7 @@ func mapMP4SampleEntry(sample string) string {
 
 func isVideoSampleEntry(sample string) bool {
 	switch sample {
-	case "avc1", "avc3", "hvc1", "hev1", "mp4v":
+	case "avc1", "avc3", "hvc1", "hev1", "vvc1", "vvi1", "av01", "mp4v":
 		return true
 	default:
 		return false
@@ -254,6 +258,32 @@ func parseVisualSampleEntry(entry []byte, sampleType string) sampleEntryResult {
 		}
 		fields = appendFieldUnique(fields, Field{Name: "Color space", Value: "YUV"})
 	}
+	if sampleType == "vvc1" || sampleType == "vvi1" {
+		payload, ok := findMP4ChildBox(entry, mp4VisualSampleEntryHeaderSize, "vvcC")
+		if !ok {
+			payload, ok = findMP4BoxByName(entry, "vvcC")
+		}
+		if ok {
+			var hdr hevcHDRInfo
+			if sps, _, ok := parseVVCConfig(payload, &hdr); ok {
+				fields = append(fields, buildVVCFields(sps, hdr, jsonExtras)...)
+				fields = append(fields, Field{Name: "Codec configuration box", Value: "vvcC"})
+			}
+		}
+	}
+	if sampleType == "av01" {
+		payload, ok := findMP4ChildBox(entry, mp4VisualSampleEntryHeaderSize, "av1C")
+		if !ok {
+			payload, ok = findMP4BoxByName(entry, "av1C")
+		}
+		if ok {
+			var hdr hevcHDRInfo
+			if seq, ok := parseAV1Config(payload, &hdr); ok {
+				fields = append(fields, buildAV1Fields(seq, hdr, jsonExtras)...)
+				fields = append(fields, Field{Name: "Codec configuration box", Value: "av1C"})
+			}
+		}
+	}
 	// When AVC bitstream says "not fixed" but container timing is CFR, official MediaInfo keeps CFR
 	// and reports the bitstream hint as FrameRate_Mode_Original=VFR.
 	if spsInfo.HasFixedFrameRate && !spsInfo.FixedFrameRate {
@@ -572,6 +602,10 @@ func mapVideoFormatInfo(sampleType string) string {
 		return "Advanced Video Codec"
 	case "hvc1", "hev1":
 		return "High Efficiency Video Coding"
+	case "vvc1", "vvi1":
+		return "Versatile Video Coding"
+	case "av01":
+		return "AOMedia Video 1"
 	case "mp4v":
 		return "MPEG-4 Visual"
 	default:
@@ -585,6 +619,10 @@ func mapVideoCodecIDInfo(sampleType string) string {
 		return "Advanced Video Coding"
 	case "hvc1", "hev1":
 		return "High Efficiency Video Coding"
+	case "vvc1", "vvi1":
+		return "Versatile Video Coding"
+	case "av01":
+		return "AOMedia Video 1"
 	case "mp4v":
 		return "MPEG-4 Visual"
 	default:
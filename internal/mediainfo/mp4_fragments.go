@@ -0,0 +1,383 @@
+package mediainfo
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// mp4FragmentBudget is the number of moof boxes read below ParseSpeed 1 before the rest
+// of the file is extrapolated from sidx, mfra or mehd.
+const mp4FragmentBudget = 1000
+
+// maxMoofSize bounds the moof payload read; real fragments are a few KiB.
+const maxMoofSize = int64(4 << 20)
+
+// mp4TrackDefaults are the trex sample defaults of a track, overridden per fragment by
+// tfhd and per sample by trun.
+type mp4TrackDefaults struct {
+	duration uint32
+	size     uint32
+	flags    uint32
+}
+
+// mp4FragmentTrack accumulates the samples of one track over all fragments.
+type mp4FragmentTrack struct {
+	samples     uint64
+	bytes       uint64
+	duration    uint64
+	head        []uint32
+	tail        []uint32
+	firstDelta  uint32
+	lastDelta   uint32
+	variable    bool
+	firstOffset uint64
+	// syncSamples counts sync samples; syncInterval is the constant distance between
+	// them, or -1 once it varies.
+	syncSamples  uint64
+	syncInterval int64
+	lastSync     uint64
+}
+
+// mp4Fragments is what ParseMP4 collects after moov in a fragmented file.
+type mp4Fragments struct {
+	tracks map[uint32]*mp4FragmentTrack
+	// truncated is set when the fragment budget stopped the walk.
+	truncated bool
+	// sidxDuration and tfraTime estimate the full track durations, in track timescale,
+	// when the walk is truncated.
+	sidxDuration map[uint32]uint64
+	tfraTime     map[uint32]uint64
+}
+
+// parseMvex reads the trex defaults and the mehd fragment duration (in movie timescale).
+func parseMvex(buf []byte) (map[uint32]mp4TrackDefaults, uint64) {
+	defaults := map[uint32]mp4TrackDefaults{}
+	var fragmentDuration uint64
+	var offset int64
+	for offset+8 <= int64(len(buf)) {
+		boxSize, boxType, headerSize := readMP4BoxHeaderFrom(buf, offset)
+		if boxSize <= 0 {
+			break
+		}
+		payload := sliceBox(buf, offset+headerSize, boxSize-headerSize)
+		switch boxType {
+		case "trex":
+			if len(payload) >= 24 {
+				defaults[binary.BigEndian.Uint32(payload[4:8])] = mp4TrackDefaults{
+					duration: binary.BigEndian.Uint32(payload[12:16]),
+					size:     binary.BigEndian.Uint32(payload[16:20]),
+					flags:    binary.BigEndian.Uint32(payload[20:24]),
+				}
+			}
+		case "mehd":
+			if len(payload) >= 12 && payload[0] == 1 {
+				fragmentDuration = binary.BigEndian.Uint64(payload[4:12])
+			} else if len(payload) >= 8 {
+				fragmentDuration = uint64(binary.BigEndian.Uint32(payload[4:8]))
+			}
+		}
+		offset += boxSize
+	}
+	return defaults, fragmentDuration
+}
+
+// parseMoof adds the samples of every traf in a moof to frags. moofOffset is the file
+// offset of the moof box, the default base of sample data offsets.
+func (frags *mp4Fragments) parseMoof(buf []byte, moofOffset int64, defaults map[uint32]mp4TrackDefaults) {
+	var offset int64
+	for offset+8 <= int64(len(buf)) {
+		boxSize, boxType, headerSize := readMP4BoxHeaderFrom(buf, offset)
+		if boxSize <= 0 {
+			break
+		}
+		if boxType == "traf" {
+			frags.parseTraf(sliceBox(buf, offset+headerSize, boxSize-headerSize), moofOffset, defaults)
+		}
+		offset += boxSize
+	}
+}
+
+func (frags *mp4Fragments) parseTraf(buf []byte, moofOffset int64, defaults map[uint32]mp4TrackDefaults) {
+	var (
+		track    *mp4FragmentTrack
+		def      mp4TrackDefaults
+		baseData = uint64(moofOffset)
+	)
+	var offset int64
+	for offset+8 <= int64(len(buf)) {
+		boxSize, boxType, headerSize := readMP4BoxHeaderFrom(buf, offset)
+		if boxSize <= 0 {
+			break
+		}
+		payload := sliceBox(buf, offset+headerSize, boxSize-headerSize)
+		switch boxType {
+		case "tfhd":
+			if len(payload) < 8 {
+				return
+			}
+			flags := uint32(payload[1])<<16 | uint32(payload[2])<<8 | uint32(payload[3])
+			id := binary.BigEndian.Uint32(payload[4:8])
+			def = defaults[id]
+			pos := 8
+			read32 := func() uint32 {
+				if pos+4 > len(payload) {
+					return 0
+				}
+				v := binary.BigEndian.Uint32(payload[pos : pos+4])
+				pos += 4
+				return v
+			}
+			if flags&0x000001 != 0 && pos+8 <= len(payload) {
+				baseData = binary.BigEndian.Uint64(payload[pos : pos+8])
+				pos += 8
+			}
+			if flags&0x000002 != 0 {
+				read32() // sample_description_index
+			}
+			if flags&0x000008 != 0 {
+				def.duration = read32()
+			}
+			if flags&0x000010 != 0 {
+				def.size = read32()
+			}
+			if flags&0x000020 != 0 {
+				def.flags = read32()
+			}
+			if frags.tracks[id] == nil {
+				frags.tracks[id] = &mp4FragmentTrack{}
+			}
+			track = frags.tracks[id]
+		case "trun":
+			if track != nil {
+				track.addTrun(payload, def, baseData)
+			}
+		}
+		offset += boxSize
+	}
+}
+
+// addTrun adds the samples of a trun box.
+func (t *mp4FragmentTrack) addTrun(payload []byte, def mp4TrackDefaults, baseData uint64) {
+	if len(payload) < 8 {
+		return
+	}
+	flags := uint32(payload[1])<<16 | uint32(payload[2])<<8 | uint32(payload[3])
+	count := binary.BigEndian.Uint32(payload[4:8])
+	pos := 8
+	if flags&0x000001 != 0 {
+		if pos+4 > len(payload) {
+			return
+		}
+		dataOffset := int32(binary.BigEndian.Uint32(payload[pos : pos+4]))
+		if t.samples == 0 && count > 0 {
+			t.firstOffset = uint64(int64(baseData) + int64(dataOffset))
+		}
+		pos += 4
+	}
+	firstFlags, hasFirstFlags := uint32(0), false
+	if flags&0x000004 != 0 {
+		if pos+4 > len(payload) {
+			return
+		}
+		firstFlags, hasFirstFlags = binary.BigEndian.Uint32(payload[pos:pos+4]), true
+		pos += 4
+	}
+	entrySize := 0
+	for _, bit := range []uint32{0x000100, 0x000200, 0x000400, 0x000800} {
+		if flags&bit != 0 {
+			entrySize += 4
+		}
+	}
+	for i := uint32(0); i < count; i++ {
+		if pos+entrySize > len(payload) {
+			return
+		}
+		duration, size, sampleFlags := def.duration, def.size, def.flags
+		if flags&0x000100 != 0 {
+			duration = binary.BigEndian.Uint32(payload[pos : pos+4])
+			pos += 4
+		}
+		if flags&0x000200 != 0 {
+			size = binary.BigEndian.Uint32(payload[pos : pos+4])
+			pos += 4
+		}
+		if flags&0x000400 != 0 {
+			sampleFlags = binary.BigEndian.Uint32(payload[pos : pos+4])
+			pos += 4
+		} else if i == 0 && hasFirstFlags {
+			sampleFlags = firstFlags
+		}
+		if flags&0x000800 != 0 {
+			pos += 4 // sample_composition_time_offset
+		}
+		t.addSample(duration, size, sampleFlags&0x00010000 == 0)
+	}
+}
+
+func (t *mp4FragmentTrack) addSample(duration, size uint32, sync bool) {
+	if t.samples == 0 {
+		t.firstDelta = duration
+	} else if duration != t.lastDelta {
+		t.variable = true
+	}
+	t.lastDelta = duration
+	if len(t.head) < mp4SampleSizeHeadMax {
+		t.head = append(t.head, size)
+	}
+	if len(t.tail) == mp4SampleSizeTailMax {
+		t.tail = t.tail[1:]
+	}
+	t.tail = append(t.tail, size)
+	if sync {
+		if t.syncSamples > 0 && t.syncInterval >= 0 {
+			interval := int64(t.samples - t.lastSync)
+			switch t.syncInterval {
+			case 0:
+				t.syncInterval = interval
+			case interval:
+			default:
+				t.syncInterval = -1
+			}
+		}
+		t.syncSamples++
+		t.lastSync = t.samples
+	}
+	t.samples++
+	t.bytes += uint64(size)
+	t.duration += uint64(duration)
+}
+
+// parseSidx adds the subsegment durations of a sidx box to the track it references.
+func (frags *mp4Fragments) parseSidx(payload []byte, timescales map[uint32]uint32) {
+	if len(payload) < 12 {
+		return
+	}
+	version := payload[0]
+	id := binary.BigEndian.Uint32(payload[4:8])
+	timescale := binary.BigEndian.Uint32(payload[8:12])
+	pos := 12 + 8 // earliest_presentation_time and first_offset
+	if version == 1 {
+		pos = 12 + 16
+	}
+	if pos+4 > len(payload) || timescale == 0 {
+		return
+	}
+	count := int(binary.BigEndian.Uint16(payload[pos+2 : pos+4]))
+	pos += 4
+	var total uint64
+	for i := 0; i < count && pos+12 <= len(payload); i++ {
+		// Hierarchical indexes reference other sidx boxes; their durations repeat.
+		if payload[pos]&0x80 == 0 {
+			total += uint64(binary.BigEndian.Uint32(payload[pos+4 : pos+8]))
+		}
+		pos += 12
+	}
+	if trackScale := timescales[id]; trackScale > 0 && trackScale != timescale {
+		total = total * uint64(trackScale) / uint64(timescale)
+	}
+	frags.sidxDuration[id] += total
+}
+
+// parseMfra reads the time of the last random access point of each track from the tfra
+// boxes of an mfra box.
+func (frags *mp4Fragments) parseMfra(buf []byte) {
+	var offset int64
+	for offset+8 <= int64(len(buf)) {
+		boxSize, boxType, headerSize := readMP4BoxHeaderFrom(buf, offset)
+		if boxSize <= 0 {
+			break
+		}
+		payload := sliceBox(buf, offset+headerSize, boxSize-headerSize)
+		if boxType == "tfra" && len(payload) >= 16 {
+			version := payload[0]
+			id := binary.BigEndian.Uint32(payload[4:8])
+			lengths := binary.BigEndian.Uint32(payload[8:12])
+			count := int(binary.BigEndian.Uint32(payload[12:16]))
+			entrySize := 8
+			if version == 1 {
+				entrySize = 16
+			}
+			entrySize += int(lengths>>4&3+1) + int(lengths>>2&3+1) + int(lengths&3+1)
+			if last := 16 + (count-1)*entrySize; count > 0 && last+entrySize <= len(payload) {
+				if version == 1 {
+					frags.tfraTime[id] = binary.BigEndian.Uint64(payload[last : last+8])
+				} else {
+					frags.tfraTime[id] = uint64(binary.BigEndian.Uint32(payload[last : last+4]))
+				}
+			}
+		}
+		offset += boxSize
+	}
+}
+
+// readMP4Mfra locates the mfra box through the mfro box that ends the file.
+func readMP4Mfra(r io.ReaderAt, size int64) []byte {
+	if size < 16 {
+		return nil
+	}
+	var mfro [16]byte
+	if _, err := r.ReadAt(mfro[:], size-16); err != nil || string(mfro[4:8]) != "mfro" {
+		return nil
+	}
+	mfraSize := int64(binary.BigEndian.Uint32(mfro[12:16]))
+	if mfraSize < 16 || mfraSize > size || mfraSize > maxMoofSize {
+		return nil
+	}
+	buf := make([]byte, mfraSize)
+	if _, err := r.ReadAt(buf, size-mfraSize); err != nil || string(buf[4:8]) != "mfra" {
+		return nil
+	}
+	return buf[8:]
+}
+
+// applyMP4Fragments fills the sample statistics of the tracks of a fragmented file from
+// its fragments. Tracks keep moov values the fragments do not replace. When the walk
+// was truncated, counts and sizes are scaled to the estimated full duration.
+func applyMP4Fragments(info *MP4Info, frags *mp4Fragments, fragmentDuration uint64) {
+	longest := 0.0
+	for i := range info.Tracks {
+		track := &info.Tracks[i]
+		stats := frags.tracks[track.ID]
+		if stats == nil || stats.samples == 0 || track.Timescale == 0 {
+			continue
+		}
+		samples, bytes, duration := stats.samples, stats.bytes, stats.duration
+		if frags.truncated && duration > 0 {
+			full := frags.sidxDuration[track.ID]
+			if full == 0 && fragmentDuration > 0 && info.MovieTimescale > 0 {
+				full = fragmentDuration * uint64(track.Timescale) / uint64(info.MovieTimescale)
+			}
+			if full == 0 {
+				if last := frags.tfraTime[track.ID]; last > 0 {
+					full = last + uint64(stats.lastDelta)
+				}
+			}
+			if full > duration {
+				scale := float64(full) / float64(duration)
+				samples = uint64(float64(samples)*scale + 0.5)
+				bytes = uint64(float64(bytes)*scale + 0.5)
+				duration = full
+			}
+		}
+		track.SampleCount = samples
+		track.SampleBytes = bytes
+		track.SampleSizeHead = stats.head
+		track.SampleSizeTail = stats.tail
+		track.SampleDelta = stats.firstDelta
+		track.LastSampleDelta = stats.lastDelta
+		track.VariableDeltas = stats.variable
+		if stats.firstOffset > 0 {
+			track.FirstChunkOff = stats.firstOffset
+		}
+		if stats.syncSamples > 1 && stats.syncSamples < stats.samples && stats.syncInterval > 0 {
+			track.SyncInterval = stats.syncInterval
+		}
+		track.DurationSeconds = float64(duration) / float64(track.Timescale)
+		longest = max(longest, track.DurationSeconds)
+	}
+	if fragmentDuration > 0 && info.MovieTimescale > 0 {
+		info.Container.DurationSeconds = float64(fragmentDuration) / float64(info.MovieTimescale)
+	} else if longest > info.Container.DurationSeconds {
+		info.Container.DurationSeconds = longest
+	}
+}
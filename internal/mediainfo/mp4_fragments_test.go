@@ -0,0 +1,134 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// buildFragmentedMP4 writes an fMP4 with one 90 kHz video track (ID 1): an empty moov
+// with trex defaults of 3000 ticks per sample, then fragments of samplesPerFragment
+// 1000-byte samples with a sync sample every 10 samples. mehd is written, in the 90 kHz
+// movie timescale, when fragmentDuration is set.
+func buildFragmentedMP4(fragments, samplesPerFragment int, fragmentDuration uint32) []byte {
+	var buf bytes.Buffer
+	writeMP4Box(&buf, "ftyp", []byte("iso6\x00\x00\x00\x00iso6cmfc"))
+
+	mvhd := make([]byte, 20)
+	binary.BigEndian.PutUint32(mvhd[12:16], 90000)
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[12:16], 1)
+	var trak bytes.Buffer
+	writeMP4Box(&trak, "tkhd", tkhd)
+	trak.Write(buildTrackWithStsd("vide", "avc1"))
+	var mvex bytes.Buffer
+	if fragmentDuration > 0 {
+		writeMP4Box(&mvex, "mehd", binary.BigEndian.AppendUint32(make([]byte, 4), fragmentDuration))
+	}
+	trex := make([]byte, 24)
+	binary.BigEndian.PutUint32(trex[4:8], 1)
+	binary.BigEndian.PutUint32(trex[8:12], 1)
+	binary.BigEndian.PutUint32(trex[12:16], 3000)
+	writeMP4Box(&mvex, "trex", trex)
+	var moov bytes.Buffer
+	writeMP4Box(&moov, "mvhd", mvhd)
+	writeMP4Box(&moov, "trak", trak.Bytes())
+	writeMP4Box(&moov, "mvex", mvex.Bytes())
+	writeMP4Box(&buf, "moov", moov.Bytes())
+
+	for i := 0; i < fragments; i++ {
+		tfhd := []byte{0, 0x02, 0, 0, 0, 0, 0, 1} // default-base-is-moof
+		tfdt := binary.BigEndian.AppendUint32([]byte{0, 0, 0, 0}, uint32(i*samplesPerFragment*3000))
+		trun := []byte{0, 0, 0x06, 0x01} // data offset, sample sizes and flags
+		trun = binary.BigEndian.AppendUint32(trun, uint32(samplesPerFragment))
+		trun = binary.BigEndian.AppendUint32(trun, 0) // patched below
+		for s := 0; s < samplesPerFragment; s++ {
+			flags := uint32(0x00010000)
+			if (i*samplesPerFragment+s)%10 == 0 {
+				flags = 0x02000000
+			}
+			trun = binary.BigEndian.AppendUint32(trun, 1000)
+			trun = binary.BigEndian.AppendUint32(trun, flags)
+		}
+		var traf bytes.Buffer
+		writeMP4Box(&traf, "tfhd", tfhd)
+		writeMP4Box(&traf, "tfdt", tfdt)
+		writeMP4Box(&traf, "trun", trun)
+		var moof bytes.Buffer
+		writeMP4Box(&moof, "mfhd", binary.BigEndian.AppendUint32(make([]byte, 4), uint32(i+1)))
+		writeMP4Box(&moof, "traf", traf.Bytes())
+		// The samples start right after the moof and the mdat header.
+		body := moof.Bytes()
+		dataOffset := uint32(8 + len(body) + 8)
+		at := bytes.Index(body, []byte("trun")) + 4 + 8
+		binary.BigEndian.PutUint32(body[at:at+4], dataOffset)
+		writeMP4Box(&buf, "moof", body)
+		writeMP4Box(&buf, "mdat", make([]byte, samplesPerFragment*1000))
+	}
+	return buf.Bytes()
+}
+
+func TestParseMP4Fragments(t *testing.T) {
+	data := buildFragmentedMP4(3, 30, 0)
+	info, ok := ParseMP4(bytes.NewReader(data), int64(len(data)))
+	if !ok {
+		t.Fatal("expected mp4 info")
+	}
+	if !info.Fragmented || len(info.Tracks) != 1 {
+		t.Fatalf("fragmented=%v tracks=%d", info.Fragmented, len(info.Tracks))
+	}
+	track := info.Tracks[0]
+	if track.SampleCount != 90 || track.SampleBytes != 90000 || track.DurationSeconds != 3 || track.SyncInterval != 10 {
+		t.Fatalf("track: samples=%d bytes=%d duration=%v sync=%d", track.SampleCount, track.SampleBytes, track.DurationSeconds, track.SyncInterval)
+	}
+	if info.Container.DurationSeconds != 3 {
+		t.Fatalf("container duration=%v", info.Container.DurationSeconds)
+	}
+	if want := uint64(bytes.Index(data, []byte("mdat")) + 4); track.FirstChunkOff != want {
+		t.Fatalf("first chunk offset=%d, want %d", track.FirstChunkOff, want)
+	}
+}
+
+func TestParseMP4FragmentBudget(t *testing.T) {
+	// The last fragment is past the budget; mehd gives the full duration.
+	fragments := mp4FragmentBudget + 1
+	data := buildFragmentedMP4(fragments, 2, uint32(fragments*2*3000))
+	full, ok := parseMP4(bytes.NewReader(data), int64(len(data)), 1)
+	if !ok {
+		t.Fatal("expected mp4 info")
+	}
+	budgeted, ok := parseMP4(bytes.NewReader(data), int64(len(data)), 0.5)
+	if !ok {
+		t.Fatal("expected mp4 info")
+	}
+	for _, info := range []MP4Info{full, budgeted} {
+		if track := info.Tracks[0]; track.SampleCount != uint64(fragments*2) || track.SampleBytes != uint64(fragments*2000) {
+			t.Fatalf("samples=%d bytes=%d", track.SampleCount, track.SampleBytes)
+		}
+	}
+}
+
+func TestAnalyzeFragmentedMP4(t *testing.T) {
+	data := buildFragmentedMP4(3, 30, 0)
+	report, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), "cmaf.mp4", defaultAnalyzeOptions())
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	if report.General.General == nil || !report.General.General.Fragmented {
+		t.Fatalf("expected Fragmented general flag")
+	}
+	text := RenderText([]Report{report})
+	for _, want := range []string{
+		"Duration                                 : 3 s 0 ms\n",
+		"Format settings, GOP                     : N=10\n",
+		"Frame rate                               : 30.000 FPS\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %q in:\n%s", want, text)
+		}
+	}
+	if jsonOut := RenderJSON([]Report{report}); !strings.Contains(jsonOut, `"IsFragmented":"Yes"`) || !strings.Contains(jsonOut, `"FrameCount":"90"`) {
+		t.Errorf("missing fragment fields in %s", jsonOut)
+	}
+}
@@ -2,6 +2,7 @@ package mediainfo
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"math"
@@ -17,10 +18,10 @@ func psStreamKey(id, subID byte) uint16 {
 }
 
 func ParseMPEGPS(file io.ReadSeeker, size int64) (ContainerInfo, []Stream, bool) {
-	return ParseMPEGPSWithOptions(file, size, mpegPSOptions{})
+	return ParseMPEGPSWithOptions(context.Background(), file, size, mpegPSOptions{})
 }
 
-func ParseMPEGPSWithOptions(file io.ReadSeeker, size int64, opts mpegPSOptions) (ContainerInfo, []Stream, bool) {
+func ParseMPEGPSWithOptions(ctx context.Context, file io.ReadSeeker, size int64, opts mpegPSOptions) (ContainerInfo, []Stream, bool) {
 	parseSpeed := opts.parseSpeed
 	if parseSpeed == 0 {
 		parseSpeed = 1
@@ -30,7 +31,7 @@ func ParseMPEGPSWithOptions(file io.ReadSeeker, size int64, opts mpegPSOptions)
 			parser := newPSStreamParser(opts)
 			reader := func(r io.Reader) bool {
 				buf := bufio.NewReaderSize(r, 1<<20)
-				return parser.parseReader(buf)
+				return parser.parseReader(ctx, buf)
 			}
 			sampleSize := int64(8 << 20)
 			if parseSpeed > 0 && parseSpeed < 1 {
@@ -77,6 +78,9 @@ func ParseMPEGPSWithOptions(file io.ReadSeeker, size int64, opts mpegPSOptions)
 					}
 				}
 			}
+			if ctx.Err() != nil {
+				return ContainerInfo{}, nil, false
+			}
 			if parsedAny {
 				return finalizeMPEGPS(parser.streams, parser.streamOrder, parser.videoParsers, parser.videoPTS, parser.anyPTS, size, opts2)
 			}
@@ -89,7 +93,7 @@ func ParseMPEGPSWithOptions(file io.ReadSeeker, size int64, opts mpegPSOptions)
 
 	reader := bufio.NewReaderSize(file, 1<<20)
 	parser := newPSStreamParser(opts)
-	if !parser.parseReader(reader) {
+	if !parser.parseReader(ctx, reader) || ctx.Err() != nil {
 		return ContainerInfo{}, nil, false
 	}
 	return finalizeMPEGPS(parser.streams, parser.streamOrder, parser.videoParsers, parser.videoPTS, parser.anyPTS, size, opts)
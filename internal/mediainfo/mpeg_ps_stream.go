@@ -3,6 +3,7 @@ package mediainfo
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/binary"
 	"io"
 	"os"
@@ -44,7 +45,7 @@ func newPSStreamParser(opts mpegPSOptions) *psStreamParser {
 	}
 }
 
-func (p *psStreamParser) parseReader(r io.Reader) bool {
+func (p *psStreamParser) parseReader(ctx context.Context, r io.Reader) bool {
 	const chunkSize = 1 << 20
 	buf := make([]byte, 0, chunkSize*2)
 	tmp := make([]byte, chunkSize)
@@ -57,6 +58,10 @@ func (p *psStreamParser) parseReader(r io.Reader) bool {
 		if eof {
 			return false
 		}
+		if ctx.Err() != nil {
+			eof = true
+			return false
+		}
 		for {
 			n, err := r.Read(tmp)
 			if n > 0 {
@@ -441,7 +446,7 @@ func findPESStart(data []byte, start int) int {
 	return -1
 }
 
-func ParseMPEGPSFiles(paths []string, size int64, opts mpegPSOptions) (ContainerInfo, []Stream, bool) {
+func ParseMPEGPSFiles(ctx context.Context, paths []string, size int64, opts mpegPSOptions) (ContainerInfo, []Stream, bool) {
 	if len(paths) == 0 {
 		return ContainerInfo{}, nil, false
 	}
@@ -452,10 +457,13 @@ func ParseMPEGPSFiles(paths []string, size int64, opts mpegPSOptions) (Container
 		if err != nil {
 			return ContainerInfo{}, nil, false
 		}
-		if parseMPEGPSFileSample(parser, file, opts) {
+		if parseMPEGPSFileSample(ctx, parser, file, opts) {
 			parsedAny = true
 		}
 		_ = file.Close()
+		if ctx.Err() != nil {
+			return ContainerInfo{}, nil, false
+		}
 	}
 	if !parsedAny {
 		return ContainerInfo{}, nil, false
@@ -465,7 +473,7 @@ func ParseMPEGPSFiles(paths []string, size int64, opts mpegPSOptions) (Container
 	return finalizeMPEGPS(parser.streams, parser.streamOrder, parser.videoParsers, parser.videoPTS, parser.anyPTS, size, opts2)
 }
 
-func parseMPEGPSFileSample(parser *psStreamParser, file *os.File, opts mpegPSOptions) bool {
+func parseMPEGPSFileSample(ctx context.Context, parser *psStreamParser, file *os.File, opts mpegPSOptions) bool {
 	info, err := file.Stat()
 	if err != nil {
 		return false
@@ -476,7 +484,7 @@ func parseMPEGPSFileSample(parser *psStreamParser, file *os.File, opts mpegPSOpt
 	}
 	reader := func(r io.Reader) bool {
 		buf := bufio.NewReaderSize(r, 1<<20)
-		return parser.parseReader(buf)
+		return parser.parseReader(ctx, buf)
 	}
 
 	parseSpeed := opts.parseSpeed
@@ -3,6 +3,7 @@ package mediainfo
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -54,7 +55,7 @@ type tsStream struct {
 	// Access Unit Delimiter-aware GOP scan state.
 	h264GOPSeenAUD   bool
 	h264GOPNeedSlice bool
-	// HEVC SPS/HDR info for TS/BDAV streams.
+	// HEVC and VVC SPS/HDR info for TS/BDAV streams.
 	hevcSPS          h264SPSInfo
 	hasHEVCSPS       bool
 	hevcHDR          hevcHDRInfo
@@ -192,13 +193,13 @@ type mpeg2UserDataPacket struct {
 	data              []byte
 }
 
-func ParseMPEGTS(file io.ReadSeeker, size int64, parseSpeed float64) (ContainerInfo, []Stream, []Field, bool) {
-	return parseMPEGTSWithPacketSize(file, size, 188, parseSpeed)
+func ParseMPEGTS(ctx context.Context, file io.ReadSeeker, size int64, parseSpeed float64) (ContainerInfo, []Stream, []Field, bool) {
+	return parseMPEGTSWithPacketSize(ctx, file, size, 188, parseSpeed)
 }
 
 // ParseBDAV parses BDAV/M2TS streams (192-byte packets: 4-byte timestamp + 188-byte TS packet).
-func ParseBDAV(file io.ReadSeeker, size int64, parseSpeed float64) (ContainerInfo, []Stream, []Field, bool) {
-	return parseMPEGTSWithPacketSize(file, size, 192, parseSpeed)
+func ParseBDAV(ctx context.Context, file io.ReadSeeker, size int64, parseSpeed float64) (ContainerInfo, []Stream, []Field, bool) {
+	return parseMPEGTSWithPacketSize(ctx, file, size, 192, parseSpeed)
 }
 
 const tsPTSGap = 30 * 90000 // 30 seconds
@@ -314,7 +315,7 @@ func findTSSyncOffset(file io.ReadSeeker, packetSize int64, tsOffset int64, size
 	return 0, false
 }
 
-func parseMPEGTSWithPacketSize(file io.ReadSeeker, size int64, packetSize int64, parseSpeed float64) (ContainerInfo, []Stream, []Field, bool) {
+func parseMPEGTSWithPacketSize(ctx context.Context, file io.ReadSeeker, size int64, packetSize int64, parseSpeed float64) (ContainerInfo, []Stream, []Field, bool) {
 	if _, err := file.Seek(0, io.SeekStart); err != nil {
 		return ContainerInfo{}, nil, nil, false
 	}
@@ -456,6 +457,9 @@ func parseMPEGTSWithPacketSize(file io.ReadSeeker, size int64, packetSize int64,
 		var packetIndex int64
 		carry := 0
 		for {
+			if ctx.Err() != nil {
+				return false
+			}
 			n, err := reader.Read(buf[carry:])
 			if n == 0 && err != nil {
 				break
@@ -790,7 +794,7 @@ func parseMPEGTSWithPacketSize(file io.ReadSeeker, size int64, packetSize int64,
 					} else {
 						pidPayloadBytes[pid] += int64(len(data))
 					}
-					if entry.kind == StreamVideo && (entry.format == "AVC" || entry.format == "HEVC") && len(data) > 0 {
+					if entry.kind == StreamVideo && (entry.format == "AVC" || entry.format == "HEVC" || entry.format == "VVC") && len(data) > 0 {
 						const maxPES = 512 * 1024
 						if len(data) > maxPES {
 							data = data[:maxPES]
@@ -870,7 +874,7 @@ func parseMPEGTSWithPacketSize(file io.ReadSeeker, size int64, packetSize int64,
 					entry.videoStarted = true
 				}
 
-				if entry.kind == StreamVideo && (entry.format == "AVC" || entry.format == "HEVC") && len(entry.pesData) > 0 {
+				if entry.kind == StreamVideo && (entry.format == "AVC" || entry.format == "HEVC" || entry.format == "VVC") && len(entry.pesData) > 0 {
 					const maxPES = 512 * 1024
 					if len(entry.pesData) < maxPES {
 						remaining := maxPES - len(entry.pesData)
@@ -1413,6 +1417,9 @@ func parseMPEGTSWithPacketSize(file io.ReadSeeker, size int64, packetSize int64,
 			if st.hasVideoFields {
 				fields = append(fields, st.videoFields...)
 			}
+			if st.format == "VVC" && st.hasHEVCSPS {
+				fields = append(fields, buildVVCFields(st.hevcSPS, st.hevcHDR, jsonExtras)...)
+			}
 			if st.streamType != 0 {
 				fields = append(fields, Field{Name: "Codec ID", Value: formatTSCodecID(st.streamType)})
 			}
@@ -2693,6 +2700,8 @@ func mapTSStream(streamType byte, formatID uint32) (StreamKind, string) {
 		return StreamVideo, "AVC"
 	case 0x24:
 		return StreamVideo, "HEVC"
+	case 0x33:
+		return StreamVideo, "VVC"
 	case 0xEA:
 		return StreamVideo, "VC-1"
 	case 0x03:
@@ -2789,6 +2798,17 @@ func processPES(entry *tsStream) {
 			}
 		}
 	}
+	if entry.kind == StreamVideo && entry.format == "VVC" && len(entry.pesData) > 0 {
+		if sps, ok := parseVVCAnnexBMeta(entry.pesData, &entry.hevcHDR); ok && !entry.hasHEVCSPS {
+			entry.hevcSPS = sps
+			entry.hasHEVCSPS = true
+			entry.width = sps.Width
+			entry.height = sps.Height
+			if sps.FrameRate > 0 {
+				entry.videoFrameRate = sps.FrameRate
+			}
+		}
+	}
 	if entry.kind == StreamVideo && entry.format == "VC-1" && !entry.vc1Parsed && len(entry.pesData) > 0 {
 		if meta, ok := parseVC1AnnexBMeta(entry.pesData); ok {
 			entry.vc1Parsed = true
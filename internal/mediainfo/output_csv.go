@@ -8,6 +8,10 @@ import (
 func RenderCSV(reports []Report) string {
 	var buf bytes.Buffer
 	for _, report := range reports {
+		if report.Err != nil {
+			writeCSVTrack(&buf, string(StreamGeneral), failedStream(report))
+			continue
+		}
 		writeCSVTrack(&buf, string(report.General.Kind), report.General)
 		forEachStreamWithKindIndex(report.Streams, func(stream Stream, index, total, _ int) {
 			title := csvStreamTitle(stream.Kind, index, total)
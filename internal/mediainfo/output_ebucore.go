@@ -1,6 +1,367 @@
 package mediainfo
 
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	ebuCoreNS             = "urn:ebu:metadata-schema:ebucore"
+	ebuCoreSchemaLocation = "https://www.ebu.ch/metadata/schemas/EBUCore/20171009/ebucore.xsd"
+	ebuCoreVersion        = "1.8"
+)
+
+// RenderEBUCore renders reports as an EBUCore 1.8 document with one ebucore:format per file.
 func RenderEBUCore(reports []Report) string {
-	_ = reports
-	return "<ebuCoreMain></ebuCoreMain>"
+	var buf bytes.Buffer
+	buf.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	buf.WriteString("<!-- Generated by " + AppName + " " + FormatVersion(AppVersion) + " -->\n")
+	buildEBUCore(reports).writeXML(&buf, 0)
+	return buf.String()
+}
+
+// RenderEBUCoreJSON renders the EBUCore document of RenderEBUCore as JSON.
+func RenderEBUCoreJSON(reports []Report) string {
+	return buildEBUCore(reports).renderJSON() + "\n"
+}
+
+func buildEBUCore(reports []Report) *xmlNode {
+	root := newXMLNode("ebucore:ebuCoreMain",
+		xmlAttr{name: "xmlns:dc", value: "http://purl.org/dc/elements/1.1/"},
+		xmlAttr{name: "xmlns:ebucore", value: ebuCoreNS},
+		xmlAttr{name: "xmlns:xsi", value: "http://www.w3.org/2001/XMLSchema-instance"},
+		xmlAttr{name: "xsi:schemaLocation", value: ebuCoreNS + " " + ebuCoreSchemaLocation},
+		xmlAttr{name: "version", value: ebuCoreVersion},
+	)
+	core := root.add(newXMLNode("ebucore:coreMetadata"))
+	for _, report := range reports {
+		core.add(buildEBUCoreFormat(report))
+	}
+	return root
+}
+
+func buildEBUCoreFormat(report Report) *xmlNode {
+	format := newXMLNode("ebucore:format")
+	if report.Err != nil {
+		addEBUCoreFileNames(format, report.Ref)
+		addEBUCoreAttributes(format, ebuCoreAttributes{strings: []xmlAttr{{name: "Error", value: report.Err.Error()}}})
+		return format
+	}
+
+	general := typedFieldsFromJSON(buildJSONGeneralFields(report))
+	containerFormat := findField(report.General.Fields, "Format")
+	var videos, audios, datas []*xmlNode
+	forEachStreamWithKindIndex(orderTracks(report.Streams), func(stream Stream, _, _, order int) {
+		fields := typedFieldsFromJSON(buildJSONStreamFields(stream, order, 0, containerFormat))
+		switch stream.Kind {
+		case StreamVideo:
+			videos = append(videos, buildEBUCoreVideo(stream, fields))
+		case StreamAudio:
+			audios = append(audios, buildEBUCoreAudio(fields))
+		case StreamText:
+			datas = append(datas, buildEBUCoreData(fields))
+		}
+	})
+	format.children = append(format.children, videos...)
+	format.children = append(format.children, audios...)
+
+	container := format.add(newXMLNode("ebucore:containerFormat"))
+	container.attr("containerFormatName", general["Format"])
+	if general["Format"] != "" {
+		container.add(newXMLNode("ebucore:containerEncoding", xmlAttr{name: "formatLabel", value: general["Format"]}))
+	}
+	addEBUCoreAttributes(container, ebuCoreAttributes{strings: nonEmptyAttrs(
+		xmlAttr{name: "FormatProfile", value: general["Format_Profile"]},
+		xmlAttr{name: "FormatVersion", value: general["Format_Version"]},
+		xmlAttr{name: "CodecID", value: general["CodecID"]},
+	)})
+
+	format.children = append(format.children, datas...)
+
+	if duration := general["Duration"]; duration != "" {
+		format.add(newXMLNode("ebucore:duration")).addText("ebucore:normalPlayTime", "PT"+duration+"S")
+	}
+	format.addText("ebucore:fileSize", general["FileSize"])
+	addEBUCoreFileNames(format, report.Ref)
+	addEBUCoreAttributes(format, ebuCoreAttributes{
+		strings: nonEmptyAttrs(
+			xmlAttr{name: "WritingApplication", value: general["Encoded_Application"]},
+			xmlAttr{name: "WritingLibrary", value: general["Encoded_Library"]},
+		),
+		integers: nonEmptyUnitAttrs(
+			ebuCoreUnitAttr{name: "OverallBitRate", unit: "bps", value: general["OverallBitRate"]},
+		),
+	})
+	if report.Ref != "" && !report.fromReader {
+		if info, err := os.Stat(report.Ref); err == nil {
+			mod := info.ModTime().UTC()
+			format.add(newXMLNode("ebucore:dateModified",
+				xmlAttr{name: "startDate", value: mod.Format("2006-01-02")},
+				xmlAttr{name: "startTime", value: mod.Format("15:04:05Z")},
+			))
+		}
+	}
+	return format
+}
+
+func addEBUCoreFileNames(format *xmlNode, ref string) {
+	if ref == "" {
+		return
+	}
+	format.addText("ebucore:fileName", filepath.Base(ref))
+	format.addText("ebucore:locator", ref)
+}
+
+func buildEBUCoreVideo(stream Stream, fields typedFields) *xmlNode {
+	video := newXMLNode("ebucore:videoFormat")
+	video.attr("videoFormatName", fields["Format"])
+	video.addText("ebucore:width", fields["Width"], xmlAttr{name: "unit", value: "pixel"})
+	video.addText("ebucore:height", fields["Height"], xmlAttr{name: "unit", value: "pixel"})
+
+	var rate big.Rat
+	fields.frameRate(&rate)
+	if rate.Sign() > 0 {
+		video.add(ebuCoreFrameRate(&rate))
+	}
+	if num, den, ok := ebuCoreAspectRatio(findField(stream.Fields, "Display aspect ratio"), fields.float("DisplayAspectRatio")); ok {
+		aspect := video.add(newXMLNode("ebucore:aspectRatio", xmlAttr{name: "typeLabel", value: "display"}))
+		aspect.addText("ebucore:factorNumerator", strconv.Itoa(num))
+		aspect.addText("ebucore:factorDenominator", strconv.Itoa(den))
+	}
+	if encoding := profileAtLevel(fields["Format_Profile"], fields["Format_Level"]); encoding != "" {
+		video.add(newXMLNode("ebucore:videoEncoding", xmlAttr{name: "typeLabel", value: encoding}))
+	}
+	addEBUCoreCodec(video, fields["CodecID"])
+	addEBUCoreBitRate(video, fields)
+	switch fields["ScanType"] {
+	case "":
+	case "Progressive":
+		video.addText("ebucore:scanningFormat", "progressive")
+	default:
+		video.addText("ebucore:scanningFormat", "interlaced")
+	}
+	switch fields["ScanOrder"] {
+	case "TFF":
+		video.addText("ebucore:scanningOrder", "top")
+	case "BFF":
+		video.addText("ebucore:scanningOrder", "bottom")
+	}
+	addEBUCoreTrack(video, "ebucore:videoTrack", fields, false)
+	addEBUCoreAttributes(video, ebuCoreAttributes{
+		strings: nonEmptyAttrs(
+			xmlAttr{name: "ColorSpace", value: fields["ColorSpace"]},
+			xmlAttr{name: "ChromaSubsampling", value: fields["ChromaSubsampling"]},
+			xmlAttr{name: "colour_primaries", value: fields["colour_primaries"]},
+			xmlAttr{name: "transfer_characteristics", value: fields["transfer_characteristics"]},
+			xmlAttr{name: "matrix_coefficients", value: fields["matrix_coefficients"]},
+			xmlAttr{name: "colour_range", value: fields["colour_range"]},
+			xmlAttr{name: "HDR_Format", value: fields["HDR_Format"]},
+			xmlAttr{name: "WritingLibrary", value: fields["Encoded_Library"]},
+		),
+		integers: nonEmptyUnitAttrs(
+			ebuCoreUnitAttr{name: "BitDepth", unit: "bit", value: fields["BitDepth"]},
+			ebuCoreUnitAttr{name: "FrameCount", value: fields["FrameCount"]},
+			ebuCoreUnitAttr{name: "StreamSize", unit: "byte", value: fields["StreamSize"]},
+		),
+		booleans: ebuCoreBooleans(fields, "Format_Settings_CABAC", "CABAC"),
+	})
+	return video
+}
+
+func buildEBUCoreAudio(fields typedFields) *xmlNode {
+	audio := newXMLNode("ebucore:audioFormat")
+	audio.attr("audioFormatName", fields["Format"])
+	encoding := fields["Format_Commercial_IfAny"]
+	if encoding == "" {
+		encoding = fields["Format_AdditionalFeatures"]
+	}
+	if encoding != "" {
+		audio.add(newXMLNode("ebucore:audioEncoding", xmlAttr{name: "typeLabel", value: encoding}))
+	}
+	addEBUCoreCodec(audio, fields["CodecID"])
+	if layout := fields["ChannelLayout"]; layout != "" {
+		audio.add(newXMLNode("ebucore:audioTrackConfiguration", xmlAttr{name: "typeLabel", value: layout}))
+	}
+	audio.addText("ebucore:samplingRate", fields["SamplingRate"])
+	audio.addText("ebucore:sampleSize", fields["BitDepth"])
+	addEBUCoreBitRate(audio, fields)
+	addEBUCoreTrack(audio, "ebucore:audioTrack", fields, true)
+	audio.addText("ebucore:channels", fields["Channels"])
+	addEBUCoreAttributes(audio, ebuCoreAttributes{
+		strings: nonEmptyAttrs(
+			xmlAttr{name: "ChannelPositions", value: fields["ChannelPositions"]},
+			xmlAttr{name: "ChannelLayout", value: fields["ChannelLayout"]},
+			xmlAttr{name: "CompressionMode", value: fields["Compression_Mode"]},
+			xmlAttr{name: "WritingLibrary", value: fields["Encoded_Library"]},
+		),
+		integers: nonEmptyUnitAttrs(
+			ebuCoreUnitAttr{name: "StreamSize", unit: "byte", value: fields["StreamSize"]},
+		),
+	})
+	return audio
+}
+
+func buildEBUCoreData(fields typedFields) *xmlNode {
+	data := newXMLNode("ebucore:dataFormat")
+	data.attr("dataFormatName", fields["Format"])
+	kind := "subtitling"
+	if strings.HasPrefix(fields["Format"], "EIA-") {
+		kind = "captioning"
+	}
+	entry := data.add(newXMLNode("ebucore:" + kind + "Format"))
+	entry.attr(kind+"FormatName", fields["Format"])
+	entry.attr("trackId", fields["ID"])
+	entry.attr("trackName", fields["Title"])
+	entry.attr("language", fields["Language"])
+	addEBUCoreCodec(data, fields["CodecID"])
+	addEBUCoreAttributes(data, ebuCoreAttributes{
+		integers: nonEmptyUnitAttrs(
+			ebuCoreUnitAttr{name: "ElementCount", value: fields["ElementCount"]},
+			ebuCoreUnitAttr{name: "StreamSize", unit: "byte", value: fields["StreamSize"]},
+		),
+	})
+	return data
+}
+
+// ebuCoreFrameRate expresses rate as EBUCore's rational: an integer rate scaled by
+// factorNumerator/factorDenominator (29.97 is 30 * 1000/1001).
+func ebuCoreFrameRate(rate *big.Rat) *xmlNode {
+	value, _ := rate.Float64()
+	base := int64(math.Round(value))
+	if base < 1 {
+		base = 1
+	}
+	factor := new(big.Rat).Quo(rate, big.NewRat(base, 1))
+	return &xmlNode{
+		name: "ebucore:frameRate",
+		attrs: []xmlAttr{
+			{name: "factorNumerator", value: factor.Num().String()},
+			{name: "factorDenominator", value: factor.Denom().String()},
+		},
+		text: strconv.FormatInt(base, 10),
+	}
+}
+
+// ebuCoreAspectRatio prefers the "16:9" form of the text output and falls back to the
+// numeric ratio.
+func ebuCoreAspectRatio(display string, ratio float64) (int, int, bool) {
+	if num, den, ok := strings.Cut(display, ":"); ok {
+		n, errNum := strconv.Atoi(strings.TrimSpace(num))
+		d, errDen := strconv.Atoi(strings.TrimSpace(den))
+		if errNum == nil && errDen == nil && n > 0 && d > 0 {
+			return n, d, true
+		}
+	}
+	if ratio <= 0 {
+		return 0, 0, false
+	}
+	r := big.NewRat(int64(math.Round(ratio*1000)), 1000)
+	return int(r.Num().Int64()), int(r.Denom().Int64()), true
+}
+
+func profileAtLevel(profile, level string) string {
+	if profile == "" || level == "" {
+		return profile
+	}
+	return profile + "@L" + level
+}
+
+func addEBUCoreCodec(parent *xmlNode, codecID string) {
+	if codecID == "" {
+		return
+	}
+	parent.add(newXMLNode("ebucore:codec")).add(newXMLNode("ebucore:codecIdentifier")).addText("dc:identifier", codecID)
+}
+
+func addEBUCoreBitRate(parent *xmlNode, fields typedFields) {
+	parent.addText("ebucore:bitRate", fields["BitRate"])
+	parent.addText("ebucore:bitRateMax", fields["BitRate_Maximum"])
+	switch fields["BitRate_Mode"] {
+	case "CBR":
+		parent.addText("ebucore:bitRateMode", "constant")
+	case "VBR":
+		parent.addText("ebucore:bitRateMode", "variable")
+	}
+}
+
+func addEBUCoreTrack(parent *xmlNode, name string, fields typedFields, withLanguage bool) {
+	if fields["ID"] == "" && fields["Title"] == "" && fields["Language"] == "" {
+		return
+	}
+	track := parent.add(newXMLNode(name))
+	track.attr("trackId", fields["ID"])
+	track.attr("trackName", fields["Title"])
+	if withLanguage {
+		track.attr("trackLanguage", fields["Language"])
+	}
+}
+
+// ebuCoreAttributes holds technicalAttribute* elements by type; the schema requires the
+// string, integer and boolean groups in that order.
+type ebuCoreAttributes struct {
+	strings  []xmlAttr
+	integers []ebuCoreUnitAttr
+	booleans []xmlAttr
+}
+
+type ebuCoreUnitAttr struct {
+	name  string
+	unit  string
+	value string
+}
+
+func addEBUCoreAttributes(parent *xmlNode, attrs ebuCoreAttributes) {
+	for _, attr := range attrs.strings {
+		parent.addText("ebucore:technicalAttributeString", attr.value, xmlAttr{name: "typeLabel", value: attr.name})
+	}
+	for _, attr := range attrs.integers {
+		labels := []xmlAttr{{name: "typeLabel", value: attr.name}}
+		if attr.unit != "" {
+			labels = append(labels, xmlAttr{name: "unit", value: attr.unit})
+		}
+		parent.addText("ebucore:technicalAttributeInteger", attr.value, labels...)
+	}
+	for _, attr := range attrs.booleans {
+		parent.addText("ebucore:technicalAttributeBoolean", attr.value, xmlAttr{name: "typeLabel", value: attr.name})
+	}
+}
+
+func nonEmptyAttrs(attrs ...xmlAttr) []xmlAttr {
+	out := attrs[:0]
+	for _, attr := range attrs {
+		if attr.value != "" {
+			out = append(out, attr)
+		}
+	}
+	return out
+}
+
+func nonEmptyUnitAttrs(attrs ...ebuCoreUnitAttr) []ebuCoreUnitAttr {
+	out := attrs[:0]
+	for _, attr := range attrs {
+		if _, err := strconv.ParseInt(attr.value, 10, 64); err == nil {
+			out = append(out, attr)
+		}
+	}
+	return out
+}
+
+// ebuCoreBooleans maps Yes/No fields to technicalAttributeBoolean pairs of field key and
+// label.
+func ebuCoreBooleans(fields typedFields, pairs ...string) []xmlAttr {
+	var out []xmlAttr
+	for i := 0; i+1 < len(pairs); i += 2 {
+		switch fields[pairs[i]] {
+		case "Yes":
+			out = append(out, xmlAttr{name: pairs[i+1], value: "true"})
+		case "No":
+			out = append(out, xmlAttr{name: pairs[i+1], value: "false"})
+		}
+	}
+	return out
 }
@@ -0,0 +1,11 @@
+package mediainfo
+
+// failedStream is the General section rendered for a batch entry that could not be analyzed.
+func failedStream(report Report) Stream {
+	fields := []Field{}
+	if report.Ref != "" {
+		fields = append(fields, Field{Name: "Complete name", Value: report.Ref})
+	}
+	fields = append(fields, Field{Name: "Error", Value: report.Err.Error()})
+	return Stream{Kind: StreamGeneral, Fields: fields}
+}
@@ -0,0 +1,913 @@
+package mediainfo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ffprobe JSON follows `ffprobe -show_format -show_streams -of json`: streams first, then
+// the container. Field order matches ffprobe's so line-based consumers see the same layout.
+type ffprobeOut struct {
+	Streams []ffprobeStream `json:"streams"`
+	Format  *ffprobeFormat  `json:"format,omitempty"`
+	Error   *ffprobeError   `json:"error,omitempty"`
+}
+
+type ffprobeError struct {
+	Code   int    `json:"code"`
+	String string `json:"string"`
+}
+
+type ffprobeStream struct {
+	Index              int                `json:"index"`
+	CodecName          string             `json:"codec_name,omitempty"`
+	CodecLongName      string             `json:"codec_long_name,omitempty"`
+	Profile            string             `json:"profile,omitempty"`
+	CodecType          string             `json:"codec_type"`
+	CodecTagString     string             `json:"codec_tag_string"`
+	CodecTag           string             `json:"codec_tag"`
+	Width              int                `json:"width,omitempty"`
+	Height             int                `json:"height,omitempty"`
+	CodedWidth         int                `json:"coded_width,omitempty"`
+	CodedHeight        int                `json:"coded_height,omitempty"`
+	SampleAspectRatio  string             `json:"sample_aspect_ratio,omitempty"`
+	DisplayAspectRatio string             `json:"display_aspect_ratio,omitempty"`
+	PixFmt             string             `json:"pix_fmt,omitempty"`
+	Level              int                `json:"level,omitempty"`
+	ColorRange         string             `json:"color_range,omitempty"`
+	ColorSpace         string             `json:"color_space,omitempty"`
+	ColorTransfer      string             `json:"color_transfer,omitempty"`
+	ColorPrimaries     string             `json:"color_primaries,omitempty"`
+	FieldOrder         string             `json:"field_order,omitempty"`
+	SampleFmt          string             `json:"sample_fmt,omitempty"`
+	SampleRate         string             `json:"sample_rate,omitempty"`
+	Channels           int                `json:"channels,omitempty"`
+	ChannelLayout      string             `json:"channel_layout,omitempty"`
+	BitsPerSample      int                `json:"bits_per_sample,omitempty"`
+	ID                 string             `json:"id,omitempty"`
+	RFrameRate         string             `json:"r_frame_rate"`
+	AvgFrameRate       string             `json:"avg_frame_rate"`
+	StartTime          string             `json:"start_time,omitempty"`
+	Duration           string             `json:"duration,omitempty"`
+	BitRate            string             `json:"bit_rate,omitempty"`
+	BitsPerRawSample   string             `json:"bits_per_raw_sample,omitempty"`
+	NbFrames           string             `json:"nb_frames,omitempty"`
+	Disposition        ffprobeDisposition `json:"disposition"`
+	Tags               map[string]string  `json:"tags,omitempty"`
+	SideDataList       []any              `json:"side_data_list,omitempty"`
+}
+
+type ffprobeDisposition struct {
+	Default         int `json:"default"`
+	Dub             int `json:"dub"`
+	Original        int `json:"original"`
+	Comment         int `json:"comment"`
+	Lyrics          int `json:"lyrics"`
+	Karaoke         int `json:"karaoke"`
+	Forced          int `json:"forced"`
+	HearingImpaired int `json:"hearing_impaired"`
+	VisualImpaired  int `json:"visual_impaired"`
+	CleanEffects    int `json:"clean_effects"`
+	AttachedPic     int `json:"attached_pic"`
+	TimedThumbnails int `json:"timed_thumbnails"`
+	NonDiegetic     int `json:"non_diegetic"`
+	Captions        int `json:"captions"`
+	Descriptions    int `json:"descriptions"`
+	Metadata        int `json:"metadata"`
+	Dependent       int `json:"dependent"`
+	StillImage      int `json:"still_image"`
+}
+
+type ffprobeDOVIConfig struct {
+	SideDataType              string `json:"side_data_type"`
+	DVVersionMajor            int    `json:"dv_version_major"`
+	DVVersionMinor            int    `json:"dv_version_minor"`
+	DVProfile                 int    `json:"dv_profile"`
+	DVLevel                   int    `json:"dv_level"`
+	RPUPresentFlag            int    `json:"rpu_present_flag"`
+	ELPresentFlag             int    `json:"el_present_flag"`
+	BLPresentFlag             int    `json:"bl_present_flag"`
+	DVBLSignalCompatibilityID int    `json:"dv_bl_signal_compatibility_id"`
+}
+
+type ffprobeMasteringDisplay struct {
+	SideDataType string `json:"side_data_type"`
+	RedX         string `json:"red_x"`
+	RedY         string `json:"red_y"`
+	GreenX       string `json:"green_x"`
+	GreenY       string `json:"green_y"`
+	BlueX        string `json:"blue_x"`
+	BlueY        string `json:"blue_y"`
+	WhitePointX  string `json:"white_point_x"`
+	WhitePointY  string `json:"white_point_y"`
+	MinLuminance string `json:"min_luminance"`
+	MaxLuminance string `json:"max_luminance"`
+}
+
+type ffprobeContentLight struct {
+	SideDataType string `json:"side_data_type"`
+	MaxContent   int    `json:"max_content"`
+	MaxAverage   int    `json:"max_average"`
+}
+
+type ffprobeFormat struct {
+	Filename       string            `json:"filename"`
+	NbStreams      int               `json:"nb_streams"`
+	NbPrograms     int               `json:"nb_programs"`
+	FormatName     string            `json:"format_name"`
+	FormatLongName string            `json:"format_long_name,omitempty"`
+	StartTime      string            `json:"start_time,omitempty"`
+	Duration       string            `json:"duration,omitempty"`
+	Size           string            `json:"size,omitempty"`
+	BitRate        string            `json:"bit_rate,omitempty"`
+	ProbeScore     int               `json:"probe_score"`
+	Tags           map[string]string `json:"tags,omitempty"`
+}
+
+// RenderFFprobeJSON renders reports in the schema of `ffprobe -show_format -show_streams
+// -of json`. A single report is rendered as an object, several as an array. Menu streams
+// have no ffprobe counterpart and are left out; failed files carry ffprobe's error object.
+func RenderFFprobeJSON(reports []Report) string {
+	var value any
+	if len(reports) == 1 {
+		value = buildFFprobe(reports[0])
+	} else {
+		outs := make([]ffprobeOut, 0, len(reports))
+		for _, report := range reports {
+			outs = append(outs, buildFFprobe(report))
+		}
+		value = outs
+	}
+	data, err := json.MarshalIndent(value, "", "    ")
+	if err != nil {
+		return ""
+	}
+	return string(data) + "\n"
+}
+
+func buildFFprobe(report Report) ffprobeOut {
+	out := ffprobeOut{Streams: []ffprobeStream{}}
+	if report.Err != nil {
+		code := -1094995529 // AVERROR_INVALIDDATA
+		if errors.Is(report.Err, fs.ErrNotExist) {
+			code = -2 // AVERROR(ENOENT)
+		}
+		out.Error = &ffprobeError{Code: code, String: report.Err.Error()}
+		return out
+	}
+
+	general := typedFieldsFromJSON(expandJSONExtra(buildJSONGeneralFields(report)))
+	containerFormat := findField(report.General.Fields, "Format")
+	startTime := math.Inf(1)
+	forEachStreamWithKindIndex(orderTracks(report.Streams), func(stream Stream, _, _, order int) {
+		fields := typedFieldsFromJSON(expandJSONExtra(buildJSONStreamFields(stream, order, 0, containerFormat)))
+		var entry ffprobeStream
+		switch stream.Kind {
+		case StreamVideo, StreamImage:
+			entry = ffprobeVideo(fields, stream.Kind == StreamImage)
+		case StreamAudio:
+			entry = ffprobeAudio(fields)
+		case StreamText:
+			entry = ffprobeSubtitle(fields)
+		default:
+			return
+		}
+		entry.Index = len(out.Streams)
+		entry.CodecTagString, entry.CodecTag = ffprobeCodecTag(containerFormat, fields["CodecID"])
+		if containerFormat == "MPEG-TS" || containerFormat == "BDAV" || containerFormat == "MPEG-PS" {
+			if id, err := strconv.ParseInt(fields["ID"], 10, 64); err == nil {
+				entry.ID = fmt.Sprintf("0x%x", id)
+			}
+		}
+		if fields["Delay"] != "" {
+			delay := fields.float("Delay")
+			entry.StartTime = ffprobeSeconds(delay)
+			startTime = min(startTime, delay)
+		}
+		if fields["Duration"] != "" {
+			entry.Duration = ffprobeSeconds(fields.float("Duration"))
+		}
+		if fields["BitRate"] != "" {
+			entry.BitRate = strconv.FormatInt(fields.int64("BitRate"), 10)
+		}
+		if fields["FrameCount"] != "" && stream.Kind == StreamVideo {
+			entry.NbFrames = strconv.FormatInt(fields.int64("FrameCount"), 10)
+		}
+		entry.Disposition.Default = ffprobeFlag(fields.bool("Default"))
+		entry.Disposition.Forced = ffprobeFlag(fields.bool("Forced"))
+		entry.Tags = ffprobeTags(
+			"language", ffprobeLanguage(fields["Language"]),
+			"title", fields["Title"],
+		)
+		out.Streams = append(out.Streams, entry)
+	})
+
+	name, longName := ffprobeFormatName(general["Format"])
+	format := &ffprobeFormat{
+		Filename:       report.Ref,
+		NbStreams:      len(out.Streams),
+		FormatName:     name,
+		FormatLongName: longName,
+		ProbeScore:     100,
+	}
+	if general.int("MenuCount") > 0 && (containerFormat == "MPEG-TS" || containerFormat == "BDAV") {
+		format.NbPrograms = general.int("MenuCount")
+	}
+	if !math.IsInf(startTime, 1) {
+		format.StartTime = ffprobeSeconds(startTime)
+	}
+	if general["Duration"] != "" {
+		format.Duration = ffprobeSeconds(general.float("Duration"))
+	}
+	if general["FileSize"] != "" {
+		format.Size = strconv.FormatInt(general.int64("FileSize"), 10)
+	}
+	if general["OverallBitRate"] != "" {
+		format.BitRate = strconv.FormatInt(general.int64("OverallBitRate"), 10)
+	}
+	title := general["Title"]
+	if title == "" {
+		title = general["Movie"]
+	}
+	format.Tags = ffprobeTags(
+		"title", title,
+		"encoder", general["Encoded_Application"],
+		"creation_time", ffprobeTime(general["Encoded_Date"]),
+	)
+	out.Format = format
+	return out
+}
+
+func ffprobeVideo(fields typedFields, image bool) ffprobeStream {
+	name, longName := ffprobeVideoCodec(fields)
+	stream := ffprobeStream{
+		CodecName:        name,
+		CodecLongName:    longName,
+		Profile:          fields["Format_Profile"],
+		CodecType:        "video",
+		Width:            fields.int("Width"),
+		Height:           fields.int("Height"),
+		PixFmt:           ffprobePixFmt(fields),
+		Level:            ffprobeLevel(name, fields["Format_Level"]),
+		ColorRange:       ffprobeColorRange(fields["colour_range"]),
+		ColorSpace:       ffprobeColorName(fields["matrix_coefficients"]),
+		ColorTransfer:    ffprobeColorName(fields["transfer_characteristics"]),
+		ColorPrimaries:   ffprobeColorName(fields["colour_primaries"]),
+		FieldOrder:       ffprobeFieldOrder(fields["ScanType"], fields["ScanOrder"]),
+		RFrameRate:       "0/0",
+		AvgFrameRate:     "0/0",
+		BitsPerRawSample: fields["BitDepth"],
+	}
+	if profile, _, ok := strings.Cut(stream.Profile, "@"); ok {
+		stream.Profile = profile
+	}
+	stream.CodedWidth = max(fields.int("Stored_Width"), stream.Width)
+	stream.CodedHeight = max(fields.int("Stored_Height"), stream.Height)
+	if stream.Width > 0 && stream.Height > 0 {
+		num, den := ffprobeRatio(fields.float("PixelAspectRatio"))
+		stream.SampleAspectRatio = fmt.Sprintf("%d:%d", num, den)
+		darNum, darDen := reduceRatio(int64(stream.Width)*num, int64(stream.Height)*den)
+		stream.DisplayAspectRatio = fmt.Sprintf("%d:%d", darNum, darDen)
+	}
+	var rate big.Rat
+	fields.frameRate(&rate)
+	if rate.Sign() > 0 {
+		stream.RFrameRate = rate.Num().String() + "/" + rate.Denom().String()
+		stream.AvgFrameRate = stream.RFrameRate
+	}
+	if image {
+		stream.Disposition.AttachedPic = 1
+	}
+	stream.SideDataList = ffprobeSideData(fields)
+	return stream
+}
+
+func ffprobeAudio(fields typedFields) ffprobeStream {
+	name, longName := ffprobeAudioCodec(fields)
+	channels := fields.int("Channels")
+	stream := ffprobeStream{
+		CodecName:     name,
+		CodecLongName: longName,
+		Profile:       ffprobeAudioProfile(name, fields),
+		CodecType:     "audio",
+		SampleFmt:     ffprobeSampleFmt(name, fields.int("BitDepth")),
+		Channels:      channels,
+		ChannelLayout: ffprobeChannelLayout(fields["ChannelLayout"], channels),
+		RFrameRate:    "0/0",
+		AvgFrameRate:  "0/0",
+	}
+	if rate := fields.int("SamplingRate"); rate > 0 {
+		stream.SampleRate = strconv.Itoa(rate)
+	}
+	if strings.HasPrefix(name, "pcm_") {
+		stream.BitsPerSample = fields.int("BitDepth")
+	}
+	if fields["Compression_Mode"] == "Lossless" && fields.int("BitDepth") > 0 {
+		stream.BitsPerRawSample = fields["BitDepth"]
+	}
+	return stream
+}
+
+func ffprobeSubtitle(fields typedFields) ffprobeStream {
+	name, longName := ffprobeSubtitleCodec(fields["Format"])
+	return ffprobeStream{
+		CodecName:     name,
+		CodecLongName: longName,
+		CodecType:     "subtitle",
+		RFrameRate:    "0/0",
+		AvgFrameRate:  "0/0",
+	}
+}
+
+func ffprobeVideoCodec(fields typedFields) (string, string) {
+	switch fields["Format"] {
+	case "AVC":
+		return "h264", "H.264 / AVC / MPEG-4 AVC / MPEG-4 part 10"
+	case "HEVC":
+		return "hevc", "H.265 / HEVC (High Efficiency Video Coding)"
+	case "VVC":
+		return "vvc", "H.266 / VVC (Versatile Video Coding)"
+	case "AV1":
+		return "av1", "Alliance for Open Media AV1"
+	case "VP9":
+		return "vp9", "Google VP9"
+	case "VP8":
+		return "vp8", "On2 VP8"
+	case "MPEG Video":
+		if strings.Contains(fields["Format_Version"], "1") {
+			return "mpeg1video", "MPEG-1 video"
+		}
+		return "mpeg2video", "MPEG-2 video"
+	case "MPEG-4 Visual":
+		return "mpeg4", "MPEG-4 part 2"
+	case "VC-1":
+		return "vc1", "SMPTE VC-1"
+	case "JPEG":
+		return "mjpeg", "Motion JPEG"
+	case "PNG":
+		return "png", "PNG (Portable Network Graphics) image"
+	case "ProRes":
+		return "prores", "Apple ProRes (iCodec Pro)"
+	case "FFV1":
+		return "ffv1", "FFmpeg video codec #1"
+	case "Theora":
+		return "theora", "Theora"
+	}
+	return strings.ToLower(fields["Format"]), ""
+}
+
+func ffprobeAudioCodec(fields typedFields) (string, string) {
+	switch fields["Format"] {
+	case "AAC":
+		return "aac", "AAC (Advanced Audio Coding)"
+	case "AC-3":
+		return "ac3", "ATSC A/52A (AC-3)"
+	case "E-AC-3":
+		return "eac3", "ATSC A/52B (AC-3, E-AC-3)"
+	case "DTS":
+		return "dts", "DCA (DTS Coherent Acoustics)"
+	case "MLP FBA", "TrueHD":
+		return "truehd", "TrueHD"
+	case "MPEG Audio":
+		if strings.Contains(fields["Format_Profile"], "Layer 2") {
+			return "mp2", "MP2 (MPEG audio layer 2)"
+		}
+		return "mp3", "MP3 (MPEG audio layer 3)"
+	case "FLAC":
+		return "flac", "FLAC (Free Lossless Audio Codec)"
+	case "Opus":
+		return "opus", "Opus (Opus Interactive Audio Codec)"
+	case "Vorbis":
+		return "vorbis", "Vorbis"
+	case "ALAC":
+		return "alac", "ALAC (Apple Lossless Audio Codec)"
+	case "PCM":
+		return ffprobePCMCodec(fields)
+	}
+	return strings.ToLower(fields["Format"]), ""
+}
+
+func ffprobePCMCodec(fields typedFields) (string, string) {
+	bits := fields.int("BitDepth")
+	if bits == 0 {
+		bits = 16
+	}
+	if bits == 8 && fields["Format_Settings_Sign"] != "Signed" {
+		return "pcm_u8", "PCM unsigned 8-bit"
+	}
+	endian, endianName := "le", "little"
+	if fields["Format_Settings_Endianness"] == "Big" {
+		endian, endianName = "be", "big"
+	}
+	return fmt.Sprintf("pcm_s%d%s", bits, endian), fmt.Sprintf("PCM signed %d-bit %s-endian", bits, endianName)
+}
+
+func ffprobeSubtitleCodec(format string) (string, string) {
+	switch format {
+	case "UTF-8", "SubRip", "SRT":
+		return "subrip", "SubRip subtitle"
+	case "ASS":
+		return "ass", "ASS (Advanced SSA) subtitle"
+	case "SSA":
+		return "ssa", "SSA (SubStation Alpha) subtitle"
+	case "PGS":
+		return "hdmv_pgs_subtitle", "HDMV Presentation Graphic Stream subtitles"
+	case "VobSub", "RLE":
+		return "dvd_subtitle", "DVD subtitles"
+	case "DVB Subtitle":
+		return "dvb_subtitle", "DVB subtitles"
+	case "Timed Text", "Text":
+		return "mov_text", "MOV text"
+	case "WebVTT":
+		return "webvtt", "WebVTT subtitle"
+	case "EIA-608":
+		return "eia_608", "EIA-608 closed captions"
+	}
+	return strings.ToLower(format), ""
+}
+
+func ffprobeAudioProfile(codec string, fields typedFields) string {
+	switch codec {
+	case "aac":
+		switch {
+		case strings.Contains(fields["Format_AdditionalFeatures"], "PS") || strings.HasPrefix(fields["Format_Settings_PS"], "Yes"):
+			return "HE-AACv2"
+		case strings.HasPrefix(fields["Format_Settings_SBR"], "Yes"):
+			return "HE-AAC"
+		case fields["Format_AdditionalFeatures"] == "LC":
+			return "LC"
+		}
+	case "dts":
+		profile := fields["Format_Profile"]
+		switch {
+		case strings.Contains(profile, "X") && strings.Contains(profile, "MA"):
+			return "DTS-HD MA + DTS:X"
+		case strings.Contains(profile, "MA"):
+			return "DTS-HD MA"
+		case strings.Contains(profile, "HRA"):
+			return "DTS-HD HRA"
+		case strings.Contains(profile, "ES"):
+			return "DTS-ES"
+		}
+		return "DTS"
+	case "eac3":
+		if strings.Contains(fields["Format_AdditionalFeatures"], "JOC") {
+			return "Dolby Digital Plus + Dolby Atmos"
+		}
+	case "truehd":
+		if strings.Contains(fields["Format_Commercial_IfAny"], "Atmos") {
+			return "Dolby TrueHD + Dolby Atmos"
+		}
+	}
+	return ""
+}
+
+// ffprobeSampleFmt is the sample format FFmpeg's decoder for codec outputs.
+func ffprobeSampleFmt(codec string, bits int) string {
+	switch codec {
+	case "aac", "ac3", "eac3", "dts", "mp2", "mp3", "opus", "vorbis":
+		return "fltp"
+	case "truehd":
+		return "s32"
+	case "flac", "alac":
+		if bits > 16 {
+			return "s32"
+		}
+		return "s16"
+	case "pcm_u8":
+		return "u8"
+	}
+	if strings.HasPrefix(codec, "pcm_s") {
+		if bits > 16 {
+			return "s32"
+		}
+		return "s16"
+	}
+	return ""
+}
+
+func ffprobePixFmt(fields typedFields) string {
+	bits := fields.int("BitDepth")
+	suffix := ""
+	if bits > 8 {
+		suffix = strconv.Itoa(bits) + "le"
+	}
+	switch fields["ColorSpace"] {
+	case "YUV":
+		switch fields["ChromaSubsampling"] {
+		case "4:2:0":
+			return "yuv420p" + suffix
+		case "4:2:2":
+			return "yuv422p" + suffix
+		case "4:4:4":
+			return "yuv444p" + suffix
+		}
+	case "Y":
+		if bits > 8 {
+			return "gray" + suffix
+		}
+		return "gray"
+	case "RGB":
+		if bits > 8 {
+			return "gbrp" + suffix
+		}
+		return "rgb24"
+	case "RGBA":
+		return "rgba"
+	}
+	return ""
+}
+
+// ffprobeLevel converts a MediaInfo level ("4.1") to FFmpeg's integer form: 41 for AVC,
+// 123 (level * 30) for HEVC and VVC.
+func ffprobeLevel(codec, level string) int {
+	level, _, _ = strings.Cut(level, "@")
+	value, err := strconv.ParseFloat(strings.TrimSpace(level), 64)
+	if err != nil {
+		return 0
+	}
+	switch codec {
+	case "h264":
+		return int(math.Round(value * 10))
+	case "hevc", "vvc":
+		return int(math.Round(value * 30))
+	}
+	return 0
+}
+
+func ffprobeColorRange(value string) string {
+	switch value {
+	case "Limited":
+		return "tv"
+	case "Full":
+		return "pc"
+	}
+	return ""
+}
+
+// ffprobeColorName maps MediaInfo color primaries, transfer characteristics and matrix
+// coefficients to FFmpeg's names; the same label means the same thing in all three.
+func ffprobeColorName(value string) string {
+	switch value {
+	case "BT.709":
+		return "bt709"
+	case "BT.2020", "BT.2020 (10-bit)":
+		return "bt2020"
+	case "BT.2020 non-constant":
+		return "bt2020nc"
+	case "BT.2020 constant":
+		return "bt2020c"
+	case "BT.2020 (12-bit)":
+		return "bt2020-12"
+	case "PQ":
+		return "smpte2084"
+	case "HLG":
+		return "arib-std-b67"
+	case "BT.601", "BT.601 NTSC":
+		return "smpte170m"
+	case "BT.601 PAL", "BT.470 System B/G":
+		return "bt470bg"
+	case "BT.470 System M":
+		return "bt470m"
+	case "SMPTE 240M":
+		return "smpte240m"
+	case "Linear":
+		return "linear"
+	case "sRGB/sYCC":
+		return "iec61966-2-1"
+	case "xvYCC":
+		return "iec61966-2-4"
+	case "DCI P3":
+		return "smpte431"
+	case "Display P3":
+		return "smpte432"
+	case "YCgCo":
+		return "ycgco"
+	case "Identity":
+		return "gbr"
+	}
+	return ""
+}
+
+func ffprobeFieldOrder(scanType, scanOrder string) string {
+	switch {
+	case scanType == "Progressive":
+		return "progressive"
+	case scanType == "" || scanType == "Unknown":
+		return ""
+	case strings.HasPrefix(scanOrder, "TFF"):
+		return "tt"
+	case strings.HasPrefix(scanOrder, "BFF"):
+		return "bb"
+	}
+	return "unknown"
+}
+
+// ffprobeRatio turns a MediaInfo pixel aspect ratio (3 decimals) into the smallest
+// fraction that rounds to it.
+func ffprobeRatio(value float64) (int64, int64) {
+	if value <= 0 || math.Abs(value-1) < 0.0005 {
+		return 1, 1
+	}
+	for den := int64(1); den <= 1000; den++ {
+		num := int64(math.Round(value * float64(den)))
+		if math.Abs(float64(num)/float64(den)-value) < 0.0005 {
+			return reduceRatio(num, den)
+		}
+	}
+	return 1, 1
+}
+
+func reduceRatio(num, den int64) (int64, int64) {
+	a, b := num, den
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a == 0 {
+		return num, den
+	}
+	return num / a, den / a
+}
+
+// ffprobeChannelLayout names the layout like FFmpeg does, from MediaInfo's channel labels.
+func ffprobeChannelLayout(layout string, channels int) string {
+	labels := strings.Fields(layout)
+	sort.Strings(labels)
+	switch strings.Join(labels, " ") {
+	case "C", "M":
+		return "mono"
+	case "L R":
+		return "stereo"
+	case "L LFE R":
+		return "2.1"
+	case "C L R":
+		return "3.0"
+	case "C L LFE R":
+		return "3.1"
+	case "L Ls R Rs":
+		return "quad(side)"
+	case "L Lb R Rb":
+		return "quad"
+	case "C L Ls R Rs":
+		return "5.0(side)"
+	case "C L Lb R Rb":
+		return "5.0"
+	case "C L LFE Ls R Rs":
+		return "5.1(side)"
+	case "C L LFE Lb R Rb":
+		return "5.1"
+	case "C Cb L LFE Ls R Rs":
+		return "6.1"
+	case "C L LFE Lb Ls R Rb Rs":
+		return "7.1"
+	}
+	switch channels {
+	case 0:
+		return ""
+	case 1:
+		return "mono"
+	case 2:
+		return "stereo"
+	}
+	return fmt.Sprintf("%d channels", channels)
+}
+
+func ffprobeFormatName(format string) (string, string) {
+	switch format {
+	case "Matroska", "WebM":
+		return "matroska,webm", "Matroska / WebM"
+	case "MPEG-4", "QuickTime":
+		return "mov,mp4,m4a,3gp,3g2,mj2", "QuickTime / MOV"
+	case "MPEG-TS", "BDAV":
+		return "mpegts", "MPEG-TS (MPEG-2 Transport Stream)"
+	case "MPEG-PS":
+		return "mpeg", "MPEG-PS (MPEG-2 Program Stream)"
+	case "AVI":
+		return "avi", "AVI (Audio Video Interleaved)"
+	case "FLAC":
+		return "flac", "raw FLAC"
+	case "MPEG Audio":
+		return "mp3", "MP2/3 (MPEG audio layer 2/3)"
+	case "Wave":
+		return "wav", "WAV / WAVE (Waveform Audio)"
+	case "Ogg":
+		return "ogg", "Ogg"
+	case "AIFF":
+		return "aiff", "Audio IFF"
+	case "ADTS":
+		return "aac", "raw ADTS AAC (Advanced Audio Coding)"
+	case "AC-3":
+		return "ac3", "raw AC-3"
+	case "E-AC-3":
+		return "eac3", "raw E-AC-3"
+	case "DTS":
+		return "dts", "raw DTS"
+	case "IVF":
+		return "ivf", "On2 IVF"
+	case "AV1":
+		return "obu", "AV1 low overhead OBU"
+	case "VVC":
+		return "vvc", "raw H.266/VVC video"
+	case "AVC":
+		return "h264", "raw H.264 video"
+	case "HEVC":
+		return "hevc", "raw HEVC video"
+	case "VC-1":
+		return "vc1", "raw VC-1 video"
+	case "MLP FBA":
+		return "truehd", "raw TrueHD"
+	case "LATM":
+		return "loas", "LOAS AudioSyncStream"
+	}
+	return strings.ToLower(format), ""
+}
+
+// ffprobeCodecTag returns codec_tag_string and codec_tag for the FourCC codec IDs of MP4,
+// QuickTime and AVI; other codec IDs (Matroska names, stream types) carry no tag.
+func ffprobeCodecTag(containerFormat, codecID string) (string, string) {
+	switch containerFormat {
+	case "MPEG-4", "QuickTime", "AVI":
+	default:
+		return "[0][0][0][0]", "0x0000"
+	}
+	if len(codecID) != 4 {
+		return "[0][0][0][0]", "0x0000"
+	}
+	var tag uint32
+	for i := 3; i >= 0; i-- {
+		c := codecID[i]
+		if c < 0x20 || c > 0x7e {
+			return "[0][0][0][0]", "0x0000"
+		}
+		tag = tag<<8 | uint32(c)
+	}
+	return codecID, fmt.Sprintf("0x%08x", tag)
+}
+
+// ffprobeSideData rebuilds the stream side data ffprobe shows for HDR video: mastering
+// display and content light level (SMPTE ST 2086 / CTA-861.3) and the Dolby Vision
+// configuration record.
+func ffprobeSideData(fields typedFields) []any {
+	var list []any
+	if mastering, ok := ffprobeMastering(fields["MasteringDisplay_ColorPrimaries"], fields["MasteringDisplay_Luminance"]); ok {
+		list = append(list, mastering)
+	}
+	if maxCLL, maxFALL := ffprobeCandela(fields["MaxCLL"]), ffprobeCandela(fields["MaxFALL"]); maxCLL > 0 || maxFALL > 0 {
+		list = append(list, ffprobeContentLight{SideDataType: "Content light level metadata", MaxContent: maxCLL, MaxAverage: maxFALL})
+	}
+	if dovi, ok := ffprobeDOVI(fields); ok {
+		list = append(list, dovi)
+	}
+	return list
+}
+
+func ffprobeMastering(primaries, luminance string) (ffprobeMasteringDisplay, bool) {
+	var values [8]uint16 // G, B, R, white point; x then y, in 0.00002 units
+	found := false
+	// Display P3 is listed with both the DCI and the D65 white point; the D65 entry comes
+	// last and is the one mastering metadata uses.
+	for _, entry := range masteringDisplayValues {
+		if primaries != "" && masteringDisplayPrimariesLabel(entry.code) == primaries {
+			values, found = entry.values, true
+		}
+	}
+	if !found {
+		var rx, ry, gx, gy, bx, by, wx, wy float64
+		if n, _ := fmt.Sscanf(primaries, "R: x=%f y=%f, G: x=%f y=%f, B: x=%f y=%f, White point: x=%f y=%f", &rx, &ry, &gx, &gy, &bx, &by, &wx, &wy); n != 8 {
+			return ffprobeMasteringDisplay{}, false
+		}
+		for i, v := range []float64{gx, gy, bx, by, rx, ry, wx, wy} {
+			values[i] = uint16(math.Round(v * 50000))
+		}
+	}
+	var minLum, maxLum float64
+	if n, _ := fmt.Sscanf(luminance, "min: %f cd/m2, max: %f cd/m2", &minLum, &maxLum); n != 2 {
+		return ffprobeMasteringDisplay{}, false
+	}
+	chroma := func(v uint16) string { return fmt.Sprintf("%d/50000", v) }
+	return ffprobeMasteringDisplay{
+		SideDataType: "Mastering display metadata",
+		RedX:         chroma(values[4]),
+		RedY:         chroma(values[5]),
+		GreenX:       chroma(values[0]),
+		GreenY:       chroma(values[1]),
+		BlueX:        chroma(values[2]),
+		BlueY:        chroma(values[3]),
+		WhitePointX:  chroma(values[6]),
+		WhitePointY:  chroma(values[7]),
+		MinLuminance: fmt.Sprintf("%d/10000", int64(math.Round(minLum*10000))),
+		MaxLuminance: fmt.Sprintf("%d/10000", int64(math.Round(maxLum*10000))),
+	}, true
+}
+
+func ffprobeCandela(value string) int {
+	number, _, _ := strings.Cut(value, " ")
+	parsed, _ := strconv.Atoi(number)
+	return parsed
+}
+
+// ffprobeDOVI reads the Dolby Vision configuration back from the HDR_Format_* fields. With
+// several HDR formats ("Dolby Vision / SMPTE ST 2094 App 4") Dolby Vision comes first.
+func ffprobeDOVI(fields typedFields) (ffprobeDOVIConfig, bool) {
+	if !strings.HasPrefix(fields["HDR_Format"], "Dolby Vision") {
+		return ffprobeDOVIConfig{}, false
+	}
+	first := func(key string) string {
+		value, _, _ := strings.Cut(fields[key], " / ")
+		return strings.TrimSpace(value)
+	}
+	dovi := ffprobeDOVIConfig{SideDataType: "DOVI configuration record"}
+	major, minor, _ := strings.Cut(first("HDR_Format_Version"), ".")
+	dovi.DVVersionMajor, _ = strconv.Atoi(major)
+	dovi.DVVersionMinor, _ = strconv.Atoi(minor)
+	if _, profile, ok := strings.Cut(first("HDR_Format_Profile"), "."); ok {
+		dovi.DVProfile, _ = strconv.Atoi(profile)
+	}
+	dovi.DVLevel, _ = strconv.Atoi(first("HDR_Format_Level"))
+	for layer := range strings.SplitSeq(first("HDR_Format_Settings"), "+") {
+		switch layer {
+		case "BL":
+			dovi.BLPresentFlag = 1
+		case "EL":
+			dovi.ELPresentFlag = 1
+		case "RPU":
+			dovi.RPUPresentFlag = 1
+		}
+	}
+	if compat := first("HDR_Format_Compatibility"); compat != "" {
+		for id, name := range dolbyVisionCompatibility {
+			if name != "" && strings.HasPrefix(compat, name) {
+				dovi.DVBLSignalCompatibilityID = id
+			}
+		}
+	}
+	return dovi, true
+}
+
+// ffprobeLanguage returns the ISO 639-2 code FFmpeg reports for a MediaInfo language,
+// preferring the bibliographic form Matroska uses ("fre", "ger").
+func ffprobeLanguage(language string) string {
+	code, _, _ := strings.Cut(normalizeLanguageCode(language), "-")
+	if len(code) != 2 {
+		return code
+	}
+	best := ""
+	for code3, code2 := range languageMap3To2 {
+		if code2 != code {
+			continue
+		}
+		if best == "" || languageBibliographic[code3] || !languageBibliographic[best] && code3 < best {
+			best = code3
+		}
+	}
+	if best == "" {
+		return code
+	}
+	return best
+}
+
+var languageBibliographic = map[string]bool{
+	"chi": true, "cze": true, "fre": true, "ger": true, "gre": true, "per": true, "rum": true,
+}
+
+// ffprobeTime converts a MediaInfo UTC date ("2024-01-31 12:00:00 UTC") to ffprobe's
+// creation_time form.
+func ffprobeTime(value string) string {
+	date, ok := strings.CutSuffix(value, " UTC")
+	if !ok || len(date) != len("2006-01-02 15:04:05") {
+		return ""
+	}
+	return strings.Replace(date, " ", "T", 1) + ".000000Z"
+}
+
+func ffprobeTags(pairs ...string) map[string]string {
+	var tags map[string]string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] == "" {
+			continue
+		}
+		if tags == nil {
+			tags = map[string]string{}
+		}
+		tags[pairs[i]] = pairs[i+1]
+	}
+	return tags
+}
+
+func ffprobeSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', 6, 64)
+}
+
+func ffprobeFlag(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,90 @@
+package mediainfo
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRenderFFprobeJSON(t *testing.T) {
+	report, err := AnalyzeFile("samples/sample.mkv")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	var probe struct {
+		Streams []map[string]any `json:"streams"`
+		Format  map[string]any   `json:"format"`
+	}
+	if err := json.Unmarshal([]byte(RenderFFprobeJSON([]Report{report})), &probe); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(probe.Streams) != 2 {
+		t.Fatalf("got %d streams, want 2", len(probe.Streams))
+	}
+	video, audio := probe.Streams[0], probe.Streams[1]
+	for key, want := range map[string]any{
+		"codec_name":           "h264",
+		"codec_type":           "video",
+		"profile":              "High",
+		"width":                640.0,
+		"height":               360.0,
+		"pix_fmt":              "yuv420p",
+		"level":                30.0,
+		"color_range":          "tv",
+		"r_frame_rate":         "30000/1001",
+		"display_aspect_ratio": "16:9",
+	} {
+		if video[key] != want {
+			t.Errorf("video %s=%v, want %v", key, video[key], want)
+		}
+	}
+	for key, want := range map[string]any{
+		"index":          1.0,
+		"codec_name":     "aac",
+		"codec_type":     "audio",
+		"sample_rate":    "48000",
+		"channels":       1.0,
+		"channel_layout": "mono",
+	} {
+		if audio[key] != want {
+			t.Errorf("audio %s=%v, want %v", key, audio[key], want)
+		}
+	}
+	if probe.Format["format_name"] != "matroska,webm" || probe.Format["size"] != "275012" || probe.Format["nb_streams"] != 2.0 {
+		t.Errorf("format=%v", probe.Format)
+	}
+}
+
+func TestFFprobeSideData(t *testing.T) {
+	fields := typedFields{
+		"HDR_Format":                      "Dolby Vision / SMPTE ST 2086",
+		"HDR_Format_Version":              "1.0 / ",
+		"HDR_Format_Profile":              "dvhe.08 / ",
+		"HDR_Format_Level":                "06 / ",
+		"HDR_Format_Settings":             "BL+RPU / ",
+		"HDR_Format_Compatibility":        "HDR10 / HDR10",
+		"MasteringDisplay_ColorPrimaries": "Display P3",
+		"MasteringDisplay_Luminance":      "min: 0.0050 cd/m2, max: 1000 cd/m2",
+		"MaxCLL":                          "1000 cd/m2",
+		"MaxFALL":                         "400 cd/m2",
+	}
+	want := []any{
+		ffprobeMasteringDisplay{
+			SideDataType: "Mastering display metadata",
+			RedX:         "34000/50000", RedY: "16000/50000",
+			GreenX: "13250/50000", GreenY: "34500/50000",
+			BlueX: "7500/50000", BlueY: "3000/50000",
+			WhitePointX: "15635/50000", WhitePointY: "16450/50000",
+			MinLuminance: "50/10000", MaxLuminance: "10000000/10000",
+		},
+		ffprobeContentLight{SideDataType: "Content light level metadata", MaxContent: 1000, MaxAverage: 400},
+		ffprobeDOVIConfig{
+			SideDataType:   "DOVI configuration record",
+			DVVersionMajor: 1, DVProfile: 8, DVLevel: 6,
+			RPUPresentFlag: 1, BLPresentFlag: 1, DVBLSignalCompatibilityID: 1,
+		},
+	}
+	if got := ffprobeSideData(fields); !reflect.DeepEqual(got, want) {
+		t.Fatalf("side data:\n got %+v\nwant %+v", got, want)
+	}
+}
@@ -1,11 +1,253 @@
 package mediainfo
 
-func RenderGraphSVG(reports []Report) string {
-	_ = reports
-	return "<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// graphNode is one box of the stream graph: the container, a program, a stream or a
+// substream carried inside a stream (caption services, object audio, Dolby Vision layers).
+type graphNode struct {
+	kind     string
+	lines    []string
+	children []*graphNode
+}
+
+func (n *graphNode) add(child *graphNode) *graphNode {
+	n.children = append(n.children, child)
+	return child
+}
+
+// buildGraph returns the container → program → stream → substream tree of report.
+func buildGraph(report Report) *graphNode {
+	root := &graphNode{kind: "container"}
+	if report.Err != nil {
+		root.lines = graphLines(filepath.Base(report.Ref), "Error: "+report.Err.Error())
+		return root
+	}
+	general := report.General.Fields
+	root.lines = graphLines(
+		findField(general, "Format"),
+		filepath.Base(report.Ref),
+		findField(general, "Duration"),
+		findField(general, "Overall bit rate"),
+	)
+
+	containerFormat := findField(general, "Format")
+	type streamEntry struct {
+		node   *graphNode
+		fields typedFields
+		stream Stream
+		parent *graphNode
+	}
+	var entries []*streamEntry
+	var menus []*streamEntry
+	byKindPos := map[string]*streamEntry{}
+	videos := map[int]*streamEntry{}
+	forEachStreamWithKindIndex(orderTracks(report.Streams), func(stream Stream, index, total, order int) {
+		fields := typedFieldsFromJSON(buildJSONStreamFields(stream, order, 0, containerFormat))
+		entry := &streamEntry{fields: fields, stream: stream}
+		if stream.Kind == StreamMenu {
+			menus = append(menus, entry)
+			return
+		}
+		title := string(stream.Kind)
+		if total > 1 {
+			title += " #" + strconv.Itoa(index)
+		}
+		entry.node = &graphNode{kind: strings.ToLower(string(stream.Kind)), lines: graphStreamLines(title, stream)}
+		addGraphSubstreams(entry.node, stream, fields)
+		entries = append(entries, entry)
+		byKindPos[graphKindCode(stream.Kind)+":"+strconv.Itoa(index-1)] = entry
+		if stream.Kind == StreamVideo {
+			videos[index] = entry
+		}
+	})
+
+	// Captions muxed in a video stream hang below that stream.
+	for _, entry := range entries {
+		more := findField(entry.stream.Fields, "Muxing mode, more info")
+		if n, ok := strings.CutPrefix(more, "Muxed in Video #"); ok {
+			if index, err := strconv.Atoi(n); err == nil && videos[index] != nil {
+				entry.node.kind = "substream"
+				entry.parent = videos[index].node
+			}
+		}
+	}
+
+	for _, menu := range menus {
+		kinds := splitGraphList(menu.fields["List_StreamKind"])
+		positions := splitGraphList(menu.fields["List_StreamPos"])
+		if len(kinds) == 0 || len(kinds) != len(positions) {
+			// Chapter menus (Matroska, MP4) are not programs.
+			root.add(&graphNode{kind: "menu", lines: graphLines("Menu", findField(menu.stream.Fields, "Format"))})
+			continue
+		}
+		program := root.add(&graphNode{kind: "program", lines: graphLines(
+			"Program "+menu.fields["MenuID"],
+			graphLabel("ID", menu.fields["ID"]),
+			menu.fields["ServiceName"],
+			menu.fields["ServiceProvider"],
+		)})
+		for i, kind := range kinds {
+			if entry := byKindPos[kind+":"+positions[i]]; entry != nil && entry.parent == nil {
+				entry.parent = program
+			}
+		}
+	}
+	for _, entry := range entries {
+		if entry.parent == nil {
+			entry.parent = root
+		}
+		entry.parent.add(entry.node)
+	}
+	return root
+}
+
+func graphStreamLines(title string, stream Stream) []string {
+	fields := stream.Fields
+	format := findField(fields, "Format")
+	if profile := findField(fields, "Format profile"); profile != "" {
+		format += " " + profile
+	}
+	lines := []string{title, graphLabel("ID", findField(fields, "ID")), format}
+	switch stream.Kind {
+	case StreamVideo:
+		width := strings.TrimSuffix(findField(fields, "Width"), " pixels")
+		height := strings.TrimSuffix(findField(fields, "Height"), " pixels")
+		if width != "" && height != "" {
+			lines = append(lines, strings.ReplaceAll(width, " ", "")+"x"+strings.ReplaceAll(height, " ", ""))
+		}
+		lines = append(lines, findField(fields, "Frame rate"))
+	case StreamAudio:
+		lines = append(lines, findField(fields, "Channel(s)"), findField(fields, "Sampling rate"))
+	case StreamText:
+		lines = append(lines, findField(fields, "Language"))
+	}
+	lines = append(lines, findField(fields, "Bit rate"))
+	return graphLines(lines...)
+}
+
+// addGraphSubstreams adds the layers a stream carries in its own bitstream.
+func addGraphSubstreams(node *graphNode, stream Stream, fields typedFields) {
+	if strings.Contains(fields["Format_AdditionalFeatures"], "JOC") {
+		node.add(&graphNode{kind: "substream", lines: graphLines(
+			"JOC object layer",
+			graphLabel("Complexity index", findField(stream.Fields, "Complexity index")),
+			graphLabel("Dynamic objects", findField(stream.Fields, "Number of dynamic objects")),
+			graphLabel("Bed channels", findField(stream.Fields, "Bed channel count")),
+		)})
+	}
+	if strings.HasPrefix(fields["HDR_Format"], "Dolby Vision") {
+		settings, _, _ := strings.Cut(fields["HDR_Format_Settings"], " / ")
+		profile, _, _ := strings.Cut(fields["HDR_Format_Profile"], " / ")
+		names := map[string]string{"BL": "base layer", "EL": "enhancement layer", "RPU": "RPU metadata"}
+		for _, layer := range strings.Split(settings, "+") {
+			if name, ok := names[strings.TrimSpace(layer)]; ok {
+				node.add(&graphNode{kind: "substream", lines: graphLines("Dolby Vision "+name, graphLabel("Profile", profile))})
+			}
+		}
+	}
+}
+
+// graphKindCode is the MediaInfo stream kind number used by List_StreamKind.
+func graphKindCode(kind StreamKind) string {
+	switch kind {
+	case StreamVideo:
+		return "1"
+	case StreamAudio:
+		return "2"
+	case StreamText:
+		return "3"
+	case StreamImage:
+		return "5"
+	case StreamMenu:
+		return "6"
+	}
+	return "0"
+}
+
+func splitGraphList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	parts := strings.Split(value, " / ")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+func graphLabel(label, value string) string {
+	if value == "" {
+		return ""
+	}
+	return label + " " + value
+}
+
+// graphLines drops empty lines.
+func graphLines(lines ...string) []string {
+	out := lines[:0]
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			out = append(out, line)
+		}
+	}
+	return out
 }
 
+// RenderGraphDOT renders the stream graph of reports as Graphviz DOT, one cluster per file.
 func RenderGraphDOT(reports []Report) string {
-	_ = reports
-	return "digraph mediainfo {}"
+	var b strings.Builder
+	b.WriteString("digraph mediainfo {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\", fontsize=10];\n")
+	b.WriteString("\tedge [arrowsize=0.6];\n")
+	for i, report := range reports {
+		fmt.Fprintf(&b, "\tsubgraph cluster_%d {\n", i)
+		fmt.Fprintf(&b, "\t\tlabel=%s;\n", dotQuote(report.Ref))
+		counter := 0
+		writeDOTNode(&b, buildGraph(report), fmt.Sprintf("f%d_", i), &counter)
+		b.WriteString("\t}\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func writeDOTNode(b *strings.Builder, node *graphNode, prefix string, counter *int) string {
+	id := prefix + strconv.Itoa(*counter)
+	*counter++
+	fmt.Fprintf(b, "\t\t%s [label=%s, fillcolor=%s];\n", id, dotQuote(strings.Join(node.lines, "\n")), dotQuote(graphColor(node.kind)))
+	for _, child := range node.children {
+		childID := writeDOTNode(b, child, prefix, counter)
+		fmt.Fprintf(b, "\t\t%s -> %s;\n", id, childID)
+	}
+	return id
+}
+
+func dotQuote(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "\"", "\\\"")
+	value = strings.ReplaceAll(value, "\n", "\\n")
+	return "\"" + value + "\""
+}
+
+func graphColor(kind string) string {
+	switch kind {
+	case "container":
+		return "#dddddd"
+	case "program":
+		return "#cfdff5"
+	case "video":
+		return "#cdeacb"
+	case "audio":
+		return "#f6dfbd"
+	case "text":
+		return "#e6d8f0"
+	case "substream":
+		return "#f7f3d6"
+	}
+	return "#f2f2f2"
 }
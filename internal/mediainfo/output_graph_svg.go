@@ -0,0 +1,144 @@
+package mediainfo
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// SVG layout: the graph is drawn left to right, one column per tree depth. Leaves take
+// consecutive rows and parents are centered on their children, so edges never cross.
+const (
+	svgFontSize   = 12
+	svgCharWidth  = 7
+	svgLineHeight = 16
+	svgPadX       = 10
+	svgPadY       = 8
+	svgColumnGap  = 48
+	svgRowGap     = 12
+	svgFileGap    = 32
+	svgMargin     = 16
+	svgTitleSize  = 14
+)
+
+type svgBox struct {
+	node     *graphNode
+	depth    int
+	x, y     int
+	w, h     int
+	children []*svgBox
+}
+
+// RenderGraphSVG renders the stream graph of reports as a standalone SVG document. The
+// layout is computed here, so Graphviz is not needed.
+func RenderGraphSVG(reports []Report) string {
+	var body strings.Builder
+	width := 0
+	y := svgMargin
+	for _, report := range reports {
+		root := newSVGBox(buildGraph(report), 0)
+		columns := []int{}
+		measureSVGColumns(root, &columns)
+		lefts := make([]int, len(columns))
+		x := svgMargin
+		for i, w := range columns {
+			lefts[i] = x
+			x += w + svgColumnGap
+		}
+		width = max(width, x-svgColumnGap+svgMargin)
+
+		y += svgTitleSize
+		fmt.Fprintf(&body, "<text x=\"%d\" y=\"%d\" font-size=\"%d\" font-weight=\"bold\">%s</text>\n", svgMargin, y, svgTitleSize, xmlEscape(report.Ref))
+		y += svgRowGap
+		height := placeSVGBox(root, lefts, y)
+		writeSVGBox(&body, root)
+		y += height + svgFileGap
+	}
+	height := y - svgFileGap + svgMargin
+	if len(reports) == 0 {
+		width, height = 2*svgMargin, 2*svgMargin
+	}
+
+	var b strings.Builder
+	b.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"Helvetica, Arial, sans-serif\" font-size=\"%d\">\n", width, height, width, height, svgFontSize)
+	b.WriteString(body.String())
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+func newSVGBox(node *graphNode, depth int) *svgBox {
+	box := &svgBox{node: node, depth: depth}
+	longest := 0
+	for _, line := range node.lines {
+		longest = max(longest, utf8.RuneCountInString(line))
+	}
+	box.w = longest*svgCharWidth + 2*svgPadX
+	box.h = max(1, len(node.lines))*svgLineHeight + 2*svgPadY
+	for _, child := range node.children {
+		box.children = append(box.children, newSVGBox(child, depth+1))
+	}
+	return box
+}
+
+func measureSVGColumns(box *svgBox, columns *[]int) {
+	if len(*columns) <= box.depth {
+		*columns = append(*columns, 0)
+	}
+	(*columns)[box.depth] = max((*columns)[box.depth], box.w)
+	for _, child := range box.children {
+		measureSVGColumns(child, columns)
+	}
+}
+
+// placeSVGBox positions box and its subtree starting at top and returns the subtree height.
+func placeSVGBox(box *svgBox, lefts []int, top int) int {
+	box.x = lefts[box.depth]
+	childrenHeight := 0
+	for i, child := range box.children {
+		if i > 0 {
+			childrenHeight += svgRowGap
+		}
+		childrenHeight += subtreeSVGHeight(child)
+	}
+	height := max(box.h, childrenHeight)
+	childTop := top + (height-childrenHeight)/2
+	for _, child := range box.children {
+		childTop += placeSVGBox(child, lefts, childTop) + svgRowGap
+	}
+	box.y = top + (height-box.h)/2
+	return height
+}
+
+func subtreeSVGHeight(box *svgBox) int {
+	childrenHeight := 0
+	for i, child := range box.children {
+		if i > 0 {
+			childrenHeight += svgRowGap
+		}
+		childrenHeight += subtreeSVGHeight(child)
+	}
+	return max(box.h, childrenHeight)
+}
+
+func writeSVGBox(b *strings.Builder, box *svgBox) {
+	for _, child := range box.children {
+		x1, y1 := box.x+box.w, box.y+box.h/2
+		x2, y2 := child.x, child.y+child.h/2
+		mid := (x1 + x2) / 2
+		fmt.Fprintf(b, "<path d=\"M%d,%d C%d,%d %d,%d %d,%d\" fill=\"none\" stroke=\"#666666\"/>\n", x1, y1, mid, y1, mid, y2, x2, y2)
+	}
+	fmt.Fprintf(b, "<g class=\"%s\">\n", box.node.kind)
+	fmt.Fprintf(b, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" rx=\"6\" fill=\"%s\" stroke=\"#555555\"/>\n", box.x, box.y, box.w, box.h, graphColor(box.node.kind))
+	for i, line := range box.node.lines {
+		weight := ""
+		if i == 0 {
+			weight = " font-weight=\"bold\""
+		}
+		fmt.Fprintf(b, "<text x=\"%d\" y=\"%d\"%s>%s</text>\n", box.x+svgPadX, box.y+svgPadY+(i+1)*svgLineHeight-4, weight, xmlEscape(line))
+	}
+	b.WriteString("</g>\n")
+	for _, child := range box.children {
+		writeSVGBox(b, child)
+	}
+}
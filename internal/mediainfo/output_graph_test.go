@@ -0,0 +1,85 @@
+package mediainfo
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func graphTestReport() Report {
+	return Report{
+		Ref: "show.ts",
+		General: Stream{Kind: StreamGeneral, Fields: []Field{
+			{Name: "Format", Value: "MPEG-TS"},
+		}},
+		Streams: []Stream{
+			{Kind: StreamVideo, Fields: []Field{
+				{Name: "ID", Value: "256 (0x100)"},
+				{Name: "Format", Value: "HEVC"},
+			}, JSON: map[string]string{
+				"HDR_Format":          "Dolby Vision",
+				"HDR_Format_Profile":  "dvhe.07",
+				"HDR_Format_Settings": "BL+EL+RPU",
+			}},
+			{Kind: StreamAudio, Fields: []Field{
+				{Name: "ID", Value: "257 (0x101)"},
+				{Name: "Format", Value: "E-AC-3"},
+				{Name: "Complexity index", Value: "16"},
+			}, JSON: map[string]string{"Format_AdditionalFeatures": "JOC"}},
+			{Kind: StreamText, Fields: []Field{
+				{Name: "ID", Value: "256 (0x100)-608"},
+				{Name: "Format", Value: "EIA-608"},
+				{Name: "Muxing mode, more info", Value: "Muxed in Video #1"},
+			}},
+			{Kind: StreamMenu, Fields: []Field{{Name: "ID", Value: "4096"}}, JSON: map[string]string{
+				"MenuID":          "1",
+				"List_StreamKind": "1 / 2 / 3",
+				"List_StreamPos":  "0 / 0 / 0",
+			}},
+		},
+	}
+}
+
+func TestBuildGraphHierarchy(t *testing.T) {
+	root := buildGraph(graphTestReport())
+	var walk func(node *graphNode, depth int, out *[]string)
+	walk = func(node *graphNode, depth int, out *[]string) {
+		*out = append(*out, strings.Repeat(" ", depth)+node.lines[0])
+		for _, child := range node.children {
+			walk(child, depth+1, out)
+		}
+	}
+	var got []string
+	walk(root, 0, &got)
+	want := []string{
+		"MPEG-TS",
+		" Program 1",
+		"  Video",
+		"   Dolby Vision base layer",
+		"   Dolby Vision enhancement layer",
+		"   Dolby Vision RPU metadata",
+		"   Text",
+		"  Audio",
+		"   JOC object layer",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("graph:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestRenderGraphOutputs(t *testing.T) {
+	reports := []Report{graphTestReport()}
+	dot := RenderGraphDOT(reports)
+	for _, want := range []string{"digraph mediainfo {", "subgraph cluster_0", "f0_0 -> f0_1;", `label="JOC object layer\nComplexity index 16"`} {
+		if !strings.Contains(dot, want) {
+			t.Fatalf("DOT missing %q:\n%s", want, dot)
+		}
+	}
+	svg := RenderGraphSVG(reports)
+	if err := xml.Unmarshal([]byte(svg), new(struct{})); err != nil {
+		t.Fatalf("SVG is not well-formed: %v", err)
+	}
+	if got := strings.Count(svg, "<rect "); got != 9 {
+		t.Fatalf("SVG has %d boxes, want 9", got)
+	}
+}
@@ -6,13 +6,26 @@ import (
 )
 
 func RenderHTML(reports []Report) string {
+	return RenderHTMLWithOptions(reports, RenderOptions{})
+}
+
+// RenderHTMLWithOptions renders reports as an HTML table, honoring opts.
+func RenderHTMLWithOptions(reports []Report, opts RenderOptions) string {
+	if opts.Full {
+		reports = fullReports(reports)
+	}
 	var buf bytes.Buffer
 	buf.WriteString("<html><head><meta charset=\"utf-8\"/></head><body>")
 	for _, report := range reports {
 		buf.WriteString("<table>")
-		buf.WriteString(renderHTMLStream("General", report.General))
+		if report.Err != nil {
+			buf.WriteString(renderHTMLStream("General", failedStream(report), opts))
+			buf.WriteString("</table>")
+			continue
+		}
+		buf.WriteString(renderHTMLStream("General", report.General, opts))
 		for _, entry := range enumerateStreams(report.Streams) {
-			buf.WriteString(renderHTMLStream(entry.Title, entry.Stream))
+			buf.WriteString(renderHTMLStream(entry.Title, entry.Stream, opts))
 		}
 		buf.WriteString("</table>")
 	}
@@ -20,8 +33,14 @@ func RenderHTML(reports []Report) string {
 	return buf.String()
 }
 
-func renderHTMLStream(title string, stream Stream) string {
-	fields := orderFieldsForJSON(stream.Kind, stream.Fields)
+func renderHTMLStream(title string, stream Stream, opts RenderOptions) string {
+	fields := stream.Fields
+	if !opts.Full {
+		// The complete field set keeps its own order.
+		fields = orderFieldsForJSON(stream.Kind, fields)
+	}
+	fields = opts.Language.localizeStream(Stream{Kind: stream.Kind, Fields: fields}).Fields
+	title = opts.Language.title(title)
 	var buf bytes.Buffer
 	buf.WriteString("<tr><th colspan=\"2\">")
 	buf.WriteString(html.EscapeString(title))
@@ -8,6 +8,7 @@ import (
 type jsonMediaOut struct {
 	Ref    string
 	Tracks []jsonTrackOut
+	Error  string
 }
 
 type jsonTrackOut struct {
@@ -73,15 +74,19 @@ func renderJSONMedia(media jsonMediaOut) string {
 	for _, track := range media.Tracks {
 		tracks = append(tracks, renderJSONTrack(track.Fields))
 	}
-	return renderJSONMediaObject(media.Ref, tracks)
+	return renderJSONMediaObject(media.Ref, tracks, media.Error)
 }
 
-func renderJSONMediaObject(ref string, tracks []string) string {
+func renderJSONMediaObject(ref string, tracks []string, errMsg string) string {
 	var buf bytes.Buffer
 	buf.WriteString("{")
 	writeJSONField(&buf, "@ref", ref, false)
 	buf.WriteString(",")
 	writeJSONField(&buf, "track", renderJSONArray(tracks, false), true)
+	if errMsg != "" {
+		buf.WriteString(",")
+		writeJSONField(&buf, "error", errMsg, false)
+	}
 	buf.WriteString("}")
 	return buf.String()
 }
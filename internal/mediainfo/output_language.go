@@ -0,0 +1,204 @@
+package mediainfo
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Language translates the field names and value words of the text and HTML reports.
+//
+// Translations are keyed like upstream MediaInfo language files: field names by their
+// internal name (the JSON key, e.g. "BitRate" or "Format_Settings_CABAC"), value words by
+// the English word ("Yes", "Variable", "channels", "kb/s"). An entry keyed by the English
+// field name is used when the internal name has none. Missing entries stay in English.
+type Language struct {
+	// Code is the ISO 639 code of the language, or "raw".
+	Code string
+
+	raw          bool
+	translations map[string]string
+}
+
+// RawLanguage returns the "raw" language, which prints internal field names (the JSON
+// keys) instead of display names and leaves values untouched.
+func RawLanguage() *Language {
+	return &Language{Code: "raw", raw: true}
+}
+
+//go:embed language.csv
+var builtinLanguages string
+
+// BuiltinLanguage returns the translation embedded for code ("de", "es", "fr" or "it"). A
+// region suffix ("fr-CA", "de_AT") falls back to the primary language. ok is false when
+// no translation is embedded for code; English needs none.
+func BuiltinLanguage(code string) (lang *Language, ok bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if primary, _, found := strings.Cut(strings.ReplaceAll(code, "_", "-"), "-"); found {
+		code = primary
+	}
+	if code == "" || !slices.Contains(BuiltinLanguageCodes(), code) {
+		return nil, false
+	}
+	lang, err := LoadLanguageCSV(strings.NewReader(builtinLanguages), code)
+	if err != nil {
+		return nil, false
+	}
+	return lang, true
+}
+
+// BuiltinLanguageCodes lists the codes BuiltinLanguage knows.
+func BuiltinLanguageCodes() []string {
+	header, _, _ := strings.Cut(builtinLanguages, "\n")
+	return strings.Split(strings.TrimPrefix(header, "Language_ISO639;"), ";")
+}
+
+// LoadLanguageCSV reads a MediaInfo language file: semicolon separated "key;translation"
+// lines. Files with several translation columns (the upstream master table, whose first
+// line is "Language_ISO639;en;fr;...") are accepted too; code picks the column, and the
+// first translation column is used when code is empty.
+func LoadLanguageCSV(r io.Reader, code string) (*Language, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = ';'
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	lang := &Language{Code: code, translations: map[string]string{}}
+	column := 1
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("language file: %w", err)
+		}
+		if len(record) == 0 {
+			continue
+		}
+		key := strings.TrimSpace(strings.TrimPrefix(record[0], "\ufeff"))
+		if key == "Language_ISO639" {
+			if code == "" && len(record) > 1 {
+				lang.Code = strings.TrimSpace(record[1])
+			}
+			if len(record) > 2 && code != "" {
+				column = -1
+				for i, name := range record[1:] {
+					if strings.EqualFold(strings.TrimSpace(name), code) {
+						column = i + 1
+					}
+				}
+				if column < 0 {
+					return nil, fmt.Errorf("language file: no column for language %q", code)
+				}
+			}
+			continue
+		}
+		if key == "" || column >= len(record) {
+			continue
+		}
+		if value := record[column]; value != "" {
+			lang.translations[key] = value
+		}
+	}
+	if len(lang.translations) == 0 {
+		return nil, errors.New("language file: no translations")
+	}
+	return lang, nil
+}
+
+// localizeStream returns stream with translated field names and values. A nil language
+// is English.
+func (l *Language) localizeStream(stream Stream) Stream {
+	if l == nil {
+		return stream
+	}
+	fields := make([]Field, len(stream.Fields))
+	for i, field := range stream.Fields {
+		fields[i] = Field{Name: l.fieldName(stream.Kind, field), Value: l.value(field.Value)}
+	}
+	stream.Fields = fields
+	return stream
+}
+
+func (l *Language) fieldName(kind StreamKind, field Field) string {
+	key := languageKey(kind, field)
+	if l.raw {
+		return key
+	}
+	if text := l.translations[key]; text != "" {
+		return text
+	}
+	if text := l.translations[field.Name]; text != "" {
+		return text
+	}
+	return field.Name
+}
+
+// value translates value as a whole or, failing that, the unit words following numbers.
+func (l *Language) value(value string) string {
+	if l == nil || l.raw || value == "" {
+		return value
+	}
+	if text := l.translations[value]; text != "" {
+		return text
+	}
+	// Only units are translated inside a value; descriptions ("Advanced Video Codec") and
+	// names stay as they are.
+	words := strings.Split(value, " ")
+	for i, word := range words {
+		if i == 0 || words[i-1] == "" || !unicode.IsDigit(rune(words[i-1][len(words[i-1])-1])) {
+			continue
+		}
+		if text := l.translations[word]; text != "" {
+			words[i] = text
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// title translates a stream title such as "Audio #2".
+func (l *Language) title(title string) string {
+	if l == nil || l.raw {
+		return title
+	}
+	kind, number, _ := strings.Cut(title, " ")
+	if text := l.translations[kind]; text != "" {
+		kind = text
+	}
+	if number == "" {
+		return kind
+	}
+	return kind + " " + number
+}
+
+// languageKey returns the internal name of a display field: its registered name, its JSON
+// key, or the display name in CamelCase for undeclared fields.
+func languageKey(kind StreamKind, field Field) string {
+	if info, ok := lookupField(kind, field.Name); ok {
+		return info.Name
+	}
+	for _, kv := range mapStreamFieldsToJSON(kind, []Field{field}) {
+		if !kv.Raw {
+			return kv.Key
+		}
+	}
+	if !strings.Contains(field.Name, " ") {
+		return field.Name
+	}
+	var b strings.Builder
+	for _, word := range strings.Fields(field.Name) {
+		word = strings.Trim(word, ",()")
+		if word == "" {
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
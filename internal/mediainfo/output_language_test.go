@@ -0,0 +1,100 @@
+package mediainfo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderTextRawLanguage(t *testing.T) {
+	report, err := AnalyzeFile("samples/sample.mp4")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	out := RenderTextWithOptions([]Report{report}, RenderOptions{Language: RawLanguage()})
+	for _, want := range []string{
+		"CompleteName                             : samples/sample.mp4\n",
+		"OverallBitRate                           : 555 kb/s\n",
+		"Format_Settings_CABAC                    : Yes\n",
+		"Format_Info                              : Advanced Video Codec\n",
+		"BitRate_Mode                             : Constant\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	full := RenderTextWithOptions([]Report{report}, RenderOptions{Full: true, Language: RawLanguage()})
+	for _, want := range []string{"StreamCount ", "StreamKindID ", "StreamSize_Proportion ", "VideoCount "} {
+		if !strings.Contains(full, "\n"+want) {
+			t.Fatalf("missing %q in full raw output:\n%s", want, full)
+		}
+	}
+}
+
+func TestLoadLanguageCSV(t *testing.T) {
+	report, err := AnalyzeFile("samples/sample.mp4")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	const table = "\ufeff  Language_ISO639;en;fr\n" +
+		"Video;Video;Vidéo\n" +
+		"BitRate;Bit rate;Débit\n" +
+		"Channel(s);Channel(s);Canal(aux)\n" +
+		"Yes;Yes;Oui\n" +
+		"channel;channel;canal\n" +
+		"Video Codec;;\n"
+	lang, err := LoadLanguageCSV(strings.NewReader(table), "fr")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	out := RenderTextWithOptions([]Report{report}, RenderOptions{Language: lang})
+	for _, want := range []string{
+		"\nVidéo\n",
+		"Débit                                    : 450 kb/s\n",
+		"Canal(aux)                               : 1 canal\n",
+		"Default                                  : Oui\n",
+		"Format/Info                              : Advanced Video Codec\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	html := RenderHTMLWithOptions([]Report{report}, RenderOptions{Language: lang})
+	if !strings.Contains(html, "<td>Débit</td><td>450 kb/s</td>") {
+		t.Fatalf("html not translated:\n%s", html)
+	}
+
+	if _, err := LoadLanguageCSV(strings.NewReader(table), "de"); err == nil {
+		t.Fatalf("expected error for missing column")
+	}
+	if _, err := LoadLanguageCSV(strings.NewReader("\n"), ""); err == nil {
+		t.Fatalf("expected error for empty file")
+	}
+}
+
+func TestBuiltinLanguage(t *testing.T) {
+	report, err := AnalyzeFile("samples/sample.mkv")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	lang, ok := BuiltinLanguage("de_DE")
+	if !ok || lang.Code != "de" {
+		t.Fatalf("builtin de: %v %v", lang, ok)
+	}
+	out := RenderTextWithOptions([]Report{report}, RenderOptions{Language: lang})
+	for _, want := range []string{"Allgemein\n", "\nDauer ", "\nBreite ", "\nHöhe ", ": 640 Pixel\n"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	fr, ok := BuiltinLanguage("fr")
+	if !ok {
+		t.Fatalf("builtin fr missing")
+	}
+	if got := RenderTextWithOptions([]Report{report}, RenderOptions{Language: fr}); !strings.Contains(got, "\nDurée ") {
+		t.Fatalf("fr not selected:\n%s", got)
+	}
+	if _, ok := BuiltinLanguage("xx"); ok {
+		t.Fatalf("unexpected builtin xx")
+	}
+}
@@ -0,0 +1,54 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// Encoder writes reports as newline-delimited JSON: each report becomes the single-file
+// JSON document ({"creatingLibrary":...,"media":...}) compacted onto one line.
+//
+// An Encoder is not safe for concurrent use; pair it with AnalyzeFilesFunc, which calls
+// its callback from one goroutine in input order.
+type Encoder struct {
+	w io.Writer
+}
+
+// NewEncoder returns an Encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Write encodes report as one line. Each line is written with a single Write call.
+func (e *Encoder) Write(report Report) error {
+	line, err := renderNDJSONLine(report)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(line)
+	return err
+}
+
+// RenderNDJSON renders reports as newline-delimited JSON, one line per report.
+func RenderNDJSON(reports []Report) string {
+	var b strings.Builder
+	for _, report := range reports {
+		line, err := renderNDJSONLine(report)
+		if err != nil {
+			continue
+		}
+		b.Write(line)
+	}
+	return b.String()
+}
+
+func renderNDJSONLine(report Report) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(renderJSONPayload(buildJSONPayload(report)))); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,70 @@
+package mediainfo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncoderStreamsReportsInOrder(t *testing.T) {
+	paths := []string{"samples/sample.mkv", "samples/missing.mkv", "samples/sample.mp4", "samples/sample.flac"}
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	var lines int
+	count, err := AnalyzeFilesFunc(context.Background(), paths, AnalyzeOptions{Concurrency: 3}, func(report Report) error {
+		if err := enc.Write(report); err != nil {
+			return err
+		}
+		// Each report is on the writer before the next one is handed over.
+		lines++
+		if got := strings.Count(buf.String(), "\n"); got != lines {
+			t.Fatalf("after %d reports the output has %d lines", lines, got)
+		}
+		return nil
+	})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Failures) != 1 {
+		t.Fatalf("err=%v, want one failure", err)
+	}
+	if count != 3 {
+		t.Fatalf("count=%d, want 3", count)
+	}
+
+	out := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(out) != len(paths) {
+		t.Fatalf("got %d lines, want %d", len(out), len(paths))
+	}
+	for i, line := range out {
+		var doc struct {
+			Media struct {
+				Ref string `json:"@ref"`
+			} `json:"media"`
+		}
+		if err := json.Unmarshal([]byte(line), &doc); err != nil {
+			t.Fatalf("line %d: %v", i, err)
+		}
+		if doc.Media.Ref != paths[i] {
+			t.Fatalf("line %d ref=%q, want %q", i, doc.Media.Ref, paths[i])
+		}
+	}
+
+	reports, _, _ := AnalyzeFilesWithOptions(paths, AnalyzeOptions{})
+	if RenderNDJSON(reports) != buf.String() {
+		t.Fatalf("RenderNDJSON differs from the streamed output")
+	}
+}
+
+func TestAnalyzeFilesFuncStopsOnCallbackError(t *testing.T) {
+	stop := errors.New("stop")
+	calls := 0
+	_, err := AnalyzeFilesFunc(context.Background(), []string{"samples/sample.mkv", "samples/sample.mp4", "samples/sample.flac"}, AnalyzeOptions{}, func(Report) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("err=%v calls=%d, want stop after 1 call", err, calls)
+	}
+}
@@ -1,6 +1,307 @@
 package mediainfo
 
+import (
+	"bytes"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	pbcoreNS              = "http://www.pbcore.org/PBCore/PBCoreNamespace.html"
+	pbcore1SchemaLocation = "http://www.pbcore.org/PBCore/PBCoreXSD_Ver_1-2-1.xsd"
+	pbcore2SchemaLocation = "https://raw.githubusercontent.com/WGBH-MLA/PBCore_2.1/master/pbcore-2.1.xsd"
+)
+
+// pbcoreVersion selects the schema generation. 1.x nests every value in child elements
+// (formatDigital, pbcoreFormatID/formatIdentifier, ...) while 2.x uses instantiation*
+// elements with attributes (source, unitsOfMeasure, annotationType).
+type pbcoreVersion int
+
+const (
+	pbcore1 pbcoreVersion = 1
+	pbcore2 pbcoreVersion = 2
+)
+
+// RenderPBCore renders reports as a PBCore 1.2.1 PBCoreDescriptionDocument with one
+// pbcoreInstantiation per file.
 func RenderPBCore(reports []Report) string {
-	_ = reports
-	return "<pbcoreDescriptionDocument></pbcoreDescriptionDocument>"
+	return renderPBCore(reports, pbcore1)
+}
+
+// RenderPBCore2 renders reports as PBCore 2.1: a pbcoreInstantiationDocument for a single
+// file, a pbcoreDescriptionDocument with one pbcoreInstantiation per file otherwise.
+func RenderPBCore2(reports []Report) string {
+	return renderPBCore(reports, pbcore2)
+}
+
+func renderPBCore(reports []Report, version pbcoreVersion) string {
+	var buf bytes.Buffer
+	buf.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	buf.WriteString("<!-- Generated by " + AppName + " " + FormatVersion(AppVersion) + " -->\n")
+	var root *xmlNode
+	if version == pbcore2 && len(reports) == 1 {
+		root = newXMLNode("pbcoreInstantiationDocument")
+		buildPBCoreInstantiation(root, reports[0], version)
+	} else {
+		root = buildPBCoreDescription(reports, version)
+	}
+	schema := pbcore2SchemaLocation
+	if version == pbcore1 {
+		schema = pbcore1SchemaLocation
+	}
+	root.attrs = append([]xmlAttr{
+		{name: "xmlns", value: pbcoreNS},
+		{name: "xmlns:xsi", value: "http://www.w3.org/2001/XMLSchema-instance"},
+		{name: "xsi:schemaLocation", value: pbcoreNS + " " + schema},
+	}, root.attrs...)
+	root.writeXML(&buf, 0)
+	return buf.String()
+}
+
+func buildPBCoreDescription(reports []Report, version pbcoreVersion) *xmlNode {
+	name := ""
+	if len(reports) > 0 {
+		name = filepath.Base(reports[0].Ref)
+	}
+	if version == pbcore1 {
+		doc := newXMLNode("PBCoreDescriptionDocument")
+		identifier := doc.add(newXMLNode("pbcoreIdentifier"))
+		identifier.add(&xmlNode{name: "identifier", text: name})
+		identifier.add(&xmlNode{name: "identifierSource", text: "File Name"})
+		doc.add(newXMLNode("pbcoreTitle")).add(&xmlNode{name: "title", text: name})
+		description := doc.add(newXMLNode("pbcoreDescription"))
+		description.add(newXMLNode("description"))
+		description.add(newXMLNode("descriptionType"))
+		for _, report := range reports {
+			buildPBCoreInstantiation(doc.add(newXMLNode("pbcoreInstantiation")), report, version)
+		}
+		return doc
+	}
+	doc := newXMLNode("pbcoreDescriptionDocument")
+	doc.add(&xmlNode{name: "pbcoreIdentifier", attrs: []xmlAttr{{name: "source", value: "File Name"}}, text: name})
+	doc.add(&xmlNode{name: "pbcoreTitle", text: name})
+	doc.add(newXMLNode("pbcoreDescription"))
+	for _, report := range reports {
+		buildPBCoreInstantiation(doc.add(newXMLNode("pbcoreInstantiation")), report, version)
+	}
+	return doc
+}
+
+// buildPBCoreInstantiation fills inst in schema order.
+func buildPBCoreInstantiation(inst *xmlNode, report Report, version pbcoreVersion) {
+	v1 := version == pbcore1
+	name := filepath.Base(report.Ref)
+	if report.Ref == "" {
+		name = "unknown"
+	}
+	if v1 {
+		id := inst.add(newXMLNode("pbcoreFormatID"))
+		id.add(&xmlNode{name: "formatIdentifier", text: name})
+		id.add(&xmlNode{name: "formatIdentifierSource", text: "File Name"})
+	} else {
+		inst.add(&xmlNode{name: "instantiationIdentifier", attrs: []xmlAttr{{name: "source", value: "File Name"}}, text: name})
+	}
+
+	if report.Err != nil {
+		pbcoreText(inst, v1, "formatDigital", "instantiationDigital", "application/octet-stream")
+		pbcoreText(inst, v1, "formatLocation", "instantiationLocation", report.Ref)
+		pbcoreAnnotations(inst, v1, "pbcoreAnnotation", "instantiationAnnotation", []xmlAttr{{name: "Error", value: report.Err.Error()}})
+		return
+	}
+
+	general := typedFieldsFromJSON(buildJSONGeneralFields(report))
+	if date := general["Encoded_Date"]; date != "" {
+		if v1 {
+			inst.addText("dateCreated", date)
+		} else {
+			inst.addText("instantiationDate", date, xmlAttr{name: "dateType", value: "encoded"})
+		}
+	}
+	counts := countStreams(report.Streams)
+	pbcoreText(inst, v1, "formatDigital", "instantiationDigital", pbcoreMimeType(general["Format"], counts[StreamVideo] > 0))
+	pbcoreText(inst, v1, "formatLocation", "instantiationLocation", report.Ref)
+	mediaType := "Sound"
+	switch {
+	case counts[StreamVideo] > 0:
+		mediaType = "Moving Image"
+	case counts[StreamAudio] == 0 && counts[StreamImage] > 0:
+		mediaType = "Static Image"
+	case counts[StreamAudio] == 0:
+		mediaType = "Text"
+	}
+	pbcoreText(inst, v1, "formatMediaType", "instantiationMediaType", mediaType)
+	pbcoreMeasure(inst, v1, "formatFileSize", "instantiationFileSize", general["FileSize"], "byte")
+	pbcoreText(inst, v1, "formatDuration", "instantiationDuration", pbcoreDuration(general["Duration"]))
+	pbcoreMeasure(inst, v1, "formatDataRate", "instantiationDataRate", general["OverallBitRate"], "bit/s")
+
+	containerFormat := findField(report.General.Fields, "Format")
+	var tracks []*xmlNode
+	forEachStreamWithKindIndex(orderTracks(report.Streams), func(stream Stream, _, _, order int) {
+		if stream.Kind != StreamVideo && stream.Kind != StreamAudio && stream.Kind != StreamText {
+			return
+		}
+		fields := typedFieldsFromJSON(buildJSONStreamFields(stream, order, 0, containerFormat))
+		tracks = append(tracks, buildPBCoreEssenceTrack(stream, fields, version))
+	})
+	if len(tracks) > 0 {
+		pbcoreText(inst, v1, "formatTracks", "instantiationTracks", strconv.Itoa(len(tracks)))
+	}
+	inst.children = append(inst.children, tracks...)
+
+	pbcoreAnnotations(inst, v1, "pbcoreAnnotation", "instantiationAnnotation", nonEmptyAttrs(
+		xmlAttr{name: "Format", value: general["Format"]},
+		xmlAttr{name: "Format_Profile", value: general["Format_Profile"]},
+		xmlAttr{name: "Writing application", value: general["Encoded_Application"]},
+		xmlAttr{name: "Writing library", value: general["Encoded_Library"]},
+	))
+}
+
+func buildPBCoreEssenceTrack(stream Stream, fields typedFields, version pbcoreVersion) *xmlNode {
+	v1 := version == pbcore1
+	name := "instantiationEssenceTrack"
+	if v1 {
+		name = "pbcoreEssenceTrack"
+	}
+	track := newXMLNode(name)
+	track.addText("essenceTrackType", string(stream.Kind))
+	if id := fields["ID"]; id != "" {
+		if v1 {
+			track.addText("essenceTrackIdentifier", id)
+			track.addText("essenceTrackIdentifierSource", "ID (MediaInfo)")
+		} else {
+			track.addText("essenceTrackIdentifier", id, xmlAttr{name: "source", value: "ID (MediaInfo)"})
+		}
+	}
+	track.addText("essenceTrackStandard", fields["Standard"])
+	track.addText("essenceTrackEncoding", fields["Format"])
+
+	dataRate := fields["BitRate"]
+	frameRate := fields["FrameRate"]
+	samplingRate := fields["SamplingRate"]
+	frameSize := ""
+	if fields["Width"] != "" && fields["Height"] != "" {
+		frameSize = fields["Width"] + "x" + fields["Height"]
+	}
+	aspect := ""
+	if stream.Kind == StreamVideo {
+		aspect = findField(stream.Fields, "Display aspect ratio")
+	}
+	duration := pbcoreDuration(fields["Duration"])
+	annotations := nonEmptyAttrs(
+		xmlAttr{name: "CodecID", value: fields["CodecID"]},
+		xmlAttr{name: "Format_Profile", value: profileAtLevel(fields["Format_Profile"], fields["Format_Level"])},
+		xmlAttr{name: "Channels", value: fields["Channels"]},
+		xmlAttr{name: "ChannelLayout", value: fields["ChannelLayout"]},
+		xmlAttr{name: "ColorSpace", value: fields["ColorSpace"]},
+		xmlAttr{name: "ChromaSubsampling", value: fields["ChromaSubsampling"]},
+		xmlAttr{name: "ScanType", value: fields["ScanType"]},
+		xmlAttr{name: "StreamSize", value: fields["StreamSize"]},
+		xmlAttr{name: "Title", value: fields["Title"]},
+	)
+
+	// 1.x and 2.x order the essence track children differently.
+	if v1 {
+		track.addText("essenceTrackDataRate", dataRate)
+		track.addText("essenceTrackDuration", duration)
+		track.addText("essenceTrackBitDepth", fields["BitDepth"])
+		track.addText("essenceTrackSamplingRate", samplingRate)
+		track.addText("essenceTrackFrameSize", frameSize)
+		track.addText("essenceTrackAspectRatio", aspect)
+		track.addText("essenceTrackFrameRate", frameRate)
+		track.addText("essenceTrackLanguage", fields["Language"])
+		if len(annotations) > 0 {
+			parts := make([]string, 0, len(annotations))
+			for _, annotation := range annotations {
+				parts = append(parts, annotation.name+": "+annotation.value)
+			}
+			track.addText("essenceTrackAnnotation", strings.Join(parts, "; "))
+		}
+		return track
+	}
+	pbcoreMeasure(track, false, "", "essenceTrackDataRate", dataRate, "bit/s")
+	pbcoreMeasure(track, false, "", "essenceTrackFrameRate", frameRate, "fps")
+	pbcoreMeasure(track, false, "", "essenceTrackSamplingRate", samplingRate, "Hz")
+	track.addText("essenceTrackBitDepth", fields["BitDepth"])
+	track.addText("essenceTrackFrameSize", frameSize, xmlAttr{name: "unitsOfMeasure", value: "pixel"})
+	track.addText("essenceTrackAspectRatio", aspect)
+	track.addText("essenceTrackDuration", duration)
+	track.addText("essenceTrackLanguage", fields["Language"])
+	pbcoreAnnotations(track, false, "", "essenceTrackAnnotation", annotations)
+	return track
+}
+
+// pbcoreText adds the 1.x or 2.x element for value.
+func pbcoreText(parent *xmlNode, v1 bool, name1, name2, value string) {
+	if v1 {
+		parent.addText(name1, value)
+		return
+	}
+	parent.addText(name2, value)
+}
+
+// pbcoreMeasure adds a numeric element; 2.x carries the unit in unitsOfMeasure.
+func pbcoreMeasure(parent *xmlNode, v1 bool, name1, name2, value, unit string) {
+	if v1 {
+		parent.addText(name1, value)
+		return
+	}
+	parent.addText(name2, value, xmlAttr{name: "unitsOfMeasure", value: unit})
+}
+
+// pbcoreAnnotations adds one annotation per entry: 2.x labels it with annotationType, 1.x
+// nests the text in an annotation element.
+func pbcoreAnnotations(parent *xmlNode, v1 bool, name1, name2 string, entries []xmlAttr) {
+	for _, entry := range entries {
+		if v1 {
+			parent.add(newXMLNode(name1)).addText("annotation", entry.name+": "+entry.value)
+			continue
+		}
+		parent.addText(name2, entry.value, xmlAttr{name: "annotationType", value: entry.name})
+	}
+}
+
+// pbcoreDuration formats JSON seconds as HH:MM:SS.mmm.
+func pbcoreDuration(value string) string {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 {
+		return ""
+	}
+	return formatClockDuration(seconds)
+}
+
+func pbcoreMimeType(format string, hasVideo bool) string {
+	kind := "audio"
+	if hasVideo {
+		kind = "video"
+	}
+	switch format {
+	case "Matroska":
+		return kind + "/x-matroska"
+	case "WebM":
+		return kind + "/webm"
+	case "MPEG-4":
+		return kind + "/mp4"
+	case "QuickTime":
+		return "video/quicktime"
+	case "MPEG-TS", "BDAV":
+		return "video/MP2T"
+	case "MPEG-PS", "DVD Video":
+		return "video/MP2P"
+	case "MPEG Video":
+		return "video/mpeg"
+	case "AVI":
+		return "video/x-msvideo"
+	case "Ogg":
+		return kind + "/ogg"
+	case "MPEG Audio":
+		return "audio/mpeg"
+	case "FLAC":
+		return "audio/flac"
+	case "Wave":
+		return "audio/wav"
+	case "AIFF":
+		return "audio/aiff"
+	}
+	return "application/octet-stream"
 }
@@ -0,0 +1,84 @@
+package mediainfo
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestPBCoreValidatesAgainstSchema checks both PBCore generations against the official
+// schemas in testdata/pbcore with xmllint; nothing is fetched from the network. Locally a
+// missing xmllint or schema skips the test; under CI it fails it.
+func TestPBCoreValidatesAgainstSchema(t *testing.T) {
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		skipOutsideCI(t, "xmllint not installed")
+	}
+	var reports []Report
+	for _, sample := range []string{"sample.mkv", "sample.ts", "sample.mp3", "sample.vob"} {
+		report, err := AnalyzeFile(filepath.Join("samples", sample))
+		if err != nil {
+			t.Fatalf("analyze %s: %v", sample, err)
+		}
+		reports = append(reports, report)
+	}
+	failed := Report{Ref: "missing.mkv", Err: errors.New("open missing.mkv: no such file")}
+
+	cases := []struct {
+		name    string
+		schema  string
+		render  func([]Report) string
+		reports []Report
+	}{
+		{name: "pbcore1", schema: "pbcore-1.2.1.xsd", render: RenderPBCore, reports: append(reports, failed)},
+		{name: "pbcore2-single", schema: "pbcore-2.1.xsd", render: RenderPBCore2, reports: reports[:1]},
+		{name: "pbcore2-multi", schema: "pbcore-2.1.xsd", render: RenderPBCore2, reports: append(reports, failed)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			schema := filepath.Join("internal", "mediainfo", "testdata", "pbcore", tc.schema)
+			if _, err := os.Stat(schema); err != nil {
+				skipOutsideCI(t, "official schema "+tc.schema+" missing (see testdata/pbcore/README.md)")
+			}
+			doc := filepath.Join(t.TempDir(), "doc.xml")
+			if err := os.WriteFile(doc, []byte(tc.render(tc.reports)), 0o600); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			out, err := exec.Command(xmllint, "--noout", "--nonet", "--schema", schema, doc).CombinedOutput()
+			if err != nil {
+				t.Fatalf("xmllint: %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+func skipOutsideCI(t *testing.T, reason string) {
+	t.Helper()
+	if os.Getenv("CI") != "" {
+		t.Fatal(reason)
+	}
+	t.Skip(reason)
+}
+
+func TestPBCore2EssenceTrack(t *testing.T) {
+	report, err := AnalyzeFile("samples/sample.mkv")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	out := RenderPBCore2([]Report{report})
+	for _, want := range []string{
+		"<pbcoreInstantiationDocument ",
+		"<instantiationDigital>video/x-matroska</instantiationDigital>",
+		"<instantiationDuration>00:00:04.021</instantiationDuration>",
+		"<essenceTrackFrameSize unitsOfMeasure=\"pixel\">640x360</essenceTrackFrameSize>",
+		"<essenceTrackAspectRatio>16:9</essenceTrackAspectRatio>",
+		"<essenceTrackSamplingRate unitsOfMeasure=\"Hz\">48000</essenceTrackSamplingRate>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
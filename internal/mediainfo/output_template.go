@@ -0,0 +1,301 @@
+package mediainfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Template is a parsed --Inform/--Output template. Each line has the form "Section;text",
+// where Section is a stream kind (General, Video, Audio, Text, Image, Menu), a kind with a
+// _Begin/_Middle/_End suffix, Page_Begin/Page_Middle/Page_End (once per output, Middle
+// between files) or File_Begin/File_End (around each file). Lines that do not start with a
+// section continue the text of the previous one.
+//
+// In the text, %Name% is replaced by the field of the same name in JSON output (extra fields
+// included), $if(cond,then,else) emits then when cond expands to a non-empty string, and
+// \n, \r, \t, \\, \, \( and \) are escapes. A lone % is copied as-is.
+type Template struct {
+	sections map[string][]templateNode
+}
+
+type templateNode struct {
+	text  string
+	field string
+	cond  *templateIf
+}
+
+type templateIf struct {
+	cond     []templateNode
+	then     []templateNode
+	elseNode []templateNode
+}
+
+// TemplateError reports a malformed template.
+type TemplateError struct {
+	Section string
+	Msg     string
+}
+
+func (e *TemplateError) Error() string {
+	if e.Section == "" {
+		return "invalid template: " + e.Msg
+	}
+	return "invalid template: " + e.Section + ": " + e.Msg
+}
+
+var templateKinds = []StreamKind{StreamGeneral, StreamVideo, StreamAudio, StreamText, StreamImage, StreamMenu}
+
+// templateSectionNames maps lower-cased section names to their canonical spelling.
+var templateSectionNames = func() map[string]string {
+	names := map[string]string{}
+	add := func(name string) { names[strings.ToLower(name)] = name }
+	for _, name := range []string{"Page_Begin", "Page_Middle", "Page_End", "File_Begin", "File_End"} {
+		add(name)
+	}
+	for _, kind := range templateKinds {
+		add(string(kind))
+		add(string(kind) + "_Begin")
+		add(string(kind) + "_Middle")
+		add(string(kind) + "_End")
+	}
+	return names
+}()
+
+// ParseTemplate parses a template. A leading UTF-8 BOM and CRLF line endings are accepted so
+// templates saved by Windows editors load as-is.
+func ParseTemplate(text string) (*Template, error) {
+	text = strings.TrimPrefix(text, "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	raw := map[string]string{}
+	current := ""
+	for _, line := range strings.Split(text, "\n") {
+		if name, body, ok := strings.Cut(line, ";"); ok {
+			if canonical, known := templateSectionNames[strings.ToLower(strings.TrimSpace(name))]; known {
+				current = canonical
+				raw[current] = body
+				continue
+			}
+		}
+		if current == "" {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			name, _, _ := strings.Cut(line, ";")
+			return nil, &TemplateError{Msg: fmt.Sprintf("unknown section %q", name)}
+		}
+		raw[current] += "\n" + line
+	}
+	if len(raw) == 0 {
+		return nil, &TemplateError{Msg: "no sections"}
+	}
+	tmpl := &Template{sections: map[string][]templateNode{}}
+	for name, body := range raw {
+		nodes, _, err := parseTemplateNodes(body, false)
+		if err != nil {
+			return nil, &TemplateError{Section: name, Msg: err.Error()}
+		}
+		tmpl.sections[name] = nodes
+	}
+	return tmpl, nil
+}
+
+// parseTemplateNodes parses text until its end or, inside $if(), until an unescaped ',' or
+// ')' at the current nesting level, which is left at the start of the returned rest.
+func parseTemplateNodes(text string, inIf bool) ([]templateNode, string, error) {
+	var nodes []templateNode
+	var buf strings.Builder
+	flush := func() {
+		if buf.Len() > 0 {
+			nodes = append(nodes, templateNode{text: buf.String()})
+			buf.Reset()
+		}
+	}
+	for len(text) > 0 {
+		switch {
+		case text[0] == '\\' && len(text) > 1:
+			switch text[1] {
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r':
+				buf.WriteByte('\r')
+			case 't':
+				buf.WriteByte('\t')
+			default:
+				buf.WriteByte(text[1])
+			}
+			text = text[2:]
+		case text[0] == '%':
+			end := strings.IndexByte(text[1:], '%')
+			if end < 0 {
+				buf.WriteByte('%')
+				text = text[1:]
+				continue
+			}
+			name := text[1 : end+1]
+			if name == "" {
+				buf.WriteByte('%')
+			} else {
+				flush()
+				nodes = append(nodes, templateNode{field: name})
+			}
+			text = text[end+2:]
+		case strings.HasPrefix(text, "$if("):
+			flush()
+			cond, rest, err := parseTemplateIf(text[len("$if("):])
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, templateNode{cond: cond})
+			text = rest
+		case inIf && (text[0] == ',' || text[0] == ')'):
+			flush()
+			return nodes, text, nil
+		default:
+			buf.WriteByte(text[0])
+			text = text[1:]
+		}
+	}
+	flush()
+	if inIf {
+		return nil, "", fmt.Errorf("unterminated $if")
+	}
+	return nodes, "", nil
+}
+
+func parseTemplateIf(text string) (*templateIf, string, error) {
+	var parts [][]templateNode
+	for {
+		nodes, rest, err := parseTemplateNodes(text, true)
+		if err != nil {
+			return nil, "", err
+		}
+		parts = append(parts, nodes)
+		if rest[0] == ')' {
+			text = rest[1:]
+			break
+		}
+		text = rest[1:]
+	}
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, "", fmt.Errorf("$if takes 2 or 3 arguments, got %d", len(parts))
+	}
+	cond := &templateIf{cond: parts[0], then: parts[1]}
+	if len(parts) == 3 {
+		cond.elseNode = parts[2]
+	}
+	return cond, text, nil
+}
+
+// RenderTemplate renders reports with tmpl.
+func RenderTemplate(reports []Report, tmpl *Template) string {
+	var buf strings.Builder
+	tmpl.write(&buf, "Page_Begin", nil)
+	for i, report := range reports {
+		if i > 0 {
+			tmpl.write(&buf, "Page_Middle", nil)
+		}
+		tracks := templateTracks(report)
+		tmpl.write(&buf, "File_Begin", tracks[StreamGeneral][0])
+		for _, kind := range templateKinds {
+			values := tracks[kind]
+			if len(values) == 0 || !tmpl.has(string(kind)) {
+				continue
+			}
+			tmpl.write(&buf, string(kind)+"_Begin", values[0])
+			for j, fields := range values {
+				if j > 0 {
+					tmpl.write(&buf, string(kind)+"_Middle", fields)
+				}
+				tmpl.write(&buf, string(kind), fields)
+			}
+			tmpl.write(&buf, string(kind)+"_End", values[len(values)-1])
+		}
+		tmpl.write(&buf, "File_End", tracks[StreamGeneral][0])
+	}
+	tmpl.write(&buf, "Page_End", nil)
+	return buf.String()
+}
+
+func (t *Template) has(section string) bool {
+	_, ok := t.sections[section]
+	return ok
+}
+
+func (t *Template) write(buf *strings.Builder, section string, fields map[string]string) {
+	writeTemplateNodes(buf, t.sections[section], fields)
+}
+
+func writeTemplateNodes(buf *strings.Builder, nodes []templateNode, fields map[string]string) {
+	for _, node := range nodes {
+		switch {
+		case node.cond != nil:
+			var cond strings.Builder
+			writeTemplateNodes(&cond, node.cond.cond, fields)
+			if strings.TrimSpace(cond.String()) != "" {
+				writeTemplateNodes(buf, node.cond.then, fields)
+			} else {
+				writeTemplateNodes(buf, node.cond.elseNode, fields)
+			}
+		case node.field != "":
+			buf.WriteString(fields[node.field])
+		default:
+			buf.WriteString(node.text)
+		}
+	}
+}
+
+// templateTracks returns the JSON fields of every track of report by kind, plus the file
+// name fields and stream position fields MediaInfo templates commonly use.
+func templateTracks(report Report) map[StreamKind][]map[string]string {
+	tracks := map[StreamKind][]map[string]string{}
+	media := buildJSONMedia(report)
+	if report.Err != nil {
+		media.Tracks = []jsonTrackOut{{Fields: []jsonKV{{Key: "@type", Val: string(StreamGeneral)}}}}
+	}
+	for _, track := range media.Tracks {
+		fields := map[string]string{}
+		for _, field := range track.Fields {
+			if !field.Raw {
+				fields[field.Key] = field.Val
+				continue
+			}
+			var extra map[string]any
+			if json.Unmarshal([]byte(field.Val), &extra) != nil {
+				continue
+			}
+			for key, value := range extra {
+				if text, ok := value.(string); ok {
+					fields[key] = text
+				}
+			}
+		}
+		kind := StreamKind(fields["@type"])
+		fields["StreamKind"] = string(kind)
+		fields["StreamKindID"] = strconv.Itoa(len(tracks[kind]))
+		tracks[kind] = append(tracks[kind], fields)
+	}
+	general := tracks[StreamGeneral][0]
+	if report.Ref != "" {
+		base := filepath.Base(report.Ref)
+		general["CompleteName"] = report.Ref
+		general["FolderName"] = filepath.Dir(report.Ref)
+		general["FileNameExtension"] = base
+		general["FileName"] = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	if report.Err != nil {
+		general["Error"] = report.Err.Error()
+	}
+	for kind, values := range tracks {
+		for _, fields := range values {
+			fields["StreamCount"] = strconv.Itoa(len(values))
+			if kind != StreamGeneral && len(values) > 1 {
+				id, _ := strconv.Atoi(fields["StreamKindID"])
+				fields["StreamKindPos"] = strconv.Itoa(id + 1)
+			}
+		}
+	}
+	return tracks
+}
@@ -0,0 +1,50 @@
+package mediainfo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRenderTemplateSections(t *testing.T) {
+	report, err := AnalyzeFile("samples/sample.mkv")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	tmpl, err := ParseTemplate("Page_Begin;[\nPage_End;]\nFile_Begin;%FileNameExtension%:\nGeneral;%Format%\\n\nVideo;%Width%x%Height% %Format% $if(%HDR_Format%,HDR,SDR)\\n\nAudio_Begin;audio=\nAudio;%SamplingRate%$if(%Language%,\\,%Language%)\nAudio_End;\\n")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	got := RenderTemplate([]Report{report}, tmpl)
+	want := "[sample.mkv:Matroska\n640x360 AVC SDR\naudio=48000\n]"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestRenderTemplateMiddleAndFailedFiles(t *testing.T) {
+	reports := []Report{
+		{Ref: "a.mkv", Err: errors.New("boom")},
+		{Ref: "b.mkv", Err: errors.New("bang")},
+	}
+	tmpl, err := ParseTemplate("General;%FileName% %Error% 100%\nPage_Middle;|")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got, want := RenderTemplate(reports, tmpl), "a boom 100%|b bang 100%"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestParseTemplateErrors(t *testing.T) {
+	for _, text := range []string{
+		"Chapters;%Title%",
+		"Video;$if(%Width%,x",
+		"Video;$if(%Width%)",
+	} {
+		_, err := ParseTemplate(text)
+		var tmplErr *TemplateError
+		if !errors.As(err, &tmplErr) {
+			t.Fatalf("%q: err=%v, want *TemplateError", text, err)
+		}
+	}
+}
@@ -4,19 +4,31 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
 func RenderText(reports []Report) string {
+	return renderText(reports, nil)
+}
+
+func renderText(reports []Report, lang *Language) string {
 	var buf bytes.Buffer
 	for i, report := range reports {
 		if i > 0 {
 			buf.WriteString("\n")
 		}
-		writeStream(&buf, string(report.General.Kind), report.General)
+		if report.Err != nil {
+			writeStream(&buf, lang.title(string(StreamGeneral)), lang.localizeStream(failedStream(report)))
+			buf.WriteString("\n")
+			buf.WriteString(reportByLine())
+			buf.WriteString("\n")
+			continue
+		}
+		writeStream(&buf, lang.title(string(report.General.Kind)), lang.localizeStream(report.General))
 		forEachStreamWithKindIndex(report.Streams, func(stream Stream, index, total, _ int) {
 			buf.WriteString("\n")
 			title := streamTitle(stream.Kind, index, total)
-			writeStream(&buf, title, stream)
+			writeStream(&buf, lang.title(title), lang.localizeStream(stream))
 		})
 		buf.WriteString("\n")
 		buf.WriteString(reportByLine())
@@ -42,10 +54,11 @@ func writeStream(buf *bytes.Buffer, title string, stream Stream) {
 }
 
 func padRight(value string, width int) string {
-	if len(value) >= width {
+	length := utf8.RuneCountInString(value)
+	if length >= width {
 		return value
 	}
-	return value + strings.Repeat(" ", width-len(value))
+	return value + strings.Repeat(" ", width-length)
 }
 
 func streamTitle(kind StreamKind, index, total int) string {
@@ -0,0 +1,242 @@
+package mediainfo
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RenderOptions tunes the text and HTML renderers.
+type RenderOptions struct {
+	// Full prints every field, as mediainfo --Full does: the raw value of each JSON field
+	// next to its display value, alternative duration representations, stream counts and
+	// the share of the file each stream takes.
+	Full bool
+	// Language translates field names and values; nil is English.
+	Language *Language
+}
+
+// RenderTextWithOptions renders reports as the MediaInfo text report, honoring opts.
+func RenderTextWithOptions(reports []Report, opts RenderOptions) string {
+	if opts.Full {
+		reports = fullReports(reports)
+	}
+	return renderText(reports, opts.Language)
+}
+
+// fullReports returns reports with the --Full field list in every stream.
+func fullReports(reports []Report) []Report {
+	full := make([]Report, len(reports))
+	for i, report := range reports {
+		full[i] = report
+		if report.Err != nil {
+			continue
+		}
+		containerFormat := findField(report.General.Fields, "Format")
+		// The general fields stat the file; build them once for all streams.
+		general := buildJSONGeneralFields(report)
+		full[i].General = fullTextStream(report, general, report.General, 1, 1, 0, containerFormat)
+		full[i].Streams = make([]Stream, 0, len(report.Streams))
+		forEachStreamWithKindIndex(report.Streams, func(stream Stream, index, total, order int) {
+			full[i].Streams = append(full[i].Streams, fullTextStream(report, general, stream, index, total, order, containerFormat))
+		})
+	}
+	return full
+}
+
+// fullTextStream returns stream with the --Full field list: a header describing the stream,
+// then every JSON field as a raw line followed, for fields that have one, by the display line
+// and its alternative representations. general holds the JSON fields of the general
+// stream.
+func fullTextStream(report Report, general []jsonKV, stream Stream, index, total, order int, containerFormat string) Stream {
+	var jsonFields []jsonKV
+	if stream.Kind == StreamGeneral {
+		jsonFields = general
+	} else {
+		jsonFields = buildJSONStreamFields(stream, order, 0, containerFormat)
+	}
+	jsonFields = expandJSONExtra(jsonFields)
+	values := typedFieldsFromJSON(jsonFields)
+
+	// owner maps a JSON key to the display field it comes from; keys produced together from
+	// one display field only take its name for the first of them.
+	owner := map[string]int{}
+	labelled := map[string]bool{}
+	for i, field := range stream.Fields {
+		// General fields are built apart from the display mapping; match them by name.
+		if stream.Kind == StreamGeneral {
+			name := strings.ReplaceAll(field.Name, " ", "")
+			for _, kv := range jsonFields {
+				if _, ok := owner[kv.Key]; !ok && strings.EqualFold(kv.Key, name) {
+					owner[kv.Key] = i
+					labelled[kv.Key] = true
+				}
+			}
+		}
+		for j, kv := range mapStreamFieldsToJSON(stream.Kind, []Field{field}) {
+			if _, ok := owner[kv.Key]; !ok {
+				owner[kv.Key] = i
+				labelled[kv.Key] = j == 0
+			}
+		}
+	}
+
+	var body []Field
+	emitted := make([]bool, len(stream.Fields))
+	next := 0
+	// flushDisplay emits the display fields before limit that have no JSON counterpart, so
+	// they keep their place relative to the others.
+	flushDisplay := func(limit int) {
+		for ; next < limit; next++ {
+			if !emitted[next] {
+				emitted[next] = true
+				if stream.Kind == StreamGeneral && stream.Fields[next].Name == "Complete name" {
+					continue
+				}
+				body = append(body, stream.Fields[next])
+			}
+		}
+	}
+
+	if stream.Kind == StreamGeneral && report.Ref != "" {
+		base := filepath.Base(report.Ref)
+		body = append(body,
+			Field{Name: "Complete name", Value: report.Ref},
+			Field{Name: "Folder name", Value: filepath.Dir(report.Ref)},
+			Field{Name: "File name extension", Value: base},
+			Field{Name: "File name", Value: strings.TrimSuffix(base, filepath.Ext(base))},
+		)
+	}
+	for _, kv := range jsonFields {
+		if kv.Raw || kv.Key == "@type" || kv.Key == "@typeorder" {
+			continue
+		}
+		i, owned := owner[kv.Key]
+		label := kv.Key
+		switch {
+		case owned && labelled[kv.Key]:
+			label = stream.Fields[i].Name
+		case fullTextLabels[kv.Key] != "":
+			label = fullTextLabels[kv.Key]
+		}
+		body = append(body, Field{Name: label, Value: fullTextRawValue(kv.Key, kv.Val)})
+		if kind, ok := strings.CutSuffix(kv.Key, "Count"); ok && stream.Kind == StreamGeneral {
+			if formats := streamFormatList(report.Streams, StreamKind(kind)); formats != "" {
+				body = append(body, Field{Name: kind + "_Format_List", Value: formats})
+			}
+		}
+		if !owned || emitted[i] {
+			continue
+		}
+		flushDisplay(i)
+		emitted[i] = true
+		field := stream.Fields[i]
+		body = append(body, field)
+		switch kv.Key {
+		case "Duration":
+			body = append(body, fullDurationFields(field.Name, values)...)
+		case "StreamSize":
+			if stream.Kind == StreamGeneral {
+				break
+			}
+			fileSize := typedFieldsFromJSON(general).int64("FileSize")
+			if size := values.int64("StreamSize"); size > 0 && fileSize > 0 {
+				body = append(body, Field{Name: "Proportion of this stream", Value: strconv.FormatFloat(float64(size)/float64(fileSize), 'f', 5, 64)})
+			}
+		}
+	}
+	flushDisplay(len(stream.Fields))
+
+	header := []Field{
+		{Name: "Count of stream of this kind", Value: strconv.Itoa(total)},
+		{Name: "Kind of stream", Value: string(stream.Kind)},
+		{Name: "Kind of stream", Value: string(stream.Kind)},
+		{Name: "Stream identifier", Value: strconv.Itoa(index - 1)},
+	}
+	if stream.Kind != StreamGeneral && total > 1 {
+		header = append(header, Field{Name: "Stream identifier", Value: strconv.Itoa(index)})
+	}
+	fields := make([]Field, 0, len(header)+len(body)+1)
+	fields = append(fields, Field{Name: "Count", Value: strconv.Itoa(len(header) + len(body) + 1)})
+	fields = append(fields, header...)
+	fields = append(fields, body...)
+	stream.Fields = fields
+	return stream
+}
+
+var fullTextLabels = map[string]string{
+	"VideoCount":    "Count of video streams",
+	"AudioCount":    "Count of audio streams",
+	"TextCount":     "Count of text streams",
+	"ImageCount":    "Count of image streams",
+	"MenuCount":     "Count of menu streams",
+	"FileExtension": "File extension",
+}
+
+// fullTextRawValue converts the JSON value of key to its raw text form, which keeps
+// durations and delays in milliseconds.
+func fullTextRawValue(key, value string) string {
+	if strings.HasSuffix(key, "Duration") || strings.HasSuffix(key, "Frame") && strings.Contains(key, "Duration_") || key == "Delay" || key == "Video_Delay" {
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+			return strconv.FormatFloat(math.Round(seconds*1e6)/1e3, 'f', -1, 64)
+		}
+	}
+	return value
+}
+
+// fullDurationFields returns the HH:MM:SS.mmm duration and, when the stream has a frame
+// rate, the HH:MM:SS:FF timecode and both combined.
+func fullDurationFields(name string, values typedFields) []Field {
+	seconds := values.float("Duration")
+	if seconds <= 0 {
+		return nil
+	}
+	clock := formatClockDuration(seconds)
+	fields := []Field{{Name: name, Value: clock}}
+	if rate := values.float("FrameRate"); rate > 0 {
+		// Every part comes from one frame count, counted in whole frames per second
+		// (30 for 29.970), so the frame number stays below the rate.
+		perSecond := max(int64(math.Round(rate)), 1)
+		frames := int64(math.Floor(seconds*rate + 1e-6))
+		secs := frames / perSecond
+		timecode := fmt.Sprintf("%02d:%02d:%02d:%02d", secs/3600, secs/60%60, secs%60, frames%perSecond)
+		fields = append(fields,
+			Field{Name: name, Value: timecode},
+			Field{Name: name, Value: clock + " (" + timecode + ")"},
+		)
+	}
+	return fields
+}
+
+func streamFormatList(streams []Stream, kind StreamKind) string {
+	var formats []string
+	for _, stream := range streams {
+		if stream.Kind == kind {
+			formats = append(formats, findField(stream.Fields, "Format"))
+		}
+	}
+	return strings.Join(formats, " / ")
+}
+
+// expandJSONExtra replaces the raw "extra" object of fields by its string members, in order.
+func expandJSONExtra(fields []jsonKV) []jsonKV {
+	out := make([]jsonKV, 0, len(fields))
+	for _, field := range fields {
+		if !field.Raw || field.Key != "extra" {
+			out = append(out, field)
+			continue
+		}
+		extra, err := parseOrderedJSON(field.Val)
+		if err != nil || extra.kind != orderedObject {
+			continue
+		}
+		for _, kv := range extra.obj {
+			if kv.val.kind == orderedString {
+				out = append(out, jsonKV{Key: kv.key, Val: kv.val.str})
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,73 @@
+package mediainfo
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRenderTextFull(t *testing.T) {
+	report, err := AnalyzeFile("samples/sample.mp4")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	reports := []Report{report}
+	if got := RenderTextWithOptions(reports, RenderOptions{}); got != RenderText(reports) {
+		t.Fatalf("default options differ from RenderText")
+	}
+	out := RenderTextWithOptions(reports, RenderOptions{Full: true})
+	sections := strings.Split(out, "\n\n")
+	if len(sections) < 3 || !strings.HasPrefix(sections[1], "Video\n") {
+		t.Fatalf("unexpected sections:\n%s", out)
+	}
+	video := sections[1]
+	for _, want := range []string{
+		"Count of stream of this kind             : 1",
+		"Kind of stream                           : Video",
+		"Stream identifier                        : 0",
+		"StreamOrder                              : 0",
+		"Duration                                 : 3971",
+		"Duration                                 : 3 s 971 ms",
+		"Duration                                 : 00:00:03.971",
+		"Duration                                 : 00:00:03:29",
+		"Bit rate                                 : 450257",
+		"Bit rate                                 : 450 kb/s",
+		"Proportion of this stream                : 0.80462",
+		"Format_Level                             : 3",
+	} {
+		if !strings.Contains(video, want+"\n") {
+			t.Fatalf("missing %q in:\n%s", want, video)
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(video), "\n")
+	if count := "Count                                    : " + strconv.Itoa(len(lines)-1); lines[1] != count {
+		t.Fatalf("count line %q, want %q", lines[1], count)
+	}
+	general := sections[0]
+	for _, want := range []string{
+		"Count of video streams                   : 1",
+		"Video_Format_List                        : AVC",
+		"File name extension                      : sample.mp4",
+		"File size                                : 277741",
+	} {
+		if !strings.Contains(general, want+"\n") {
+			t.Fatalf("missing %q in:\n%s", want, general)
+		}
+	}
+}
+
+func TestFullDurationTimecode(t *testing.T) {
+	for _, tc := range []struct {
+		duration, rate, want string
+	}{
+		{"10.0", "29.970", "00:00:09:29"},
+		{"59.999", "25.000", "00:00:59:24"},
+		{"3600.0", "25.000", "01:00:00:00"},
+	} {
+		values := typedFieldsFromJSON([]jsonKV{{Key: "Duration", Val: tc.duration}, {Key: "FrameRate", Val: tc.rate}})
+		fields := fullDurationFields("Duration", values)
+		if len(fields) != 3 || fields[1].Value != tc.want {
+			t.Errorf("%s s at %s fps: got %+v, want %s", tc.duration, tc.rate, fields, tc.want)
+		}
+	}
+}
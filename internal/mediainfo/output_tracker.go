@@ -0,0 +1,270 @@
+package mediainfo
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// TrackerMarkup selects how RenderTracker wraps the text report.
+type TrackerMarkup int
+
+const (
+	// TrackerBBCode wraps the report in [mediainfo]...[/mediainfo].
+	TrackerBBCode TrackerMarkup = iota
+	// TrackerBBCodeCode wraps the report in [code]...[/code], for forums without a
+	// [mediainfo] tag.
+	TrackerBBCodeCode
+	// TrackerMarkdown wraps the report in a fenced code block.
+	TrackerMarkdown
+)
+
+// TrackerOptions configures RenderTracker.
+type TrackerOptions struct {
+	RenderOptions
+	Markup TrackerMarkup
+	// Redact drops fields that identify the machine or the file rather than the media:
+	// unique IDs, file times and values holding absolute paths. Absolute paths inside
+	// other values, such as encoder settings or error messages, are reduced to their
+	// base names.
+	Redact bool
+}
+
+// redactedFields are dropped by TrackerOptions.Redact, by internal name.
+var redactedFields = map[string]bool{
+	"UniqueID":                 true,
+	"File_Created_Date":        true,
+	"File_Created_Date_Local":  true,
+	"File_Modified_Date":       true,
+	"File_Modified_Date_Local": true,
+}
+
+// RenderTracker renders each report as a paste for tracker upload forms: a one-paragraph
+// summary (see ReportSummary) followed by the text report in BBCode or Markdown. Complete
+// name is always reduced to the bare file name.
+func RenderTracker(reports []Report, opts TrackerOptions) string {
+	if opts.Full {
+		reports = fullReports(reports)
+	}
+	var b strings.Builder
+	for i, report := range reports {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		report = trackerReport(report, opts.Redact)
+		text := strings.TrimRight(renderText([]Report{report}, opts.Language), "\n")
+		summary := ReportSummary(report)
+		switch opts.Markup {
+		case TrackerMarkdown:
+			fence := "```"
+			for strings.Contains(text, fence) {
+				fence += "`"
+			}
+			b.WriteString(summary + "\n\n" + fence + "text\n" + text + "\n" + fence + "\n")
+		case TrackerBBCodeCode:
+			b.WriteString(summary + "\n\n[code]\n" + text + "\n[/code]\n")
+		default:
+			b.WriteString(summary + "\n\n[mediainfo]\n" + text + "\n[/mediainfo]\n")
+		}
+	}
+	return b.String()
+}
+
+// trackerReport returns report with Complete name reduced to the file name and, when
+// redact is set, the identifying fields removed. The streams are copied, not modified.
+func trackerReport(report Report, redact bool) Report {
+	if report.Err != nil {
+		// Failed reports render Ref and the error text directly.
+		base := filepath.Base(strings.ReplaceAll(report.Ref, `\`, "/"))
+		message := report.Err.Error()
+		if report.Ref != "" {
+			message = strings.ReplaceAll(message, report.Ref, base)
+			report.Ref = base
+		}
+		if redact {
+			message = redactPaths(message)
+		}
+		report.Err = errors.New(message)
+		return report
+	}
+	clean := func(stream Stream) Stream {
+		fields := make([]Field, 0, len(stream.Fields))
+		for _, field := range stream.Fields {
+			info, _ := lookupField(stream.Kind, field.Name)
+			switch {
+			case info.Name == "CompleteName" || info.Name == "CompleteName_Last":
+				field.Value = filepath.Base(strings.ReplaceAll(field.Value, `\`, "/"))
+			case info.Name == "FolderName":
+				continue
+			case redact && (redactedFields[info.Name] || isAbsolutePathValue(field.Value)):
+				continue
+			case redact:
+				field.Value = redactPaths(field.Value)
+			}
+			fields = append(fields, field)
+		}
+		stream.Fields = fields
+		return stream
+	}
+	report.General = clean(report.General)
+	streams := make([]Stream, len(report.Streams))
+	for i, stream := range report.Streams {
+		streams[i] = clean(stream)
+	}
+	report.Streams = streams
+	return report
+}
+
+// isAbsolutePathValue reports whether value is a POSIX, Windows drive or UNC path.
+func isAbsolutePathValue(value string) bool {
+	switch {
+	case strings.HasPrefix(value, "/") && strings.Count(value, "/") > 1:
+		return true
+	case strings.HasPrefix(value, `\\`):
+		return true
+	case len(value) > 2 && value[1] == ':' && (value[2] == '\\' || value[2] == '/'):
+		return true
+	}
+	return false
+}
+
+// embeddedPathPattern matches the absolute paths inside a value: POSIX paths of two or
+// more elements, Windows drive and UNC paths, starting the value or following a space,
+// quote or settings delimiter and running up to the next one.
+var embeddedPathPattern = regexp.MustCompile(`(?:^|[\s=:"'(,;])((?:/[^\s/"'(),;]+){2,}|[A-Za-z]:[\\/][^\s"'(),;]*|\\\\[^\s"'(),;]+)`)
+
+// redactPaths reduces every absolute path inside value to its base name.
+func redactPaths(value string) string {
+	matches := embeddedPathPattern.FindAllStringSubmatchIndex(value, -1)
+	if matches == nil {
+		return value
+	}
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		start, end := match[2], match[3]
+		b.WriteString(value[last:start])
+		b.WriteString(filepath.Base(strings.ReplaceAll(value[start:end], `\`, "/")))
+		last = end
+	}
+	b.WriteString(value[last:])
+	return b.String()
+}
+
+// ReportSummary describes report in one paragraph: container, duration and size, then the
+// video (resolution, codec, bit depth, frame rate, HDR), audio (language, codec, channels,
+// bit rate) and subtitle tracks.
+func ReportSummary(report Report) string {
+	name := filepath.Base(strings.ReplaceAll(report.Ref, `\`, "/"))
+	if report.Err != nil {
+		return name + ": " + report.Err.Error()
+	}
+	var sentences []string
+	head := []string{}
+	for _, value := range []string{
+		findField(report.General.Fields, "Format"),
+		findField(report.General.Fields, "Duration"),
+		findField(report.General.Fields, "File size"),
+	} {
+		if value != "" {
+			head = append(head, value)
+		}
+	}
+	sentences = append(sentences, name+": "+strings.Join(head, ", "))
+
+	var videos, audios, texts []string
+	for _, stream := range report.Streams {
+		switch {
+		case stream.Kind == StreamVideo && stream.Video != nil:
+			videos = append(videos, summaryVideo(stream.Video))
+		case stream.Kind == StreamAudio && stream.Audio != nil:
+			audios = append(audios, summaryAudio(stream.Audio))
+		case stream.Kind == StreamText && stream.Text != nil:
+			texts = append(texts, summaryText(stream.Text))
+		}
+	}
+	for _, group := range []struct {
+		label string
+		items []string
+	}{{"Video", videos}, {"Audio", audios}, {"Subtitles", texts}} {
+		if len(group.items) > 0 {
+			sentences = append(sentences, group.label+": "+strings.Join(group.items, "; "))
+		}
+	}
+	return strings.Join(sentences, ". ") + "."
+}
+
+func summaryVideo(video *VideoInfo) string {
+	parts := []string{}
+	codec := strings.TrimSpace(video.Format + " " + video.FormatProfile)
+	if video.Width > 0 && video.Height > 0 {
+		codec = fmt.Sprintf("%dx%d %s", video.Width, video.Height, codec)
+	}
+	parts = append(parts, codec)
+	if video.BitDepth > 0 {
+		parts = append(parts, fmt.Sprintf("%d-bit", video.BitDepth))
+	}
+	if video.FrameRate.Sign() > 0 {
+		parts = append(parts, video.FrameRate.FloatString(3)+" FPS")
+	}
+	if hdr := summaryHDR(video); hdr != "" {
+		parts = append(parts, hdr)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// summaryHDR names the HDR formats the way release notes do: Dolby Vision, HDR10+, HDR10
+// and HLG.
+func summaryHDR(video *VideoInfo) string {
+	return strings.Join(releaseHDR(video.HDRFormat+" / "+video.HDRFormatCompatibility, video.TransferCharacteristics, summaryNames), " / ")
+}
+
+func summaryAudio(audio *AudioInfo) string {
+	codec, atmos, dtsx := releaseAudioCodec(audio.Format, audio.FormatProfile, audio.FormatCommercial, audio.FormatAdditionalFeatures, summaryNames)
+	parts := []string{summaryLanguage(audio.Language), ReleaseAudio{Codec: codec, Atmos: atmos, DTSX: dtsx}.String()}
+	if audio.Channels > 0 {
+		parts = append(parts, summaryChannels(audio.Channels, audio.ChannelLayout))
+	}
+	out := strings.Join(parts, " ")
+	if audio.BitRate > 0 {
+		out += " @ " + formatBitrate(float64(audio.BitRate))
+	}
+	if audio.Title != "" {
+		out += fmt.Sprintf(" (%q)", audio.Title)
+	}
+	return out
+}
+
+// summaryChannels writes the channel count as "5.1" or "2.0".
+func summaryChannels(channels int, layout string) string {
+	for label := range strings.FieldsSeq(layout) {
+		if label == "LFE" && channels > 1 {
+			return fmt.Sprintf("%d.1", channels-1)
+		}
+	}
+	return fmt.Sprintf("%d.0", channels)
+}
+
+func summaryText(text *TextInfo) string {
+	details := []string{}
+	if text.Format != "" {
+		details = append(details, text.Format)
+	}
+	if text.Forced {
+		details = append(details, "forced")
+	}
+	out := summaryLanguage(text.Language)
+	if len(details) > 0 {
+		out += " (" + strings.Join(details, ", ") + ")"
+	}
+	return out
+}
+
+func summaryLanguage(code string) string {
+	if code == "" {
+		return "Unknown"
+	}
+	return formatLanguage(code)
+}
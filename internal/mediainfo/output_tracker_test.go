@@ -0,0 +1,107 @@
+package mediainfo
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderTracker(t *testing.T) {
+	path, err := filepath.Abs("samples/sample.mkv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := AnalyzeFile(path)
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+
+	out := RenderTracker([]Report{report}, TrackerOptions{})
+	if !strings.HasPrefix(out, "sample.mkv: Matroska, 4 s 21 ms, 269 KiB. Video: 640x360 AVC High, 8-bit, 29.970 FPS. Audio: Unknown AAC 1.0.\n\n[mediainfo]\nGeneral\n") {
+		t.Fatalf("unexpected start:\n%s", out)
+	}
+	if !strings.HasSuffix(out, "ReportBy : go-mediainfo - "+FormatVersion(AppVersion)+"\n[/mediainfo]\n") {
+		t.Fatalf("unexpected end:\n%s", out)
+	}
+	if !strings.Contains(out, "Complete name                            : sample.mkv\n") || strings.Contains(out, path) {
+		t.Fatalf("complete name not reduced to the file name:\n%s", out)
+	}
+	if !strings.Contains(out, "Unique ID") {
+		t.Fatalf("unique ID dropped without Redact")
+	}
+
+	for _, full := range []bool{false, true} {
+		out = RenderTracker([]Report{report}, TrackerOptions{RenderOptions: RenderOptions{Full: full}, Markup: TrackerMarkdown, Redact: true})
+		if !strings.Contains(out, "\n\n```text\nGeneral\n") || !strings.HasSuffix(out, "\n```\n") {
+			t.Fatalf("full=%v: not a Markdown fence:\n%s", full, out)
+		}
+		for _, dropped := range []string{"Unique ID", "UniqueID", "Folder name", "File_Modified_Date", filepath.Dir(path)} {
+			if strings.Contains(out, dropped) {
+				t.Fatalf("full=%v: %q not redacted:\n%s", full, dropped, out)
+			}
+		}
+	}
+}
+
+func TestRenderTrackerRedactsPaths(t *testing.T) {
+	failed := Report{Ref: "/home/secret/user/missing.mkv", Err: &FileError{Path: "/home/secret/user/missing.mkv", Err: errors.New("open /home/secret/user/missing.mkv: no such file")}}
+	settings := Report{
+		Ref: "/home/secret/user/movie.mkv",
+		General: Stream{Kind: StreamGeneral, Fields: []Field{
+			{Name: "Complete name", Value: "/home/secret/user/movie.mkv"},
+			{Name: "Comment", Value: `ripped from "D:\\rips\\disc.iso" (see \\\\nas\\share\\notes.txt)`},
+		}},
+		Streams: []Stream{{Kind: StreamVideo, Fields: []Field{
+			{Name: "Encoding settings", Value: "crf=18.0 / stats=/home/secret/user/x265.log / ref=4"},
+			{Name: "Frame rate", Value: "24000/1001"},
+		}}},
+	}
+	out := RenderTracker([]Report{failed, settings}, TrackerOptions{Redact: true})
+	if strings.Contains(out, "secret") || strings.Contains(out, "rips") || strings.Contains(out, "nas") {
+		t.Fatalf("path not redacted:\n%s", out)
+	}
+	for _, want := range []string{
+		"Complete name                            : missing.mkv\n",
+		"stats=x265.log / ref=4\n",
+		`ripped from "disc.iso" (see notes.txt)`,
+		"24000/1001\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestReportSummaryTracks(t *testing.T) {
+	report := Report{
+		Ref:     "/media/Movie.2024.mkv",
+		General: Stream{Kind: StreamGeneral, Fields: []Field{{Name: "Format", Value: "Matroska"}}},
+		Streams: []Stream{
+			{Kind: StreamVideo, Video: &VideoInfo{Format: "HEVC", FormatProfile: "Main 10", Width: 3840, Height: 2160, BitDepth: 10, HDRFormat: "Dolby Vision / SMPTE ST 2086", HDRFormatCompatibility: "HDR10 / HDR10"}},
+			{Kind: StreamAudio, Audio: &AudioInfo{Format: "MLP FBA", FormatCommercial: "Dolby TrueHD with Dolby Atmos", Channels: 8, ChannelLayout: "L R C LFE Ls Rs Lb Rb", Language: "en"}},
+			{Kind: StreamAudio, Audio: &AudioInfo{Format: "AC-3", Channels: 2, ChannelLayout: "L R", BitRate: 192000, Language: "fr", Title: "Commentary"}},
+			{Kind: StreamText, Text: &TextInfo{Format: "PGS", Language: "de", Forced: true}},
+		},
+	}
+	want := `Movie.2024.mkv: Matroska. Video: 3840x2160 HEVC Main 10, 10-bit, Dolby Vision / HDR10. ` +
+		`Audio: English TrueHD Atmos 7.1; French AC-3 2.0 @ 192 kb/s ("Commentary"). Subtitles: German (PGS, forced).`
+	if got := ReportSummary(report); got != want {
+		t.Fatalf("summary:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestSummaryAudioMatchesReleaseAttributes(t *testing.T) {
+	for _, tc := range []struct {
+		audio AudioInfo
+		want  string
+	}{
+		{AudioInfo{Format: "DTS", FormatAdditionalFeatures: "XLL X", Channels: 8, ChannelLayout: "C L R LFE Ls Rs Lb Rb"}, "Unknown DTS-HD MA DTS:X 7.1"},
+		{AudioInfo{Format: "MLP FBA", FormatAdditionalFeatures: "16-ch", Channels: 8, ChannelLayout: "L R C LFE Ls Rs Lb Rb"}, "Unknown TrueHD Atmos 7.1"},
+		{AudioInfo{Format: "E-AC-3", FormatAdditionalFeatures: "JOC", Channels: 6, ChannelLayout: "L R C LFE Ls Rs"}, "Unknown E-AC-3 Atmos 5.1"},
+	} {
+		if got := summaryAudio(&tc.audio); got != tc.want {
+			t.Errorf("%s %q: got %q, want %q", tc.audio.Format, tc.audio.FormatAdditionalFeatures, got, tc.want)
+		}
+	}
+}
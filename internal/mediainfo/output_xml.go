@@ -57,6 +57,11 @@ func renderXMLMedia(report Report) string {
 		buf.WriteString(fmt.Sprintf(" ref=\"%s\"", xmlEscapeAttr(report.Ref)))
 	}
 	buf.WriteString(">\n")
+	if report.Err != nil {
+		buf.WriteString(renderXMLField("error", report.Err.Error()))
+		buf.WriteString("</media>\n")
+		return buf.String()
+	}
 
 	buf.WriteString(renderXMLTrack("General", 0, buildJSONGeneralFields(report)))
 
@@ -0,0 +1,506 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// rawAudioHeaderBytes is the longest frame header read by the rawAudioSpan functions.
+const rawAudioHeaderBytes = 16
+
+// rawAudioSpan returns the size of the frame at the start of buf and the number of
+// samples it adds to the stream; frames of dependent substreams and extensions add none.
+type rawAudioSpan func(buf []byte) (size, samples int, ok bool)
+
+// walkRawAudioFrames walks the complete frames between start and end, resyncing one byte
+// at a time past data span rejects, and returns the frames that carry samples and the
+// sample total.
+func walkRawAudioFrames(r io.ReaderAt, start, end int64, span rawAudioSpan) (frames, samples int64) {
+	buf := make([]byte, rawScanChunk)
+	for offset := start; offset < end; {
+		n, _ := r.ReadAt(buf[:min(int64(len(buf)), end-offset)], offset)
+		if n == 0 {
+			break
+		}
+		last := offset+int64(n) == end
+		pos := 0
+		for pos < n && (last || n-pos >= rawAudioHeaderBytes) {
+			size, count, ok := span(buf[pos:n])
+			if !ok {
+				pos++
+				continue
+			}
+			if offset+int64(pos+size) > end {
+				return frames, samples
+			}
+			if count > 0 {
+				frames++
+				samples += int64(count)
+			}
+			pos += size
+		}
+		if pos == 0 {
+			break
+		}
+		offset += int64(pos)
+	}
+	return frames, samples
+}
+
+// scanRawAudio walks the frames of a raw audio stream within the scan budget and
+// extrapolates the counts to the whole stream.
+func scanRawAudio(r io.ReaderAt, size int64, parseSpeed float64, span rawAudioSpan) (frames, samples int64) {
+	scanSize := rawScanSize(size, parseSpeed)
+	frames, samples = walkRawAudioFrames(r, 0, scanSize, span)
+	return extrapolateRawCount(frames, scanSize, size), extrapolateRawCount(samples, scanSize, size)
+}
+
+// ac3FrameSpan spans AC-3 frames and E-AC-3 syncframes; only independent substream 0
+// adds samples.
+func ac3FrameSpan(buf []byte) (int, int, bool) {
+	if len(buf) < 6 || buf[0] != 0x0B || buf[1] != 0x77 {
+		return 0, 0, false
+	}
+	bsid := buf[5] >> 3
+	if bsid <= 10 {
+		size := ac3FrameSizeBytes(int(buf[4]>>6), int(buf[4]&0x3F))
+		return size, 1536, size > 0
+	}
+	if bsid > 16 {
+		return 0, 0, false
+	}
+	size := (int(buf[2]&0x07)<<8 | int(buf[3]) + 1) * 2
+	strmtyp := buf[2] >> 6
+	substreamID := (buf[2] >> 3) & 0x07
+	if strmtyp == 1 || substreamID != 0 {
+		return size, 0, true
+	}
+	numblkscod := int(buf[4]>>4) & 0x03
+	if buf[4]>>6 == 3 {
+		numblkscod = 3
+	}
+	return size, eac3SamplesPerFrame(numblkscod), true
+}
+
+// dtsFrameSpan spans DTS core frames and DTS-HD extension substreams.
+func dtsFrameSpan(buf []byte) (int, int, bool) {
+	if len(buf) < 10 {
+		return 0, 0, false
+	}
+	if dtsCoreSyncAt(buf) {
+		size := (int(buf[5]&0x03)<<12 | int(buf[6])<<4 | int(buf[7]>>4)) + 1
+		blocks := (int(buf[4]&0x01)<<6 | int(buf[5]>>2)) + 1
+		return size, blocks * 32, size >= 96
+	}
+	if binary.BigEndian.Uint32(buf) != 0x64582025 {
+		return 0, 0, false
+	}
+	br := newBitReader(buf[5:])
+	br.readBitsValue(2) // nExtSSIndex
+	wide := uint8(br.readBitsValue(1))
+	br.readBitsValue(8 + 4*wide) // nuExtSSHeaderSize
+	size := int(br.readBitsValue(16+4*wide)) + 1
+	return size, 0, size > 8
+}
+
+// adtsFrameSpan spans ADTS frames.
+func adtsFrameSpan(buf []byte) (int, int, bool) {
+	if len(buf) < 7 || buf[0] != 0xFF || buf[1]&0xF6 != 0xF0 {
+		return 0, 0, false
+	}
+	size := int(buf[3]&0x03)<<11 | int(buf[4])<<3 | int(buf[5]>>5)
+	return size, (int(buf[6]&0x03) + 1) * 1024, size >= 7
+}
+
+// loasFrameSpan spans LOAS AudioSyncStream frames, one AudioMuxElement each.
+func loasFrameSpan(buf []byte) (int, int, bool) {
+	if len(buf) < 3 || buf[0] != 0x56 || buf[1]&0xE0 != 0xE0 {
+		return 0, 0, false
+	}
+	length := int(buf[1]&0x1F)<<8 | int(buf[2])
+	return 3 + length, 1024, length > 0
+}
+
+// trueHDFrameSpan returns a span of TrueHD access units of spf samples each.
+func trueHDFrameSpan(spf int) rawAudioSpan {
+	return func(buf []byte) (int, int, bool) {
+		size, ok := trueHDAccessUnitSpan(buf)
+		return size, spf, ok
+	}
+}
+
+// rawAudioFramesChain reports whether header starts with a frame that span accepts and,
+// when the header holds it, is followed by another one.
+func rawAudioFramesChain(header []byte, span rawAudioSpan) bool {
+	size, _, ok := span(header)
+	if !ok {
+		return false
+	}
+	if size+rawAudioHeaderBytes > len(header) {
+		return true
+	}
+	_, _, ok = span(header[size:])
+	return ok
+}
+
+// rawAudioFormat detects raw AC-3, E-AC-3, DTS, TrueHD, ADTS and LOAS streams from the
+// sync word of the first frame and the one of the frame after it.
+func rawAudioFormat(header []byte) string {
+	switch {
+	case len(header) >= 6 && header[0] == 0x0B && header[1] == 0x77:
+		if !rawAudioFramesChain(header, ac3FrameSpan) {
+			return ""
+		}
+		if header[5]>>3 <= 10 {
+			if _, _, ok := parseAC3Frame(header); ok {
+				return "AC-3"
+			}
+		} else if _, _, ok := parseEAC3FrameWithOptions(header, false); ok {
+			return "E-AC-3"
+		}
+	case bytes.HasPrefix(header, []byte("DTSHDHDR")):
+		return "DTS"
+	case dtsCoreSyncAt(header):
+		if _, ok := parseDTSCoreFrame(header); ok && rawAudioFramesChain(header, dtsFrameSpan) {
+			return "DTS"
+		}
+	case len(header) >= 8 && bytes.Equal(header[4:8], []byte{0xF8, 0x72, 0x6F, 0xBA}):
+		if sync, ok := parseTrueHDMajorSync(header); ok && rawAudioFramesChain(header, trueHDFrameSpan(sync.spf)) {
+			return "MLP FBA"
+		}
+	case len(header) >= 7 && header[0] == 0xFF:
+		if rawAudioFramesChain(header, adtsFrameSpan) && adtsSampleRate(int(header[2]>>2)&0x0F) > 0 {
+			return "ADTS"
+		}
+	case len(header) >= 3 && header[0] == 0x56:
+		if rawAudioFramesChain(header, loasFrameSpan) {
+			return "LATM"
+		}
+	}
+	return ""
+}
+
+// appendRawAudioStats adds the duration, frame and sample counts and stream size of a raw
+// audio stream and returns its duration.
+func appendRawAudioStats(fields []Field, jsonExtras map[string]string, frames, samples int64, sampleRate float64, streamBytes int64) ([]Field, float64) {
+	duration := 0.0
+	if sampleRate > 0 && samples > 0 {
+		duration = float64(samples) / sampleRate
+		fields = addStreamDuration(fields, duration)
+		jsonExtras["Duration"] = formatJSONSeconds(duration)
+		jsonExtras["SamplingCount"] = strconv.FormatInt(samples, 10)
+	}
+	if frames > 0 {
+		jsonExtras["FrameCount"] = strconv.FormatInt(frames, 10)
+	}
+	if streamBytes > 0 {
+		if streamSize := formatStreamSize(streamBytes, streamBytes); streamSize != "" {
+			fields = append(fields, Field{Name: "Stream size", Value: streamSize})
+		}
+		jsonExtras["StreamSize"] = strconv.FormatInt(streamBytes, 10)
+	}
+	return fields, duration
+}
+
+// appendRawAudioBitRate adds the bit rate mode and the bit rate, measured from the
+// stream size when bitRate is 0.
+func appendRawAudioBitRate(fields []Field, info *ContainerInfo, mode string, bitRate float64, streamBytes int64, duration float64) []Field {
+	if bitRate <= 0 && duration > 0 {
+		bitRate = float64(streamBytes) * 8 / duration
+	}
+	fields = append(fields, Field{Name: "Bit rate mode", Value: mode})
+	info.BitrateMode = mode
+	return addStreamBitrate(fields, bitRate)
+}
+
+// rawAudioResult wraps the fields of a raw audio stream in its ContainerInfo and stream.
+func rawAudioResult(info ContainerInfo, fields []Field, jsonExtras map[string]string, duration float64) (ContainerInfo, []Stream, bool) {
+	info.DurationSeconds = duration
+	return info, []Stream{{Kind: StreamAudio, Fields: fields, JSON: jsonExtras, JSONSkipStreamOrder: true}}, true
+}
+
+// ParseAC3 reads raw AC-3 and E-AC-3 streams (.ac3, .eac3). The first frames are decoded
+// as in transport streams; the duration comes from the samples of all syncframes.
+func ParseAC3(r io.ReaderAt, size int64, parseSpeed float64) (ContainerInfo, []Stream, bool) {
+	head, ok := readRawHead(r, size)
+	if !ok || len(head) < 6 {
+		return ContainerInfo{}, nil, false
+	}
+	format := "AC-3"
+	if head[5]>>3 > 10 {
+		format = "E-AC-3"
+	}
+	entry := &tsStream{kind: StreamAudio, format: format}
+	consumeAudio(entry, head, false, false, false)
+	if !entry.hasAC3 {
+		return ContainerInfo{}, nil, false
+	}
+	ac3 := entry.ac3Info
+	frames, samples := scanRawAudio(r, size, parseSpeed, ac3FrameSpan)
+
+	fields := []Field{
+		{Name: "Format", Value: format},
+		{Name: "Format/Info", Value: mapMatroskaFormatInfo(format)},
+	}
+	jsonExtras := map[string]string{"Format_Settings_Endianness": "Big"}
+	commercialName := "Dolby Digital"
+	if format == "E-AC-3" {
+		commercialName = "Dolby Digital Plus"
+		if ac3.hasJOC || ac3.hasJOCComplex || ac3.jocObjects > 0 || ac3.hasJOCDyn || ac3.hasJOCBed {
+			commercialName = "Dolby Digital Plus with Dolby Atmos"
+			jsonExtras["Format_AdditionalFeatures"] = "JOC"
+			if ac3.hasJOCComplex {
+				fields = append(fields, Field{Name: "Complexity index", Value: strconv.Itoa(ac3.jocComplexity)})
+			}
+			if ac3.hasJOCDyn {
+				fields = append(fields, Field{Name: "Number of dynamic objects", Value: strconv.Itoa(ac3.jocDynObjects)})
+			}
+		}
+	}
+	fields = append(fields, Field{Name: "Commercial name", Value: commercialName})
+
+	fields, duration := appendRawAudioStats(fields, jsonExtras, frames, samples, ac3.sampleRate, size)
+	var info ContainerInfo
+	bitRate := 0.0
+	if format == "AC-3" {
+		bitRate = float64(ac3.bitRateKbps * 1000)
+	}
+	fields = appendRawAudioBitRate(fields, &info, "Constant", bitRate, size, duration)
+	if ac3.channels > 0 {
+		fields = append(fields, Field{Name: "Channel(s)", Value: formatChannels(ac3.channels)})
+	}
+	if ac3.layout != "" {
+		fields = append(fields, Field{Name: "Channel layout", Value: ac3.layout})
+	}
+	fields = appendSampleRateField(fields, ac3.sampleRate)
+	if value := formatAudioFrameRate(ac3.frameRate, ac3.spf); value != "" {
+		fields = append(fields, Field{Name: "Frame rate", Value: value})
+		jsonExtras["SamplesPerFrame"] = strconv.Itoa(ac3.spf)
+	}
+	fields = append(fields, Field{Name: "Compression mode", Value: "Lossy"})
+	if ac3.serviceKind != "" {
+		fields = append(fields, Field{Name: "Service kind", Value: ac3.serviceKind})
+	}
+	if code := ac3ServiceKindCode(ac3.bsmod); code != "" {
+		jsonExtras["ServiceKind"] = code
+	}
+	if ac3.hasDialnorm {
+		fields = append(fields, Field{Name: "Dialog Normalization", Value: strconv.Itoa(ac3.dialnorm) + " dB"})
+	}
+	if ac3.hasCompr {
+		fields = append(fields, Field{Name: "compr", Value: fmt.Sprintf("%.2f dB", ac3.comprDB)})
+	}
+	return rawAudioResult(info, fields, jsonExtras, duration)
+}
+
+// ParseDTS reads raw DTS and DTS-HD streams (.dts, .dtshd), including the chunked files
+// of the DTS-HD tools whose stream sits in a STRMDATA chunk. The core gives the stream
+// parameters, the extension substream the HD channel layout and bit depth.
+func ParseDTS(r io.ReaderAt, size int64, parseSpeed float64) (ContainerInfo, []Stream, bool) {
+	if start, length, ok := dtsHDFileStreamData(r, size); ok {
+		r = io.NewSectionReader(r, start, length)
+		size = length
+	}
+	head, ok := readRawHead(r, size)
+	if !ok {
+		return ContainerInfo{}, nil, false
+	}
+	var core dtsInfo
+	coreAt := -1
+	for i := 0; i+12 <= len(head) && coreAt < 0; i++ {
+		if dtsCoreSyncAt(head[i:]) {
+			if parsed, ok := parseDTSCoreFrame(head[i:]); ok {
+				core, coreAt = parsed, i
+			}
+		}
+	}
+	if coreAt < 0 {
+		return ContainerInfo{}, nil, false
+	}
+	head = head[coreAt:]
+	frames, samples := scanRawAudio(r, size, parseSpeed, dtsFrameSpan)
+
+	channels := uint64(core.channels)
+	layout := channelLayout(channels)
+	bitDepth := core.bitDepth
+	hd := hasDTSHDExtension(head)
+	xllBitDepth, xll := parseDTSHDXLLBitDepth(head)
+	if hd {
+		if ch, mask, depth, ok := parseDTSHDExSSMeta(head); ok {
+			if ch > 0 {
+				channels = uint64(ch)
+				layout = channelLayout(channels)
+			}
+			if mask > 0 {
+				layout = dtsHDSpeakerActivityMaskChannelLayout(mask)
+			}
+			if depth > 0 {
+				bitDepth = depth
+			}
+		}
+		if xll && xllBitDepth > 0 {
+			bitDepth = xllBitDepth
+		}
+	}
+
+	fields := []Field{
+		{Name: "Format", Value: "DTS"},
+		{Name: "Format/Info", Value: "Digital Theater Systems"},
+	}
+	jsonExtras := map[string]string{
+		"Format_Settings_Mode":       "16",
+		"Format_Settings_Endianness": "Big",
+	}
+	compression := "Lossy"
+	switch {
+	case hd && xll:
+		fields = append(fields, Field{Name: "Commercial name", Value: "DTS-HD Master Audio"})
+		jsonExtras["Format_AdditionalFeatures"] = "XLL"
+		compression = "Lossless"
+	case hd:
+		fields = append(fields, Field{Name: "Commercial name", Value: "DTS-HD High Resolution Audio"})
+	}
+	fields, duration := appendRawAudioStats(fields, jsonExtras, frames, samples, float64(core.sampleRate), size)
+	var info ContainerInfo
+	if hd {
+		fields = appendRawAudioBitRate(fields, &info, "Variable", 0, size, duration)
+	} else {
+		bitRate := core.bitRateBps
+		// Code 0x0F maps to 754.5 kb/s in table form; MediaInfo rounds this mode to 768 kb/s.
+		if bitRate == 754500 {
+			bitRate = 768000
+		}
+		fields = appendRawAudioBitRate(fields, &info, "Constant", float64(bitRate), size, duration)
+	}
+	fields = append(fields, Field{Name: "Channel(s)", Value: formatChannels(channels)})
+	if layout != "" {
+		fields = append(fields, Field{Name: "Channel layout", Value: layout})
+	}
+	fields = appendSampleRateField(fields, float64(core.sampleRate))
+	if value := formatAudioFrameRate(float64(core.sampleRate)/float64(core.samplesPerFrame), core.samplesPerFrame); value != "" {
+		fields = append(fields, Field{Name: "Frame rate", Value: value})
+		jsonExtras["SamplesPerFrame"] = strconv.Itoa(core.samplesPerFrame)
+	}
+	if bitDepth > 0 && bitDepth <= 255 {
+		fields = append(fields, Field{Name: "Bit depth", Value: formatBitDepth(uint8(bitDepth))})
+	}
+	fields = append(fields, Field{Name: "Compression mode", Value: compression})
+	return rawAudioResult(info, fields, jsonExtras, duration)
+}
+
+// dtsHDFileStreamData returns the position of the STRMDATA chunk of a file written by the
+// DTS-HD tools: chunks are an 8-byte ID and a 64-bit big-endian size, starting with
+// DTSHDHDR.
+func dtsHDFileStreamData(r io.ReaderAt, size int64) (int64, int64, bool) {
+	var header [16]byte
+	for offset := int64(0); offset+16 <= size; {
+		if _, err := r.ReadAt(header[:], offset); err != nil {
+			return 0, 0, false
+		}
+		id := string(header[:8])
+		if offset == 0 && id != "DTSHDHDR" {
+			return 0, 0, false
+		}
+		chunkSize := int64(binary.BigEndian.Uint64(header[8:]))
+		if chunkSize < 0 || chunkSize > size-offset-16 {
+			chunkSize = size - offset - 16
+		}
+		if id == "STRMDATA" {
+			return offset + 16, chunkSize, chunkSize > 0
+		}
+		offset += 16 + chunkSize
+	}
+	return 0, 0, false
+}
+
+// ParseTrueHD reads raw Dolby TrueHD streams (.thd): the first major sync gives the
+// stream parameters and every access unit carries the same number of samples.
+func ParseTrueHD(r io.ReaderAt, size int64, parseSpeed float64) (ContainerInfo, []Stream, bool) {
+	head, ok := readRawHead(r, size)
+	if !ok {
+		return ContainerInfo{}, nil, false
+	}
+	sync, ok := parseTrueHDMajorSync(head)
+	if !ok {
+		return ContainerInfo{}, nil, false
+	}
+	frames, samples := scanRawAudio(r, size, parseSpeed, trueHDFrameSpan(sync.spf))
+
+	fields := []Field{
+		{Name: "Format", Value: "MLP FBA"},
+		{Name: "Format/Info", Value: "Meridian Lossless Packing FBA"},
+	}
+	jsonExtras := map[string]string{}
+	if sync.atmos {
+		fields = append(fields, Field{Name: "Commercial name", Value: "Dolby TrueHD with Dolby Atmos"})
+		jsonExtras["Format_AdditionalFeatures"] = "16-ch"
+	} else {
+		fields = append(fields, Field{Name: "Commercial name", Value: "Dolby TrueHD"})
+	}
+	fields, duration := appendRawAudioStats(fields, jsonExtras, frames, samples, sync.sampleRate, size)
+	var info ContainerInfo
+	mode := "Constant"
+	if sync.vbr {
+		mode = "Variable"
+	}
+	fields = appendRawAudioBitRate(fields, &info, mode, 0, size, duration)
+	if sync.vbr && sync.peakBitRate > 0 {
+		fields = append(fields, Field{Name: "Maximum bit rate", Value: formatBitrate(float64(sync.peakBitRate))})
+	}
+	fields = append(fields, Field{Name: "Channel(s)", Value: formatChannels(sync.channels)})
+	fields = append(fields, Field{Name: "Channel layout", Value: sync.layout})
+	fields = appendSampleRateField(fields, sync.sampleRate)
+	fields = append(fields, Field{Name: "Frame rate", Value: formatAudioFrameRate(sync.sampleRate/float64(sync.spf), sync.spf)})
+	jsonExtras["SamplesPerFrame"] = strconv.Itoa(sync.spf)
+	fields = append(fields, Field{Name: "Compression mode", Value: "Lossless"})
+	return rawAudioResult(info, fields, jsonExtras, duration)
+}
+
+// ParseAAC reads raw AAC streams in ADTS (.aac) or LOAS/LATM framing. The first frames
+// are decoded as in transport streams; every raw data block holds 1024 samples.
+func ParseAAC(r io.ReaderAt, size int64, parseSpeed float64) (ContainerInfo, []Stream, bool) {
+	head, ok := readRawHead(r, size)
+	if !ok {
+		return ContainerInfo{}, nil, false
+	}
+	entry := &tsStream{kind: StreamAudio, format: "AAC"}
+	span := adtsFrameSpan
+	if head[0] == 0x56 {
+		entry.streamType = 0x11
+		span = loasFrameSpan
+	}
+	consumeAudio(entry, head, false, false, false)
+	if !entry.hasAudioInfo {
+		return ContainerInfo{}, nil, false
+	}
+	frames, samples := scanRawAudio(r, size, parseSpeed, span)
+
+	format := "AAC"
+	if entry.audioProfile != "" {
+		format += " " + entry.audioProfile
+	}
+	fields := []Field{{Name: "Format", Value: format}}
+	formatInfo := "Advanced Audio Codec"
+	if entry.audioProfile == "LC" {
+		formatInfo = "Advanced Audio Codec Low Complexity"
+	}
+	fields = append(fields, Field{Name: "Format/Info", Value: formatInfo})
+	if entry.streamType == 0x11 {
+		fields = append(fields, Field{Name: "Muxing mode", Value: "LATM"})
+	} else {
+		fields = append(fields, Field{Name: "Format version", Value: formatAACVersion(entry.audioMPEGVersion)})
+	}
+	jsonExtras := map[string]string{}
+	fields, duration := appendRawAudioStats(fields, jsonExtras, frames, samples, entry.audioRate, size)
+	var info ContainerInfo
+	fields = appendRawAudioBitRate(fields, &info, "Variable", 0, size, duration)
+	fields = appendChannelFields(fields, entry.audioChannels)
+	fields = appendSampleRateField(fields, entry.audioRate)
+	fields = append(fields, Field{Name: "Frame rate", Value: formatAudioFrameRate(entry.audioRate/1024, 1024)})
+	jsonExtras["SamplesPerFrame"] = "1024"
+	fields = append(fields, Field{Name: "Compression mode", Value: "Lossy"})
+	return rawAudioResult(info, fields, jsonExtras, duration)
+}
@@ -0,0 +1,150 @@
+package mediainfo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// sealEAC3CRC sets crc2, the last two bytes of an E-AC-3 syncframe, so the frame CRC
+// checks out.
+func sealEAC3CRC(frame []byte) []byte {
+	crc := uint16(0)
+	for _, b := range frame[2 : len(frame)-2] {
+		crc = crc<<8 ^ ac3CRC16Table[byte(crc>>8)^b]
+	}
+	frame[len(frame)-2] = byte(crc >> 8)
+	frame[len(frame)-1] = byte(crc)
+	return frame
+}
+
+func buildADTSFrame(payload int) []byte {
+	size := 7 + payload
+	frame := make([]byte, size)
+	frame[0] = 0xFF
+	frame[1] = 0xF1                  // MPEG-4, no CRC
+	frame[2] = 1<<6 | 3<<2           // AAC LC, 48 kHz
+	frame[3] = 2<<6 | byte(size>>11) // 2 channels
+	frame[4] = byte(size >> 3)
+	frame[5] = byte(size&7)<<5 | 0x1F
+	frame[6] = 0xFC
+	return frame
+}
+
+// buildTrueHDStream returns units 40-byte access units of a 48 kHz 7.1 stream, the first
+// one carrying the major sync.
+func buildTrueHDStream(units int) []byte {
+	var data []byte
+	for i := range units {
+		unit := make([]byte, 40)
+		unit[0] = 0xF0 | 40/2>>8
+		unit[1] = 40 / 2
+		if i == 0 {
+			w := &testBitWriter{}
+			w.put(0xF8726FBA, 32)
+			w.put(0, 4)    // audio_sampling_frequency: 48 kHz
+			w.put(0, 8)    // multichannel types, channel modifiers
+			w.put(0x0F, 5) // 6ch assignment: L R, C, LFE, Ls Rs
+			w.put(0, 2)
+			w.put(0x4F, 13) // 8ch assignment: L R, C, LFE, Ls Rs, Lb Rb
+			w.put(0xB752, 16)
+			w.put(0, 32)
+			w.put(1, 1)     // variable_rate
+			w.put(1274, 15) // peak_data_rate
+			w.put(3, 4)     // substreams
+			copy(unit[4:], w.buf)
+		}
+		data = append(data, unit...)
+	}
+	return data
+}
+
+func analyzeRawAudio(t *testing.T, data []byte, name, format string, want []string) {
+	t.Helper()
+	if got := DetectFormat(data[:min(len(data), maxSniffBytes)], name); got != format {
+		t.Fatalf("DetectFormat = %q, want %q", got, format)
+	}
+	report, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), name, defaultAnalyzeOptions())
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	text := RenderText([]Report{report})
+	for _, line := range want {
+		if !strings.Contains(text, line) {
+			t.Errorf("missing %q in:\n%s", line, text)
+		}
+	}
+}
+
+func TestAnalyzeRawEAC3(t *testing.T) {
+	var data []byte
+	for range 100 {
+		data = append(data, sealEAC3CRC(buildEAC3Frame(768, 27, 0x00))...)
+	}
+	analyzeRawAudio(t, data, "clip.eac3", "E-AC-3", []string{
+		"Format                                   : E-AC-3\n",
+		"Commercial name                          : Dolby Digital Plus\n",
+		"Duration                                 : 3 s 200 ms\n",
+		"Bit rate mode                            : Constant\n",
+		"Bit rate                                 : 192 kb/s\n",
+		"Channel layout                           : L R\n",
+		"Sampling rate                            : 48.0 kHz\n",
+		"Frame rate                               : 31.250 FPS (1536 SPF)\n",
+		"Dialog Normalization                     : -27 dB\n",
+	})
+}
+
+func TestAnalyzeRawDTS(t *testing.T) {
+	var data []byte
+	for range 150 {
+		frame := make([]byte, 96)
+		copy(frame, buildDTSCoreFrame(9, 1, 15))
+		data = append(data, frame...)
+	}
+	analyzeRawAudio(t, data, "clip.dts", "DTS", []string{
+		"Format                                   : DTS\n",
+		"Duration                                 : 1 s 600 ms\n",
+		"Bit rate mode                            : Constant\n",
+		"Bit rate                                 : 768 kb/s\n",
+		"Channel(s)                               : 6 channels\n",
+		"Frame rate                               : 93.750 FPS (512 SPF)\n",
+		"Bit depth                                : 24 bits\n",
+	})
+}
+
+func TestAnalyzeRawTrueHD(t *testing.T) {
+	analyzeRawAudio(t, buildTrueHDStream(1200), "clip.thd", "MLP FBA", []string{
+		"Format                                   : MLP FBA\n",
+		"Commercial name                          : Dolby TrueHD\n",
+		"Duration                                 : 1 s 0 ms\n",
+		"Maximum bit rate                         : 3 822 kb/s\n",
+		"Channel(s)                               : 8 channels\n",
+		"Channel layout                           : L R C LFE Ls Rs Lb Rb\n",
+		"Frame rate                               : 1200.000 FPS (40 SPF)\n",
+		"Compression mode                         : Lossless\n",
+	})
+}
+
+func TestAnalyzeRawADTS(t *testing.T) {
+	var data []byte
+	for range 75 {
+		data = append(data, buildADTSFrame(100)...)
+	}
+	analyzeRawAudio(t, data, "clip.aac", "ADTS", []string{
+		"Format                                   : ADTS\n",
+		"Format                                   : AAC LC\n",
+		"Format version                           : Version 4\n",
+		"Duration                                 : 1 s 600 ms\n",
+		"Bit rate mode                            : Variable\n",
+		"Channel(s)                               : 2 channels\n",
+		"Sampling rate                            : 48.0 kHz\n",
+	})
+}
+
+func TestRawAudioFormatNeedsFrameChain(t *testing.T) {
+	data := append(buildADTSFrame(100), 0x00, 0x00, 0x00)
+	data = append(data, make([]byte, 100)...)
+	if got := DetectFormat(data, "clip.aac"); got == "ADTS" {
+		t.Fatalf("DetectFormat = %q for a lone ADTS header", got)
+	}
+}
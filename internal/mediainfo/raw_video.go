@@ -0,0 +1,291 @@
+package mediainfo
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strconv"
+)
+
+const (
+	// rawHeadBytes is read from raw elementary streams for the parameter sets, SEI messages
+	// and the first audio frames.
+	rawHeadBytes = 1 << 20
+	// rawScanBudget bounds the frame count scan below ParseSpeed 1; the count is
+	// extrapolated to the file size past it.
+	rawScanBudget = 64 << 20
+	rawScanChunk  = 1 << 20
+	// rawNALPeek is the number of bytes after a start code handed to picture counters: the
+	// NAL unit header and the start of a slice header.
+	rawNALPeek = 32
+)
+
+// readRawHead reads the first rawHeadBytes of a raw elementary stream.
+func readRawHead(r io.ReaderAt, size int64) ([]byte, bool) {
+	head := make([]byte, min(size, rawHeadBytes))
+	n, err := r.ReadAt(head, 0)
+	if err != nil && err != io.EOF || n == 0 {
+		return nil, false
+	}
+	return head[:n], true
+}
+
+// rawScanSize returns how much of a raw elementary stream is scanned for frames.
+func rawScanSize(size int64, parseSpeed float64) int64 {
+	if parseSpeed < 1 && size > rawScanBudget {
+		return rawScanBudget
+	}
+	return size
+}
+
+// extrapolateRawCount scales a count taken over the first scanned bytes to size.
+func extrapolateRawCount(count, scanned, size int64) int64 {
+	if scanned < size && scanned > 0 {
+		return count * size / scanned
+	}
+	return count
+}
+
+// countAnnexBPictures sums picture(nal) over the NAL units of the first size bytes of an
+// Annex B stream. nal holds at most rawNALPeek bytes.
+func countAnnexBPictures(r io.ReaderAt, size int64, picture func(nal []byte) int64) int64 {
+	// Chunks overlap so a start code and the bytes peeked after it are always seen whole.
+	const overlap = 3 + rawNALPeek
+	var count int64
+	buf := make([]byte, rawScanChunk+overlap)
+	for offset := int64(0); offset < size; offset += rawScanChunk {
+		n, err := r.ReadAt(buf[:min(int64(len(buf)), size-offset)], offset)
+		if n == 0 {
+			break
+		}
+		chunk := buf[:n]
+		for pos := 0; ; {
+			idx := bytes.Index(chunk[pos:], []byte{0x00, 0x00, 0x01})
+			if idx < 0 || pos+idx >= rawScanChunk {
+				break
+			}
+			pos += idx + 3
+			count += picture(chunk[pos:min(pos+rawNALPeek, n)])
+		}
+		if err != nil {
+			break
+		}
+	}
+	return count
+}
+
+// rawVideoFormat detects raw AVC, HEVC and VC-1 elementary streams: the first NAL unit
+// must be one that starts an access unit and the header must hold a parsable sequence
+// header.
+func rawVideoFormat(header []byte) string {
+	start, startLen := findAnnexBStartCode(header, 0)
+	if start != 0 || len(header) < startLen+2 {
+		return ""
+	}
+	nal := header[startLen:]
+	if startLen == 3 && nal[0] == 0x0F {
+		if _, ok := parseVC1AnnexBMeta(header); ok {
+			return "VC-1"
+		}
+		return ""
+	}
+	switch {
+	case nal[0] == 0x06 || nal[0]&0x9F == 0x07 || nal[0] == 0x09:
+		// SEI, SPS or access unit delimiter.
+		if _, _, ok := parseH264AnnexBMeta(header); ok {
+			return "AVC"
+		}
+	case nal[0]&0x81 == 0 && nal[1]&0x07 != 0:
+		switch nal[0] >> 1 {
+		case 32, 33, 35, 39: // VPS, SPS, access unit delimiter, prefix SEI
+			if _, _, _, ok := parseHEVCAnnexBMeta(header); ok {
+				return "HEVC"
+			}
+		}
+	}
+	return ""
+}
+
+// ParseAVC reads raw H.264 Annex B streams (.h264, .264). The SPS, PPS and the x264 SEI
+// come from the head of the file; frames are counted from the first slice of each
+// picture, with field pictures counted as half a frame.
+func ParseAVC(r io.ReaderAt, size int64, parseSpeed float64) (ContainerInfo, []Stream, bool) {
+	head, ok := readRawHead(r, size)
+	if !ok {
+		return ContainerInfo{}, nil, false
+	}
+	avcFields, sps, ok := parseH264AnnexBMeta(head)
+	if !ok {
+		return ContainerInfo{}, nil, false
+	}
+
+	scanSize := rawScanSize(size, parseSpeed)
+	fields := countAnnexBPictures(r, scanSize, func(nal []byte) int64 {
+		return avcPictureFields(nal, sps)
+	})
+	frames := extrapolateRawCount(fields, scanSize, size) / 2
+
+	streamFields := []Field{
+		{Name: "Format", Value: "AVC"},
+		{Name: "Format/Info", Value: "Advanced Video Codec"},
+	}
+	jsonExtras := map[string]string{}
+	streamFields = append(streamFields, avcFields...)
+	streamFields = appendColorInfoFields(streamFields, sps, jsonExtras)
+	if lib, settings := findX264Info(head); lib != "" {
+		streamFields = append(streamFields, Field{Name: "Writing library", Value: lib})
+		if settings != "" {
+			streamFields = append(streamFields, Field{Name: "Encoding settings", Value: settings})
+		}
+	}
+	streamFields = appendVideoStreamStats(streamFields, jsonExtras, frames, sps.FrameRate, size, size, sps.Width, sps.Height)
+	streamFields = applySampleAspectRatio(streamFields, jsonExtras, sps)
+	return rawVideoResult(streamFields, jsonExtras, frames, sps.FrameRate)
+}
+
+// avcPictureFields returns the number of fields started by nal: two for the first slice
+// of a frame, one for the first slice of a field picture, zero otherwise.
+func avcPictureFields(nal []byte, sps h264SPSInfo) int64 {
+	if len(nal) < 2 || nal[0]&0x80 != 0 {
+		return 0
+	}
+	if nalType := nal[0] & 0x1F; nalType != 1 && nalType != 5 {
+		return 0
+	}
+	br := newBitReader(nalToRBSP(nal))
+	if firstMB, ok := br.readUEWithOk(); !ok || firstMB != 0 {
+		return 0
+	}
+	if sps.FrameMbsOnly {
+		return 2
+	}
+	br.readUE() // slice_type
+	br.readUE() // pic_parameter_set_id
+	if sps.SeparateColourPlane {
+		br.readBitsValue(2)
+	}
+	br.readBitsValue(uint8(sps.Log2MaxFrameNumMinus4 + 4)) // frame_num
+	if br.readBitsValue(1) == 1 {                          // field_pic_flag
+		return 1
+	}
+	return 2
+}
+
+// ParseHEVC reads raw H.265 Annex B streams (.hevc, .265). The SPS and HDR SEI messages
+// come from the head of the file; pictures are counted from the first slice segment of
+// each base layer picture.
+func ParseHEVC(r io.ReaderAt, size int64, parseSpeed float64) (ContainerInfo, []Stream, bool) {
+	head, ok := readRawHead(r, size)
+	if !ok {
+		return ContainerInfo{}, nil, false
+	}
+	hevcFields, sps, hdr, ok := parseHEVCAnnexBMeta(head)
+	if !ok {
+		return ContainerInfo{}, nil, false
+	}
+
+	scanSize := rawScanSize(size, parseSpeed)
+	frames := extrapolateRawCount(countAnnexBPictures(r, scanSize, hevcPictureCount), scanSize, size)
+
+	fields := []Field{
+		{Name: "Format", Value: "HEVC"},
+		{Name: "Format/Info", Value: "High Efficiency Video Coding"},
+	}
+	jsonExtras := map[string]string{}
+	fields = append(fields, hevcFields...)
+	fields = appendColorInfoFields(fields, sps, jsonExtras)
+	fields = appendHDRInfoFields(fields, hdr, jsonExtras)
+	fields = appendVideoStreamStats(fields, jsonExtras, frames, sps.FrameRate, size, size, sps.Width, sps.Height)
+	fields = applySampleAspectRatio(fields, jsonExtras, sps)
+	return rawVideoResult(fields, jsonExtras, frames, sps.FrameRate)
+}
+
+// hevcPictureCount returns 1 when nal is the first slice segment of a base layer picture.
+func hevcPictureCount(nal []byte) int64 {
+	if len(nal) < 3 || nal[0]&0x80 != 0 || nal[1]&0x07 == 0 {
+		return 0
+	}
+	nalType := (nal[0] >> 1) & 0x3F
+	layerID := (nal[0]&0x01)<<5 | nal[1]>>3
+	if nalType <= 21 && layerID == 0 && nal[2]&0x80 != 0 { // first_slice_segment_in_pic_flag
+		return 1
+	}
+	return 0
+}
+
+// ParseVC1 reads raw VC-1 Advanced profile streams (.vc1): the sequence header gives the
+// stream parameters and frames are counted from frame start codes.
+func ParseVC1(r io.ReaderAt, size int64, parseSpeed float64) (ContainerInfo, []Stream, bool) {
+	head, ok := readRawHead(r, size)
+	if !ok {
+		return ContainerInfo{}, nil, false
+	}
+	meta, ok := parseVC1AnnexBMeta(head)
+	if !ok {
+		return ContainerInfo{}, nil, false
+	}
+
+	scanSize := rawScanSize(size, parseSpeed)
+	frames := extrapolateRawCount(countAnnexBPictures(r, scanSize, func(nal []byte) int64 {
+		if len(nal) > 0 && nal[0] == 0x0D {
+			return 1
+		}
+		return 0
+	}), scanSize, size)
+
+	fields := []Field{{Name: "Format", Value: "VC-1"}}
+	jsonExtras := map[string]string{}
+	if meta.Profile != "" {
+		fields = append(fields, Field{Name: "Format profile", Value: meta.Profile})
+	}
+	if meta.Level > 0 {
+		fields = append(fields, Field{Name: "Format level", Value: strconv.Itoa(meta.Level)})
+	}
+	fields = append(fields, Field{Name: "Color space", Value: "YUV"})
+	if meta.ChromaSubsampling != "" {
+		fields = append(fields, Field{Name: "Chroma subsampling", Value: meta.ChromaSubsampling})
+	}
+	fields = append(fields, Field{Name: "Bit depth", Value: "8 bits"})
+	if meta.ScanType != "" {
+		fields = append(fields, Field{Name: "Scan type", Value: meta.ScanType})
+	}
+	fields = append(fields, Field{Name: "Compression mode", Value: "Lossy"})
+	if meta.BufferSize > 0 {
+		jsonExtras["BufferSize"] = strconv.FormatInt(meta.BufferSize, 10)
+	}
+	if meta.FrameRateNum > 0 && meta.FrameRateDen > 0 {
+		jsonExtras["FrameRate_Num"] = strconv.Itoa(meta.FrameRateNum)
+		jsonExtras["FrameRate_Den"] = strconv.Itoa(meta.FrameRateDen)
+	}
+	fields = appendVideoStreamStats(fields, jsonExtras, frames, meta.FrameRate, size, size, meta.Width, meta.Height)
+	if meta.PixelAspectRatio > 0 && meta.PixelAspectRatio != 1 && meta.Height > 0 {
+		jsonExtras["PixelAspectRatio"] = formatJSONFloat(meta.PixelAspectRatio)
+		displayWidth := uint64(math.Round(float64(meta.Width) * meta.PixelAspectRatio * 1000))
+		if ar := formatAspectRatio(displayWidth, meta.Height*1000); ar != "" {
+			fields = setFieldValue(fields, "Display aspect ratio", ar)
+		}
+	}
+	return rawVideoResult(fields, jsonExtras, frames, meta.FrameRate)
+}
+
+// applySampleAspectRatio replaces the display aspect ratio derived from the picture size
+// with the one of the sample aspect ratio signalled in the VUI.
+func applySampleAspectRatio(fields []Field, jsonExtras map[string]string, sps h264SPSInfo) []Field {
+	if !sps.HasSAR || sps.SARWidth == 0 || sps.SARHeight == 0 || sps.SARWidth == sps.SARHeight || sps.Width == 0 || sps.Height == 0 {
+		return fields
+	}
+	jsonExtras["PixelAspectRatio"] = formatJSONFloat(float64(sps.SARWidth) / float64(sps.SARHeight))
+	if ar := formatAspectRatio(sps.Width*uint64(sps.SARWidth), sps.Height*uint64(sps.SARHeight)); ar != "" {
+		fields = setFieldValue(fields, "Display aspect ratio", ar)
+	}
+	return fields
+}
+
+// rawVideoResult wraps the fields of a raw video stream in its ContainerInfo and stream.
+func rawVideoResult(fields []Field, jsonExtras map[string]string, frames int64, frameRate float64) (ContainerInfo, []Stream, bool) {
+	info := ContainerInfo{}
+	if frameRate > 0 {
+		info.DurationSeconds = float64(frames) / frameRate
+	}
+	return info, []Stream{{Kind: StreamVideo, Fields: fields, JSON: jsonExtras, JSONSkipStreamOrder: true}}, true
+}
@@ -0,0 +1,168 @@
+package mediainfo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// testAVCNAL returns an H.264 NAL unit with emulation prevention applied to rbsp.
+func testAVCNAL(header byte, rbsp []byte) []byte {
+	nal := []byte{header}
+	zeros := 0
+	for _, b := range rbsp {
+		if zeros == 2 && b <= 0x03 {
+			nal = append(nal, 0x03)
+			zeros = 0
+		}
+		nal = append(nal, b)
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+	}
+	return nal
+}
+
+// testAVCSPS is a Baseline@L3 640x480 25 fps SPS with a 4:3 sample aspect ratio.
+func testAVCSPS() []byte {
+	w := &testBitWriter{}
+	w.put(66, 8)   // profile_idc
+	w.put(0xC0, 8) // constraint flags
+	w.put(30, 8)   // level_idc
+	w.putUE(0)     // seq_parameter_set_id
+	w.putUE(0)     // log2_max_frame_num_minus4
+	w.putUE(2)     // pic_order_cnt_type
+	w.putUE(1)     // max_num_ref_frames
+	w.put(0, 1)    // gaps_in_frame_num_value_allowed_flag
+	w.putUE(39)    // pic_width_in_mbs_minus1
+	w.putUE(29)    // pic_height_in_map_units_minus1
+	w.put(1, 1)    // frame_mbs_only_flag
+	w.put(1, 1)    // direct_8x8_inference_flag
+	w.put(0, 1)    // frame_cropping_flag
+	w.put(1, 1)    // vui_parameters_present_flag
+	w.put(1, 1)    // aspect_ratio_info_present_flag
+	w.put(255, 8)  // Extended_SAR
+	w.put(4, 16)
+	w.put(3, 16)
+	w.put(0, 3) // overscan, video signal type, chroma loc
+	w.put(1, 1) // timing_info_present_flag
+	w.put(1, 32)
+	w.put(50, 32)
+	w.put(1, 1) // fixed_frame_rate_flag
+	w.put(0, 4) // HRD, pic_struct, bitstream_restriction
+	w.put(1, 1) // rbsp_stop_one_bit
+	return testAVCNAL(0x67, w.buf)
+}
+
+// testVC1SequenceHeader is an Advanced profile 1920x1080 24000/1001 fps sequence header.
+func testVC1SequenceHeader() []byte {
+	w := &testBitWriter{}
+	w.put(3, 2) // profile: Advanced
+	w.put(3, 3) // level
+	w.put(1, 2) // colordiff_format: 4:2:0
+	w.put(0, 9) // frmrtq_postproc, bitrtq_postproc, postprocflag
+	w.put(959, 12)
+	w.put(539, 12)
+	w.put(0, 6) // pulldown, interlace, tfcntrflag, finterpflag, reserved, psf
+	w.put(1, 1) // display_ext
+	w.put(1919, 14)
+	w.put(1079, 14)
+	w.put(0, 1) // aspectratio_flag
+	w.put(1, 1) // framerate_flag
+	w.put(0, 1) // framerateind
+	w.put(1, 8) // frameratenr: 24000
+	w.put(2, 4) // frameratedr: 1001
+	w.put(0, 1) // color_format_flag
+	w.put(0, 1) // hrd_param_flag
+	return append([]byte{0x00, 0x00, 0x01, 0x0F}, w.buf...)
+}
+
+func analyzeRawVideo(t *testing.T, data []byte, name, format string, want []string) {
+	t.Helper()
+	if got := DetectFormat(data[:min(len(data), maxSniffBytes)], name); got != format {
+		t.Fatalf("DetectFormat = %q, want %q", got, format)
+	}
+	report, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), name, defaultAnalyzeOptions())
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	text := RenderText([]Report{report})
+	for _, line := range want {
+		if !strings.Contains(text, line) {
+			t.Errorf("missing %q in:\n%s", line, text)
+		}
+	}
+}
+
+func TestAnalyzeRawAVC(t *testing.T) {
+	data := append([]byte{0x00, 0x00, 0x00, 0x01}, testAVCSPS()...)
+	slice := bytes.Repeat([]byte{0x55}, 200)
+	for i := range 50 {
+		header := byte(0x41) // non-IDR slice
+		if i == 0 {
+			header = 0x65
+		}
+		data = append(data, 0x00, 0x00, 0x01)
+		data = append(data, testAVCNAL(header, append([]byte{0x88}, slice...))...)
+	}
+	analyzeRawVideo(t, data, "clip.h264", "AVC", []string{
+		"Format                                   : AVC\n",
+		"Format profile                           : Baseline@L3\n",
+		"Duration                                 : 2 s 0 ms\n",
+		"Width                                    : 640 pixels\n",
+		"Height                                   : 480 pixels\n",
+		"Display aspect ratio                     : 16:9\n",
+		"Frame rate                               : 25.000 FPS\n",
+	})
+}
+
+func TestAVCPictureFieldsCountsFieldPictures(t *testing.T) {
+	sps := h264SPSInfo{Log2MaxFrameNumMinus4: 0}
+	// first_mb_in_slice 0, slice_type 0, pps_id 0, frame_num 0000, field_pic_flag 1.
+	if got := avcPictureFields([]byte{0x01, 0xE1}, sps); got != 1 {
+		t.Fatalf("field picture = %d, want 1", got)
+	}
+	if got := avcPictureFields([]byte{0x01, 0xE0}, sps); got != 2 {
+		t.Fatalf("frame picture = %d, want 2", got)
+	}
+	// first_mb_in_slice 1: a later slice of the same picture.
+	if got := avcPictureFields([]byte{0x01, 0x40}, sps); got != 0 {
+		t.Fatalf("second slice = %d, want 0", got)
+	}
+}
+
+func TestHEVCPictureCount(t *testing.T) {
+	for _, tc := range []struct {
+		nal  []byte
+		want int64
+	}{
+		{[]byte{0x26, 0x01, 0xAF}, 1}, // IDR_W_RADL, first slice segment
+		{[]byte{0x02, 0x01, 0x80}, 1}, // TRAIL_R, first slice segment
+		{[]byte{0x02, 0x01, 0x40}, 0}, // TRAIL_R, later slice segment
+		{[]byte{0x02, 0x09, 0x80}, 0}, // TRAIL_R, layer 1
+		{[]byte{0x42, 0x01, 0x80}, 0}, // SPS
+	} {
+		if got := hevcPictureCount(tc.nal); got != tc.want {
+			t.Errorf("hevcPictureCount(% X) = %d, want %d", tc.nal, got, tc.want)
+		}
+	}
+}
+
+func TestAnalyzeRawVC1(t *testing.T) {
+	data := testVC1SequenceHeader()
+	for range 48 {
+		data = append(data, 0x00, 0x00, 0x01, 0x0D)
+		data = append(data, bytes.Repeat([]byte{0x55}, 300)...)
+	}
+	analyzeRawVideo(t, data, "clip.vc1", "VC-1", []string{
+		"Format                                   : VC-1\n",
+		"Format profile                           : Advanced\n",
+		"Width                                    : 1 920 pixels\n",
+		"Height                                   : 1 080 pixels\n",
+		"Chroma subsampling                       : 4:2:0\n",
+		"Duration                                 : 2 s 2 ms\n",
+		"Frame rate                               : 23.976 (24000/1001) FPS\n",
+	})
+}
@@ -0,0 +1,281 @@
+package mediainfo
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ReleaseAttributes are the attributes release names advertise, derived from what the
+// file actually contains. Values use scene and tracker spelling ("2160p", "HEVC", "DV",
+// "DDP") so they compare directly with attributes parsed from a release name.
+type ReleaseAttributes struct {
+	// Resolution is the class of the first video stream: "4320p", "2160p", "1080p",
+	// "720p", "576p", "480p" or the height for smaller video, with "i" instead of "p" when
+	// interlaced. Empty without video.
+	Resolution string
+	Width      int
+	Height     int
+	// VideoCodec is "HEVC", "H.264", "AV1", "VVC", "VP9", "VP8", "MPEG-2", "VC-1", "XviD",
+	// "DivX" or the MediaInfo format name.
+	VideoCodec string
+	// VideoEncoder is the encoder that signed the bitstream ("x264", "x265"), if any.
+	VideoEncoder string
+	BitDepth     int
+	// HDR lists the HDR formats, most specific first: "DV", "HDR10+", "HDR10", "HLG", or
+	// "PQ" for PQ video without static metadata.
+	HDR []string
+	// DolbyVisionProfile is the Dolby Vision profile with its base-layer compatibility,
+	// as in "8.1" or "7.6", or "5" when there is none.
+	DolbyVisionProfile string
+	// Audio lists the audio streams in stream order.
+	Audio []ReleaseAudio
+	// AudioLanguages and SubtitleLanguages list the distinct language codes of the audio
+	// and text streams, in stream order.
+	AudioLanguages    []string
+	SubtitleLanguages []string
+}
+
+// ReleaseAudio describes one audio stream.
+type ReleaseAudio struct {
+	// Codec is "TrueHD", "DDP", "DD", "DTS-HD MA", "DTS-HD HRA", "DTS-ES", "DTS", "AAC",
+	// "FLAC", "Opus", "LPCM", "MP3", "MP2" or the MediaInfo format name.
+	Codec string
+	// Atmos is set for TrueHD and E-AC-3 carrying Dolby Atmos objects (JOC).
+	Atmos bool
+	// DTSX is set for DTS:X.
+	DTSX bool
+	// Channels is the layout as "7.1", "5.1" or "2.0".
+	Channels   string
+	Language   string
+	Default    bool
+	Commentary bool
+}
+
+// String writes the stream as release names do: "TrueHD Atmos 7.1", "DTS-HD MA DTS:X 7.1".
+func (a ReleaseAudio) String() string {
+	parts := []string{a.Codec}
+	if a.Atmos {
+		parts = append(parts, "Atmos")
+	}
+	if a.DTSX {
+		parts = append(parts, "DTS:X")
+	}
+	if a.Channels != "" {
+		parts = append(parts, a.Channels)
+	}
+	return strings.Join(parts, " ")
+}
+
+// HDRString joins the HDR formats as release names do: "DV HDR10+".
+func (r ReleaseAttributes) HDRString() string {
+	return strings.Join(r.HDR, " ")
+}
+
+// ExtractReleaseAttributes derives the release attributes of report from its first video
+// stream and all audio and text streams. A failed report yields the zero value.
+func ExtractReleaseAttributes(report Report) ReleaseAttributes {
+	var attrs ReleaseAttributes
+	if report.Err != nil {
+		return attrs
+	}
+	containerFormat := findField(report.General.Fields, "Format")
+	video := false
+	forEachStreamWithKindIndex(orderTracks(report.Streams), func(stream Stream, _, _, order int) {
+		fields := typedFieldsFromJSON(expandJSONExtra(buildJSONStreamFields(stream, order, 0, containerFormat)))
+		switch stream.Kind {
+		case StreamVideo:
+			if video {
+				return
+			}
+			video = true
+			attrs.Width = fields.int("Width")
+			attrs.Height = fields.int("Height")
+			attrs.Resolution = releaseResolution(attrs.Width, attrs.Height, fields["ScanType"])
+			attrs.VideoCodec = releaseVideoCodec(fields)
+			attrs.VideoEncoder = releaseVideoEncoder(fields["Encoded_Library_Name"])
+			attrs.BitDepth = fields.int("BitDepth")
+			attrs.HDR = releaseHDR(fields["HDR_Format"]+" / "+fields["HDR_Format_Compatibility"], fields["transfer_characteristics"], releaseNames)
+			if dovi, ok := ffprobeDOVI(fields); ok && dovi.DVProfile > 0 {
+				attrs.DolbyVisionProfile = strconv.Itoa(dovi.DVProfile)
+				if dovi.DVBLSignalCompatibilityID > 0 {
+					attrs.DolbyVisionProfile += "." + strconv.Itoa(dovi.DVBLSignalCompatibilityID)
+				}
+			}
+		case StreamAudio:
+			codec, atmos, dtsx := releaseAudioCodec(fields["Format"], fields["Format_Profile"], fields["Format_Commercial_IfAny"], fields["Format_AdditionalFeatures"], releaseNames)
+			audio := ReleaseAudio{
+				Codec:      codec,
+				Atmos:      atmos,
+				DTSX:       dtsx,
+				Language:   normalizeLanguageCode(fields["Language"]),
+				Default:    fields.bool("Default"),
+				Commentary: strings.Contains(strings.ToLower(fields["Title"]), "commentary"),
+			}
+			if channels := fields.int("Channels"); channels > 0 {
+				audio.Channels = summaryChannels(channels, fields["ChannelLayout"])
+			}
+			attrs.Audio = append(attrs.Audio, audio)
+			attrs.AudioLanguages = appendLanguage(attrs.AudioLanguages, audio.Language)
+		case StreamText:
+			attrs.SubtitleLanguages = appendLanguage(attrs.SubtitleLanguages, normalizeLanguageCode(fields["Language"]))
+		}
+	})
+	return attrs
+}
+
+// releaseResolution classes a frame by width as well as height so that scope (1920x800)
+// and cropped (3840x1600) encodes get the class of their source.
+func releaseResolution(width, height int, scanType string) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	var lines int
+	switch {
+	case width >= 7000 || height >= 4000:
+		lines = 4320
+	case width >= 3200 || height >= 2000:
+		lines = 2160
+	case width >= 1800 || height >= 1000:
+		lines = 1080
+	case width >= 1200 || height >= 700:
+		lines = 720
+	case height >= 540:
+		lines = 576
+	case height >= 400 || width >= 700:
+		lines = 480
+	default:
+		lines = height
+	}
+	suffix := "p"
+	if scanType != "" && scanType != "Progressive" {
+		suffix = "i"
+	}
+	return strconv.Itoa(lines) + suffix
+}
+
+func releaseVideoCodec(fields typedFields) string {
+	switch fields["Format"] {
+	case "AVC":
+		return "H.264"
+	case "MPEG Video":
+		if strings.Contains(fields["Format_Version"], "1") {
+			return "MPEG-1"
+		}
+		return "MPEG-2"
+	case "MPEG-4 Visual":
+		switch codecID := strings.ToUpper(fields["CodecID"]); {
+		case strings.Contains(codecID, "XVID"):
+			return "XviD"
+		case strings.Contains(codecID, "DIV") || strings.Contains(codecID, "DX50"):
+			return "DivX"
+		}
+		return "MPEG-4"
+	}
+	return fields["Format"]
+}
+
+func releaseVideoEncoder(library string) string {
+	lower := strings.ToLower(library)
+	for _, name := range []string{"x264", "x265", "svt-av1", "rav1e", "xvid"} {
+		if strings.HasPrefix(lower, name) {
+			return library[:len(name)]
+		}
+	}
+	return ""
+}
+
+// releaseNaming selects how releaseHDR and releaseAudioCodec name what they detect.
+type releaseNaming int
+
+const (
+	// releaseNames are the short names of release names: "DV", "DDP", "DD", "LPCM".
+	releaseNames releaseNaming = iota
+	// summaryNames are the names of the tracker summary: "Dolby Vision" and the MediaInfo
+	// format for codecs release names abbreviate.
+	summaryNames
+)
+
+// summaryAudioCodecs maps the release name codecs the tracker summary names differently.
+var summaryAudioCodecs = map[string]string{
+	"DDP":    "E-AC-3",
+	"DD":     "AC-3",
+	"DTS-ES": "DTS",
+	"LPCM":   "PCM",
+	"MP3":    "MPEG Audio",
+	"MP2":    "MPEG Audio",
+}
+
+// releaseHDR names the HDR formats in MediaInfo's HDR_Format and compatibility fields.
+// HDR10+ streams are HDR10 compatible by definition, so HDR10 is only listed without it.
+func releaseHDR(formats, transfer string, naming releaseNaming) []string {
+	var names []string
+	if strings.Contains(formats, "Dolby Vision") {
+		if naming == summaryNames {
+			names = append(names, "Dolby Vision")
+		} else {
+			names = append(names, "DV")
+		}
+	}
+	switch {
+	case strings.Contains(formats, "SMPTE ST 2094 App 4") || strings.Contains(formats, "HDR10+"):
+		names = append(names, "HDR10+")
+	case strings.Contains(formats, "SMPTE ST 2086") || strings.Contains(formats, "HDR10"):
+		names = append(names, "HDR10")
+	case transfer == "HLG" || strings.Contains(formats, "HLG"):
+		names = append(names, "HLG")
+	case transfer == "PQ" && len(names) == 0:
+		names = append(names, "PQ")
+	}
+	return names
+}
+
+// releaseAudioCodec names an audio format as naming asks and reports Atmos and DTS:X.
+func releaseAudioCodec(format, profile, commercial, features string, naming releaseNaming) (codec string, atmos, dtsx bool) {
+	featureSet := strings.Fields(features)
+	switch format {
+	case "MLP FBA", "TrueHD":
+		codec = "TrueHD"
+		atmos = slices.Contains(featureSet, "16-ch")
+	case "E-AC-3", "E-AC-3 JOC":
+		codec = "DDP"
+		atmos = format == "E-AC-3 JOC" || slices.Contains(featureSet, "JOC")
+	case "AC-3":
+		codec = "DD"
+	case "DTS":
+		switch {
+		case slices.Contains(featureSet, "XLL") || strings.Contains(commercial, "Master Audio") || strings.Contains(profile, "MA"):
+			codec = "DTS-HD MA"
+		case slices.Contains(featureSet, "XBR") || strings.Contains(commercial, "High Resolution") || strings.Contains(profile, "HRA"):
+			codec = "DTS-HD HRA"
+		case slices.Contains(featureSet, "ES") || slices.Contains(featureSet, "XXCH") || strings.Contains(profile, "ES"):
+			codec = "DTS-ES"
+		default:
+			codec = "DTS"
+		}
+		dtsx = slices.Contains(featureSet, "X") || strings.Contains(commercial, "DTS:X") || strings.HasPrefix(profile, "X /")
+	case "PCM":
+		codec = "LPCM"
+	case "MPEG Audio":
+		codec = "MP3"
+		if strings.Contains(profile, "Layer 2") {
+			codec = "MP2"
+		}
+	default:
+		codec = format
+	}
+	if strings.Contains(commercial, "Atmos") {
+		atmos = true
+	}
+	if name, ok := summaryAudioCodecs[codec]; ok && naming == summaryNames {
+		codec = name
+	}
+	return codec, atmos, dtsx
+}
+
+func appendLanguage(languages []string, code string) []string {
+	if code == "" || slices.Contains(languages, code) {
+		return languages
+	}
+	return append(languages, code)
+}
@@ -0,0 +1,322 @@
+package mediainfo
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CheckStatus is the outcome of one NameCheck.
+type CheckStatus string
+
+const (
+	// CheckPass means the file has what the name claims.
+	CheckPass CheckStatus = "pass"
+	// CheckFail means the file contradicts the claim.
+	CheckFail CheckStatus = "fail"
+	// CheckUnverified means the file carries nothing to confirm or refute the claim, e.g.
+	// "x265" on HEVC without an encoder signature.
+	CheckUnverified CheckStatus = "unverified"
+)
+
+// NameCheck compares one claim of a release name with the analyzed file.
+type NameCheck struct {
+	// Attribute is "resolution", "video codec", "encoder", "bit depth", "hdr",
+	// "audio codec", "atmos", "dts:x", "channels" or "language".
+	Attribute string      `json:"attribute"`
+	Claimed   string      `json:"claimed"`
+	Actual    string      `json:"actual"`
+	Status    CheckStatus `json:"status"`
+	Reason    string      `json:"reason,omitempty"`
+}
+
+// releaseNameExtensions are stripped from names before parsing.
+var releaseNameExtensions = map[string]bool{
+	".mkv": true, ".mka": true, ".mp4": true, ".m4v": true, ".m4a": true, ".mov": true, ".avi": true,
+	".ts": true, ".m2ts": true, ".mts": true, ".mpg": true, ".mpeg": true, ".vob": true, ".webm": true,
+	".wmv": true, ".flac": true, ".mp3": true, ".wav": true, ".ogg": true, ".iso": true,
+}
+
+// releaseToken matches pattern as a whole token of a release name: delimited by start,
+// end or any character other than a letter, digit or "+".
+func releaseToken(pattern string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(?:^|[^a-z0-9+])(?:` + pattern + `)(?:[^a-z0-9+]|$)`)
+}
+
+type releasePattern struct {
+	re    *regexp.Regexp
+	value string
+}
+
+// The pattern lists are in priority order: the first match of a list is the claim.
+var (
+	releaseResolutionPatterns = []releasePattern{
+		{releaseToken(`4320p`), "4320p"},
+		{releaseToken(`2160p`), "2160p"},
+		{releaseToken(`1080p`), "1080p"},
+		{releaseToken(`1080i`), "1080i"},
+		{releaseToken(`720p`), "720p"},
+		{releaseToken(`576p`), "576p"},
+		{releaseToken(`576i`), "576i"},
+		{releaseToken(`480p`), "480p"},
+		{releaseToken(`480i`), "480i"},
+		// "UHD" also tags 1080p releases of UHD discs; it claims 2160p only without an
+		// explicit resolution.
+		{releaseToken(`4k|uhd`), "2160p"},
+	}
+	releaseVideoPatterns = []releasePattern{
+		{releaseToken(`x265`), "HEVC"},
+		{releaseToken(`x264`), "H.264"},
+		{releaseToken(`h[ .]?265|hevc`), "HEVC"},
+		{releaseToken(`h[ .]?264|avc`), "H.264"},
+		{releaseToken(`h[ .]?266|vvc`), "VVC"},
+		{releaseToken(`av1`), "AV1"},
+		{releaseToken(`vp9`), "VP9"},
+		{releaseToken(`xvid`), "XviD"},
+		{releaseToken(`divx`), "DivX"},
+		{releaseToken(`mpeg-?2`), "MPEG-2"},
+		{releaseToken(`vc-?1`), "VC-1"},
+	}
+	releaseEncoderPatterns = []releasePattern{
+		{releaseToken(`x265`), "x265"},
+		{releaseToken(`x264`), "x264"},
+	}
+	releaseBitDepthPatterns = []releasePattern{
+		{releaseToken(`10-?bits?|hi10p?`), "10"},
+		{releaseToken(`12-?bits?`), "12"},
+		{releaseToken(`8-?bits?`), "8"},
+	}
+	// HDR claims are not exclusive: "DV.HDR10+" claims both.
+	releaseHDRPatterns = []releasePattern{
+		{releaseToken(`dv|dovi|dolby[ ._-]?vision`), "DV"},
+		{releaseToken(`hdr10(?:\+|plus)`), "HDR10+"},
+		{releaseToken(`hdr10`), "HDR10"},
+		{releaseToken(`hdr`), "HDR"},
+		{releaseToken(`hlg`), "HLG"},
+	}
+	// Audio codecs may be followed by Atmos and the channel layout ("DDP5.1", "TrueHD.Atmos.7.1").
+	releaseAudioPatterns = []releasePattern{
+		{releaseAudioToken(`truehd`), "TrueHD"},
+		{releaseAudioToken(`dts[ ._-]?hd[ ._-]?ma`), "DTS-HD MA"},
+		{releaseAudioToken(`dts[ ._-]?hd[ ._-]?hra`), "DTS-HD HRA"},
+		{releaseAudioToken(`dts[ ._-]?x|dts:x`), "DTS:X"},
+		{releaseAudioToken(`dts[ ._-]?hd`), "DTS-HD"},
+		{releaseAudioToken(`dts[ ._-]?es`), "DTS-ES"},
+		{releaseAudioToken(`dts`), "DTS"},
+		{releaseAudioToken(`ddp|dd\+|e-?ac-?3`), "DDP"},
+		{releaseAudioToken(`dd|ac-?3`), "DD"},
+		{releaseAudioToken(`aac`), "AAC"},
+		{releaseAudioToken(`flac`), "FLAC"},
+		{releaseAudioToken(`opus`), "Opus"},
+		{releaseAudioToken(`l?pcm`), "LPCM"},
+		{releaseAudioToken(`mp3`), "MP3"},
+	}
+	// releaseTitleEndPattern matches the year, season, episode or resolution that ends the
+	// title.
+	releaseTitleEndPattern = releaseToken(`(?:19|20)\d\d|s\d{1,2}(?:e\d{1,3})?|\d{3,4}[pi]|4k|uhd`)
+	releaseAtmosPattern    = releaseToken(`atmos`)
+	releaseDTSXPattern     = releaseToken(`dts[ ._-]?x|dts:x`)
+)
+
+// releaseAudioToken matches an audio codec token with an optional Atmos tag and channel
+// layout right after it; the layout is the last submatch.
+func releaseAudioToken(pattern string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(?:^|[^a-z0-9+])(?:` + pattern + `)(?:[ ._-]?atmos)?(?:[ ._-]?([1-9][ ._][01]))?(?:[^a-z0-9+]|$)`)
+}
+
+func matchReleasePattern(name string, patterns []releasePattern) (releasePattern, []string) {
+	for _, pattern := range patterns {
+		if match := pattern.re.FindStringSubmatch(name); match != nil {
+			return pattern, match
+		}
+	}
+	return releasePattern{}, nil
+}
+
+// ParseReleaseName reads the attributes a scene or P2P release name claims, such as
+// "Movie.2023.2160p.UHD.BluRay.DV.HDR10.TrueHD.Atmos.7.1.x265-GROUP". A path or file name
+// may be given; the directory and a media file extension are ignored. Attributes the name
+// does not mention are left empty. HDR may hold the generic "HDR" and the audio codec
+// the generic "DTS-HD", which ExtractReleaseAttributes never reports.
+func ParseReleaseName(name string) ReleaseAttributes {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	if ext := filepath.Ext(name); releaseNameExtensions[strings.ToLower(ext)] {
+		name = strings.TrimSuffix(name, ext)
+	}
+	var claims ReleaseAttributes
+	if pattern, _ := matchReleasePattern(name, releaseResolutionPatterns); pattern.re != nil {
+		claims.Resolution = pattern.value
+	}
+	if pattern, _ := matchReleasePattern(name, releaseVideoPatterns); pattern.re != nil {
+		claims.VideoCodec = pattern.value
+	}
+	if pattern, _ := matchReleasePattern(name, releaseEncoderPatterns); pattern.re != nil {
+		claims.VideoEncoder = pattern.value
+	}
+	if pattern, _ := matchReleasePattern(name, releaseBitDepthPatterns); pattern.re != nil {
+		claims.BitDepth, _ = strconv.Atoi(pattern.value)
+	}
+	for _, pattern := range releaseHDRPatterns {
+		if pattern.re.MatchString(name) {
+			claims.HDR = append(claims.HDR, pattern.value)
+		}
+	}
+	// HDR10+ also matches "HDR" and "HDR10" does too; keep the most specific claim.
+	if slices.Contains(claims.HDR, "HDR10+") || slices.Contains(claims.HDR, "HDR10") {
+		claims.HDR = slices.DeleteFunc(claims.HDR, func(v string) bool { return v == "HDR" })
+	}
+	if pattern, match := matchReleasePattern(name, releaseAudioPatterns); pattern.re != nil {
+		audio := ReleaseAudio{Codec: pattern.value}
+		if layout := match[len(match)-1]; layout != "" {
+			audio.Channels = layout[:1] + "." + layout[len(layout)-1:]
+		}
+		if audio.Codec == "DTS:X" {
+			audio.Codec = "DTS-HD MA"
+		}
+		audio.Atmos = releaseAtmosPattern.MatchString(name)
+		audio.DTSX = releaseDTSXPattern.MatchString(name)
+		claims.Audio = []ReleaseAudio{audio}
+	} else if releaseAtmosPattern.MatchString(name) {
+		claims.Audio = []ReleaseAudio{{Atmos: true}}
+	}
+	// Language words in the title ("The.French.Dispatch") are no claims; only the tags
+	// after it count. Without a year, season or resolution to end the title, only the
+	// uppercase scene tags ("FRENCH") do.
+	tags, sceneTagsOnly := name, true
+	if loc := releaseTitleEndPattern.FindStringIndex(name); loc != nil {
+		tags, sceneTagsOnly = name[loc[0]:], false
+	}
+	for word := range strings.FieldsFuncSeq(tags, func(r rune) bool { return r == '.' || r == ' ' || r == '_' || r == '-' }) {
+		if sceneTagsOnly && word != strings.ToUpper(word) {
+			continue
+		}
+		for code, language := range languageNames {
+			if strings.EqualFold(word, language) {
+				claims.AudioLanguages = appendLanguage(claims.AudioLanguages, code)
+			}
+		}
+	}
+	return claims
+}
+
+// CheckReleaseName compares every claim ParseReleaseName finds in name with report and
+// returns one check per claim, in the order of ReleaseAttributes. A name without
+// recognizable claims yields no checks.
+func CheckReleaseName(name string, report Report) []NameCheck {
+	claims := ParseReleaseName(name)
+	actual := ExtractReleaseAttributes(report)
+	var checks []NameCheck
+	add := func(attribute, claimed, actualValue string, status CheckStatus, reason string) {
+		checks = append(checks, NameCheck{Attribute: attribute, Claimed: claimed, Actual: actualValue, Status: status, Reason: reason})
+	}
+
+	if claims.Resolution != "" {
+		frame := "no video"
+		if actual.Resolution != "" {
+			frame = fmt.Sprintf("%dx%d", actual.Width, actual.Height)
+		}
+		add("resolution", claims.Resolution, actual.Resolution, checkStatus(claims.Resolution == actual.Resolution), "video is "+frame)
+	}
+	if claims.VideoCodec != "" {
+		add("video codec", claims.VideoCodec, actual.VideoCodec, checkStatus(claims.VideoCodec == actual.VideoCodec), "")
+	}
+	if claims.VideoEncoder != "" {
+		switch {
+		case actual.VideoEncoder != "":
+			add("encoder", claims.VideoEncoder, actual.VideoEncoder, checkStatus(claims.VideoEncoder == actual.VideoEncoder), "")
+		case claims.VideoCodec != actual.VideoCodec:
+			add("encoder", claims.VideoEncoder, "", CheckFail, claims.VideoEncoder+" produces "+claims.VideoCodec+", video is "+actual.VideoCodec)
+		default:
+			add("encoder", claims.VideoEncoder, "", CheckUnverified, "no encoder signature in the video stream")
+		}
+	}
+	if claims.BitDepth != 0 {
+		add("bit depth", strconv.Itoa(claims.BitDepth), strconv.Itoa(actual.BitDepth), checkStatus(claims.BitDepth == actual.BitDepth), "")
+	}
+	hdr := actual.HDRString()
+	for _, claim := range claims.HDR {
+		var ok bool
+		reason := ""
+		switch claim {
+		case "HDR":
+			ok = slices.Contains(actual.HDR, "HDR10") || slices.Contains(actual.HDR, "HDR10+")
+			if !ok {
+				reason = "no static HDR metadata (mastering display or HDR10+)"
+			}
+		case "HDR10":
+			ok = slices.Contains(actual.HDR, "HDR10") || slices.Contains(actual.HDR, "HDR10+")
+		default:
+			ok = slices.Contains(actual.HDR, claim)
+		}
+		if claim == "DV" && ok && actual.DolbyVisionProfile != "" {
+			reason = "profile " + actual.DolbyVisionProfile
+		}
+		add("hdr", claim, hdr, checkStatus(ok), reason)
+	}
+	if len(claims.Audio) > 0 {
+		checks = append(checks, checkReleaseAudio(claims.Audio[0], actual.Audio)...)
+	}
+	for _, language := range claims.AudioLanguages {
+		ok := slices.ContainsFunc(actual.AudioLanguages, func(code string) bool {
+			primary, _, _ := strings.Cut(code, "-")
+			return primary == language
+		})
+		add("language", formatLanguage(language), strings.Join(actual.AudioLanguages, ", "), checkStatus(ok), "")
+	}
+	return checks
+}
+
+// checkReleaseAudio checks the audio claims against the first stream with the claimed
+// codec, or against all streams when none has it.
+func checkReleaseAudio(claim ReleaseAudio, streams []ReleaseAudio) []NameCheck {
+	var all []string
+	for _, stream := range streams {
+		all = append(all, stream.String())
+	}
+	actual := strings.Join(all, ", ")
+	matches := func(stream ReleaseAudio) bool {
+		if claim.Codec == "DTS-HD" {
+			return stream.Codec == "DTS-HD MA" || stream.Codec == "DTS-HD HRA"
+		}
+		return stream.Codec == claim.Codec
+	}
+	var checks []NameCheck
+	index := slices.IndexFunc(streams, matches)
+	if claim.Codec != "" {
+		status, reason := CheckPass, ""
+		if index < 0 {
+			status = CheckFail
+			if strings.HasPrefix(claim.Codec, "DTS-HD") && slices.ContainsFunc(streams, func(s ReleaseAudio) bool { return s.Codec == "DTS" }) {
+				reason = "core-only DTS, no DTS-HD extension"
+			}
+		}
+		checks = append(checks, NameCheck{Attribute: "audio codec", Claimed: claim.Codec, Actual: actual, Status: status, Reason: reason})
+	}
+	candidates := streams
+	if index >= 0 {
+		candidates = streams[index : index+1]
+	}
+	if claim.Atmos {
+		ok := slices.ContainsFunc(candidates, func(s ReleaseAudio) bool { return s.Atmos })
+		checks = append(checks, NameCheck{Attribute: "atmos", Claimed: "Atmos", Actual: actual, Status: checkStatus(ok)})
+	}
+	if claim.DTSX {
+		ok := slices.ContainsFunc(candidates, func(s ReleaseAudio) bool { return s.DTSX })
+		checks = append(checks, NameCheck{Attribute: "dts:x", Claimed: "DTS:X", Actual: actual, Status: checkStatus(ok)})
+	}
+	if claim.Channels != "" {
+		ok := slices.ContainsFunc(candidates, func(s ReleaseAudio) bool { return s.Channels == claim.Channels })
+		checks = append(checks, NameCheck{Attribute: "channels", Claimed: claim.Channels, Actual: actual, Status: checkStatus(ok)})
+	}
+	return checks
+}
+
+func checkStatus(ok bool) CheckStatus {
+	if ok {
+		return CheckPass
+	}
+	return CheckFail
+}
@@ -0,0 +1,85 @@
+package mediainfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseReleaseName(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		want ReleaseAttributes
+	}{
+		{
+			"/downloads/Movie.2023.2160p.UHD.BluRay.DV.HDR10.TrueHD.Atmos.7.1.x265-GROUP.mkv",
+			ReleaseAttributes{
+				Resolution: "2160p", VideoCodec: "HEVC", VideoEncoder: "x265", HDR: []string{"DV", "HDR10"},
+				Audio: []ReleaseAudio{{Codec: "TrueHD", Atmos: true, Channels: "7.1"}},
+			},
+		},
+		{
+			"Show.S01E01.1080p.WEB-DL.DDP5.1.Atmos.H.264-GROUP",
+			ReleaseAttributes{Resolution: "1080p", VideoCodec: "H.264", Audio: []ReleaseAudio{{Codec: "DDP", Atmos: true, Channels: "5.1"}}},
+		},
+		{
+			"Movie 2019 FRENCH 1080p BluRay DTS-HD MA 5.1 HDR10+ 10bit HEVC",
+			ReleaseAttributes{
+				Resolution: "1080p", VideoCodec: "HEVC", BitDepth: 10, HDR: []string{"HDR10+"},
+				Audio: []ReleaseAudio{{Codec: "DTS-HD MA", Channels: "5.1"}}, AudioLanguages: []string{"fr"},
+			},
+		},
+		{
+			"Movie.2020.720p.HDR.DTS-X.AVC",
+			ReleaseAttributes{Resolution: "720p", VideoCodec: "H.264", HDR: []string{"HDR"}, Audio: []ReleaseAudio{{Codec: "DTS-HD MA", DTSX: true}}},
+		},
+		{"Movie.2018.DVDRip.XviD.AC3-GROUP.avi", ReleaseAttributes{VideoCodec: "XviD", Audio: []ReleaseAudio{{Codec: "DD"}}}},
+		{"Foo.1080p.UHD.BluRay.x264-G", ReleaseAttributes{Resolution: "1080p", VideoCodec: "H.264", VideoEncoder: "x264"}},
+		{"Movie.UHD.BluRay.x265-G", ReleaseAttributes{Resolution: "2160p", VideoCodec: "HEVC", VideoEncoder: "x265"}},
+		{
+			"The.French.Dispatch.2021.1080p.BluRay.DD5.1.x264-G",
+			ReleaseAttributes{Resolution: "1080p", VideoCodec: "H.264", VideoEncoder: "x264", Audio: []ReleaseAudio{{Codec: "DD", Channels: "5.1"}}},
+		},
+		{"Show.S01.FRENCH.1080p.WEB.H264-G", ReleaseAttributes{Resolution: "1080p", VideoCodec: "H.264", AudioLanguages: []string{"fr"}}},
+		{"Movie.GERMAN.DL.BluRay.x264-G", ReleaseAttributes{VideoCodec: "H.264", VideoEncoder: "x264", AudioLanguages: []string{"de"}}},
+		{"The.French.Connection.BluRay.x264-G", ReleaseAttributes{VideoCodec: "H.264", VideoEncoder: "x264"}},
+		{"holiday video", ReleaseAttributes{}},
+	} {
+		if got := ParseReleaseName(tc.name); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestCheckReleaseName(t *testing.T) {
+	report, err := AnalyzeFile("samples/sample.mkv")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	checks := CheckReleaseName("Sample.2024.1080p.BluRay.HDR.DTS-HD.MA.5.1.x264-GROUP", report)
+	want := []struct {
+		attribute, claimed string
+		status             CheckStatus
+	}{
+		{"resolution", "1080p", CheckFail},
+		{"video codec", "H.264", CheckPass},
+		{"encoder", "x264", CheckPass},
+		{"hdr", "HDR", CheckFail},
+		{"audio codec", "DTS-HD MA", CheckFail},
+		{"channels", "5.1", CheckFail},
+	}
+	if len(checks) != len(want) {
+		t.Fatalf("got %d checks, want %d: %+v", len(checks), len(want), checks)
+	}
+	for i, check := range checks {
+		if check.Attribute != want[i].attribute || check.Claimed != want[i].claimed || check.Status != want[i].status {
+			t.Errorf("check %d: got %+v, want %+v", i, check, want[i])
+		}
+	}
+	if checks[0].Actual != "360p" || checks[0].Reason != "video is 640x360" {
+		t.Errorf("resolution check: %+v", checks[0])
+	}
+
+	if checks := CheckReleaseName("sample.mkv", report); len(checks) != 0 {
+		t.Errorf("name without claims: %+v", checks)
+	}
+}
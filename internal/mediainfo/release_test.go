@@ -0,0 +1,89 @@
+package mediainfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractReleaseAttributes(t *testing.T) {
+	report, err := AnalyzeFile("samples/sample.mkv")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	got := ExtractReleaseAttributes(report)
+	want := ReleaseAttributes{
+		Resolution:   "360p",
+		Width:        640,
+		Height:       360,
+		VideoCodec:   "H.264",
+		VideoEncoder: "x264",
+		BitDepth:     8,
+		Audio:        []ReleaseAudio{{Codec: "AAC", Channels: "1.0"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("attributes:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestReleaseResolution(t *testing.T) {
+	for _, tc := range []struct {
+		width, height int
+		scan          string
+		want          string
+	}{
+		{3840, 2160, "Progressive", "2160p"},
+		{3840, 1600, "Progressive", "2160p"},
+		{1920, 800, "Progressive", "1080p"},
+		{1920, 1080, "Interlaced", "1080i"},
+		{1280, 536, "", "720p"},
+		{720, 576, "MBAFF", "576i"},
+		{720, 480, "Progressive", "480p"},
+		{640, 360, "Progressive", "360p"},
+		{0, 0, "", ""},
+	} {
+		if got := releaseResolution(tc.width, tc.height, tc.scan); got != tc.want {
+			t.Errorf("%dx%d %s: got %q, want %q", tc.width, tc.height, tc.scan, got, tc.want)
+		}
+	}
+}
+
+func TestReleaseAudioAndHDR(t *testing.T) {
+	for _, tc := range []struct {
+		format, profile, commercial, features string
+		want                                  string
+	}{
+		{"MLP FBA", "", "Dolby TrueHD with Dolby Atmos", "16-ch", "TrueHD Atmos"},
+		{"E-AC-3", "", "Dolby Digital Plus with Dolby Atmos", "JOC", "DDP Atmos"},
+		{"E-AC-3", "", "Dolby Digital Plus", "", "DDP"},
+		{"AC-3", "", "Dolby Digital", "", "DD"},
+		{"DTS", "", "DTS-HD Master Audio", "XLL X", "DTS-HD MA DTS:X"},
+		{"DTS", "", "DTS-HD Master Audio", "XLL", "DTS-HD MA"},
+		{"DTS", "", "DTS-HD High Resolution Audio", "XBR", "DTS-HD HRA"},
+		{"DTS", "", "", "", "DTS"},
+		{"PCM", "", "", "", "LPCM"},
+		{"MPEG Audio", "Layer 2", "", "", "MP2"},
+	} {
+		codec, atmos, dtsx := releaseAudioCodec(tc.format, tc.profile, tc.commercial, tc.features, releaseNames)
+		if got := (ReleaseAudio{Codec: codec, Atmos: atmos, DTSX: dtsx}).String(); got != tc.want {
+			t.Errorf("%s %q: got %q, want %q", tc.format, tc.features, got, tc.want)
+		}
+	}
+
+	for _, tc := range []struct {
+		formats, transfer string
+		want              string
+	}{
+		{"Dolby Vision / SMPTE ST 2086 / HDR10 / HDR10", "PQ", "DV HDR10"},
+		{"Dolby Vision / SMPTE ST 2094 App 4 / HDR10 / HDR10+ Profile B compatible", "PQ", "DV HDR10+"},
+		{"SMPTE ST 2086 / HDR10", "PQ", "HDR10"},
+		{"Dolby Vision / ", "PQ", "DV"},
+		{" / ", "HLG", "HLG"},
+		{" / ", "PQ", "PQ"},
+		{" / ", "BT.709", ""},
+	} {
+		attrs := ReleaseAttributes{HDR: releaseHDR(tc.formats, tc.transfer, releaseNames)}
+		if got := attrs.HDRString(); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.formats, got, tc.want)
+		}
+	}
+}
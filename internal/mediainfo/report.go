@@ -23,6 +23,11 @@ type Stream struct {
 	JSONRaw             map[string]string
 	JSONSkipStreamOrder bool
 	JSONSkipComputed    bool
+	// Typed views of the stream, set by the analyzer for the matching Kind.
+	General             *GeneralInfo
+	Video               *VideoInfo
+	Audio               *AudioInfo
+	Text                *TextInfo
 	eac3Dec3            eac3Dec3Info
 	nalLengthSize       int
 	mkvHeaderStripBytes []byte
@@ -34,4 +39,9 @@ type Report struct {
 	Ref     string
 	General Stream
 	Streams []Stream
+	// Err is set on batch entries that could not be analyzed; such reports only carry Ref.
+	Err error
+	// fromReader marks reports built by AnalyzeReader: Ref is only a name, so renderers
+	// must not stat it for file size or timestamps.
+	fromReader bool
 }
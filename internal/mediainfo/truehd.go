@@ -0,0 +1,85 @@
+package mediainfo
+
+import "bytes"
+
+// trueHDMajorSync holds the stream parameters of a Dolby TrueHD (MLP FBA) major sync.
+type trueHDMajorSync struct {
+	sampleRate  float64
+	spf         int
+	channels    uint64
+	layout      string
+	vbr         bool
+	peakBitRate int64
+	substreams  int
+	atmos       bool
+}
+
+// trueHDChannelAssignment lists the speakers of the bits of the 6ch and 8ch presentation
+// channel assignments, lowest bit first.
+var trueHDChannelAssignment = [...]struct {
+	channels uint64
+	layout   string
+}{
+	{2, "L R"}, {1, "C"}, {1, "LFE"}, {2, "Ls Rs"}, {2, "Tfl Tfr"}, {2, "Lsc Rsc"}, {2, "Lb Rb"},
+	{1, "Cb"}, {1, "Tc"}, {2, "Lsd Rsd"}, {2, "Lw Rw"}, {1, "Tfc"}, {1, "LFE2"},
+}
+
+// parseTrueHDMajorSync reads the first major sync (format_sync 0xF8726FBA) of buf.
+func parseTrueHDMajorSync(buf []byte) (trueHDMajorSync, bool) {
+	idx := bytes.Index(buf, []byte{0xF8, 0x72, 0x6F, 0xBA})
+	if idx < 0 || idx+21 > len(buf) {
+		return trueHDMajorSync{}, false
+	}
+	br := newBitReader(buf[idx+4:])
+	rate := br.readBitsValue(4) // audio_sampling_frequency
+	if rate&7 > 2 || rate == 0x0F {
+		return trueHDMajorSync{}, false
+	}
+	var sync trueHDMajorSync
+	base := 48000.0
+	if rate&8 != 0 {
+		base = 44100
+	}
+	sync.sampleRate = base * float64(int(1)<<(rate&7))
+	sync.spf = 40 << (rate & 7)
+	br.readBitsValue(4) // 6ch/8ch multichannel type, reserved
+	br.readBitsValue(4) // 2ch and 6ch presentation channel modifiers
+	assign6 := br.readBitsValue(5)
+	br.readBitsValue(2) // 8ch presentation channel modifier
+	assign8 := br.readBitsValue(13)
+	br.readBitsValue(48) // signature, flags, reserved
+	sync.vbr = br.readBitsValue(1) == 1
+	peak := int64(br.readBitsValue(15))
+	sync.peakBitRate = (peak*int64(sync.sampleRate) + 8) >> 4
+	sync.substreams = int(br.readBitsValue(4))
+
+	assign := assign8
+	if assign == 0 {
+		assign = assign6
+	}
+	var layout []byte
+	for bit, speakers := range trueHDChannelAssignment {
+		if assign&(1<<bit) == 0 {
+			continue
+		}
+		sync.channels += speakers.channels
+		if len(layout) > 0 {
+			layout = append(layout, ' ')
+		}
+		layout = append(layout, speakers.layout...)
+	}
+	sync.layout = string(layout)
+	// The fourth substream carries the 16-channel presentation of Dolby Atmos.
+	sync.atmos = sync.substreams == 4
+	return sync, sync.channels > 0
+}
+
+// trueHDAccessUnitSpan returns the size of the access unit at the start of buf: the
+// 12-bit access_unit_length counts 16-bit words.
+func trueHDAccessUnitSpan(buf []byte) (int, bool) {
+	if len(buf) < 4 {
+		return 0, false
+	}
+	size := (int(buf[0]&0x0F)<<8 | int(buf[1])) * 2
+	return size, size >= 4
+}
@@ -0,0 +1,295 @@
+package mediainfo
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GeneralInfo is the typed view of a General stream. Values match the JSON output; zero
+// values mean the field is not reported.
+type GeneralInfo struct {
+	Format             string
+	FormatProfile      string
+	FileSize           int64
+	Duration           time.Duration
+	OverallBitRate     int64
+	FrameRate          big.Rat
+	FrameCount         int64
+	Title              string
+	Movie              string
+	EncodedApplication string
+	EncodedLibrary     string
+	VideoCount         int
+	AudioCount         int
+	TextCount          int
+	ImageCount         int
+	MenuCount          int
+	// Fragmented is set for fragmented MP4 and CMAF files.
+	Fragmented bool
+}
+
+// VideoInfo is the typed view of a Video stream.
+type VideoInfo struct {
+	ID                      string
+	Format                  string
+	FormatProfile           string
+	FormatLevel             string
+	CodecID                 string
+	Width                   int
+	Height                  int
+	PixelAspectRatio        float64
+	DisplayAspectRatio      float64
+	FrameRateMode           string
+	FrameRate               big.Rat
+	FrameCount              int64
+	Duration                time.Duration
+	BitRate                 int64
+	BitDepth                int
+	ColorSpace              string
+	ChromaSubsampling       string
+	ScanType                string
+	HDRFormat               string
+	HDRFormatCompatibility  string
+	ColorRange              string
+	ColorPrimaries          string
+	TransferCharacteristics string
+	MatrixCoefficients      string
+	StreamSize              int64
+	Language                string
+	Title                   string
+	Default                 bool
+	Forced                  bool
+}
+
+// AudioInfo is the typed view of an Audio stream.
+type AudioInfo struct {
+	ID                       string
+	Format                   string
+	FormatCommercial         string
+	FormatProfile            string
+	FormatAdditionalFeatures string
+	CodecID                  string
+	Duration                 time.Duration
+	BitRateMode              string
+	BitRate                  int64
+	Channels                 int
+	ChannelLayout            string
+	SamplingRate             int
+	BitDepth                 int
+	CompressionMode          string
+	Delay                    time.Duration
+	StreamSize               int64
+	Language                 string
+	Title                    string
+	Default                  bool
+	Forced                   bool
+}
+
+// TextInfo is the typed view of a Text stream.
+type TextInfo struct {
+	ID           string
+	Format       string
+	CodecID      string
+	Duration     time.Duration
+	ElementCount int64
+	Language     string
+	Title        string
+	Default      bool
+	Forced       bool
+}
+
+// fillTypedInfo sets the typed views of report from the same JSON fields RenderJSON emits,
+// so both always agree.
+func fillTypedInfo(report *Report) {
+	general := typedFieldsFromJSON(buildJSONGeneralFields(*report))
+	report.General.General = general.generalInfo()
+	containerFormat := findField(report.General.Fields, "Format")
+	for i := range report.Streams {
+		stream := &report.Streams[i]
+		fields := typedFieldsFromJSON(buildJSONStreamFields(*stream, 0, 0, containerFormat))
+		switch stream.Kind {
+		case StreamVideo:
+			stream.Video = fields.videoInfo()
+		case StreamAudio:
+			stream.Audio = fields.audioInfo()
+		case StreamText:
+			stream.Text = fields.textInfo()
+		}
+	}
+}
+
+type typedFields map[string]string
+
+func (f typedFields) generalInfo() *GeneralInfo {
+	info := &GeneralInfo{
+		Format:             f["Format"],
+		FormatProfile:      f["Format_Profile"],
+		FileSize:           f.int64("FileSize"),
+		Duration:           f.duration("Duration"),
+		OverallBitRate:     f.int64("OverallBitRate"),
+		FrameCount:         f.int64("FrameCount"),
+		Title:              f["Title"],
+		Movie:              f["Movie"],
+		EncodedApplication: f["Encoded_Application"],
+		EncodedLibrary:     f["Encoded_Library"],
+		VideoCount:         f.int("VideoCount"),
+		AudioCount:         f.int("AudioCount"),
+		TextCount:          f.int("TextCount"),
+		ImageCount:         f.int("ImageCount"),
+		MenuCount:          f.int("MenuCount"),
+		Fragmented:         f["IsFragmented"] == "Yes",
+	}
+	f.frameRate(&info.FrameRate)
+	return info
+}
+
+func (f typedFields) videoInfo() *VideoInfo {
+	info := &VideoInfo{
+		ID:                      f["ID"],
+		Format:                  f["Format"],
+		FormatProfile:           f["Format_Profile"],
+		FormatLevel:             f["Format_Level"],
+		CodecID:                 f["CodecID"],
+		Width:                   f.int("Width"),
+		Height:                  f.int("Height"),
+		PixelAspectRatio:        f.float("PixelAspectRatio"),
+		DisplayAspectRatio:      f.float("DisplayAspectRatio"),
+		FrameRateMode:           f["FrameRate_Mode"],
+		FrameCount:              f.int64("FrameCount"),
+		Duration:                f.duration("Duration"),
+		BitRate:                 f.int64("BitRate"),
+		BitDepth:                f.int("BitDepth"),
+		ColorSpace:              f["ColorSpace"],
+		ChromaSubsampling:       f["ChromaSubsampling"],
+		ScanType:                f["ScanType"],
+		HDRFormat:               f["HDR_Format"],
+		HDRFormatCompatibility:  f["HDR_Format_Compatibility"],
+		ColorRange:              f["colour_range"],
+		ColorPrimaries:          f["colour_primaries"],
+		TransferCharacteristics: f["transfer_characteristics"],
+		MatrixCoefficients:      f["matrix_coefficients"],
+		StreamSize:              f.int64("StreamSize"),
+		Language:                f["Language"],
+		Title:                   f["Title"],
+		Default:                 f.bool("Default"),
+		Forced:                  f.bool("Forced"),
+	}
+	f.frameRate(&info.FrameRate)
+	return info
+}
+
+func (f typedFields) audioInfo() *AudioInfo {
+	return &AudioInfo{
+		ID:                       f["ID"],
+		Format:                   f["Format"],
+		FormatCommercial:         f["Format_Commercial_IfAny"],
+		FormatProfile:            f["Format_Profile"],
+		FormatAdditionalFeatures: f["Format_AdditionalFeatures"],
+		CodecID:                  f["CodecID"],
+		Duration:                 f.duration("Duration"),
+		BitRateMode:              f["BitRate_Mode"],
+		BitRate:                  f.int64("BitRate"),
+		Channels:                 f.int("Channels"),
+		ChannelLayout:            f["ChannelLayout"],
+		SamplingRate:             f.int("SamplingRate"),
+		BitDepth:                 f.int("BitDepth"),
+		CompressionMode:          f["Compression_Mode"],
+		Delay:                    f.duration("Delay"),
+		StreamSize:               f.int64("StreamSize"),
+		Language:                 f["Language"],
+		Title:                    f["Title"],
+		Default:                  f.bool("Default"),
+		Forced:                   f.bool("Forced"),
+	}
+}
+
+func (f typedFields) textInfo() *TextInfo {
+	return &TextInfo{
+		ID:           f["ID"],
+		Format:       f["Format"],
+		CodecID:      f["CodecID"],
+		Duration:     f.duration("Duration"),
+		ElementCount: f.int64("ElementCount"),
+		Language:     f["Language"],
+		Title:        f["Title"],
+		Default:      f.bool("Default"),
+		Forced:       f.bool("Forced"),
+	}
+}
+
+func typedFieldsFromJSON(fields []jsonKV) typedFields {
+	out := make(typedFields, len(fields))
+	for _, field := range fields {
+		if field.Raw {
+			continue
+		}
+		out[field.Key] = field.Val
+	}
+	return out
+}
+
+func (f typedFields) int(key string) int {
+	return int(f.int64(key))
+}
+
+func (f typedFields) int64(key string) int64 {
+	value := strings.TrimSpace(f[key])
+	if value == "" {
+		return 0
+	}
+	if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return parsed
+	}
+	// Some counts and rates carry a fraction (e.g. "48000.000").
+	if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+		return int64(math.Round(parsed))
+	}
+	return 0
+}
+
+func (f typedFields) float(key string) float64 {
+	parsed, _ := strconv.ParseFloat(strings.TrimSpace(f[key]), 64)
+	return parsed
+}
+
+func (f typedFields) bool(key string) bool {
+	return f[key] == "Yes"
+}
+
+// duration converts a JSON seconds value ("3.970000000") to a time.Duration.
+func (f typedFields) duration(key string) time.Duration {
+	value := strings.TrimSpace(f[key])
+	if value == "" {
+		return 0
+	}
+	seconds, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return 0
+	}
+	nanos := seconds.Mul(seconds, big.NewRat(int64(time.Second), 1))
+	return time.Duration(math.Round(ratFloat(nanos)))
+}
+
+// frameRate sets dst to FrameRate_Num/FrameRate_Den when reported, else to the exact
+// decimal FrameRate value.
+func (f typedFields) frameRate(dst *big.Rat) {
+	num, errNum := strconv.ParseInt(f["FrameRate_Num"], 10, 64)
+	den, errDen := strconv.ParseInt(f["FrameRate_Den"], 10, 64)
+	if errNum == nil && errDen == nil && num > 0 && den > 0 {
+		dst.SetFrac64(num, den)
+		return
+	}
+	if value := strings.TrimSpace(f["FrameRate"]); value != "" {
+		if _, ok := dst.SetString(value); !ok {
+			dst.SetInt64(0)
+		}
+	}
+}
+
+func ratFloat(value *big.Rat) float64 {
+	out, _ := value.Float64()
+	return out
+}
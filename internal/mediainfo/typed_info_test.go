@@ -0,0 +1,61 @@
+package mediainfo
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestTypedInfoMatchesJSON(t *testing.T) {
+	report, err := AnalyzeFile("samples/sample.mkv")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	general := report.General.General
+	if general == nil {
+		t.Fatalf("missing GeneralInfo")
+	}
+	if general.Format != "Matroska" || general.FileSize != 275012 || general.Duration != 4021*time.Millisecond {
+		t.Fatalf("general=%+v", general)
+	}
+	if general.VideoCount != 1 || general.AudioCount != 1 {
+		t.Fatalf("counts video=%d audio=%d", general.VideoCount, general.AudioCount)
+	}
+
+	var video *VideoInfo
+	var audio *AudioInfo
+	for _, stream := range report.Streams {
+		if stream.Video != nil {
+			video = stream.Video
+		}
+		if stream.Audio != nil {
+			audio = stream.Audio
+		}
+	}
+	if video == nil || audio == nil {
+		t.Fatalf("missing typed streams: video=%v audio=%v", video, audio)
+	}
+	if video.Width != 640 || video.Height != 360 || video.BitDepth != 8 {
+		t.Fatalf("video=%+v", video)
+	}
+	if video.FrameRate.Cmp(big.NewRat(30000, 1001)) != 0 {
+		t.Fatalf("FrameRate=%s, want 30000/1001", video.FrameRate.String())
+	}
+	if video.Duration != 3970*time.Millisecond || video.Format != "AVC" || video.ScanType != "Progressive" {
+		t.Fatalf("video=%+v", video)
+	}
+	if audio.Channels != 1 || audio.SamplingRate != 48000 || audio.Format != "AAC" || audio.Duration != 4021*time.Millisecond {
+		t.Fatalf("audio=%+v", audio)
+	}
+}
+
+func TestTypedFieldsFrameRateFallsBackToDecimal(t *testing.T) {
+	var rate big.Rat
+	typedFields{"FrameRate": "25.000"}.frameRate(&rate)
+	if rate.Cmp(big.NewRat(25, 1)) != 0 {
+		t.Fatalf("rate=%s", rate.String())
+	}
+	if got := (typedFields{"Delay": "-0.042"}).duration("Delay"); got != -42*time.Millisecond {
+		t.Fatalf("delay=%s", got)
+	}
+}
@@ -0,0 +1,275 @@
+package mediainfo
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+// VVC NAL unit types (H.266 Table 5).
+const (
+	vvcNALLastVCL   = 11
+	vvcNALOPI       = 12
+	vvcNALDCI       = 13
+	vvcNALVPS       = 14
+	vvcNALSPS       = 15
+	vvcNALPH        = 19
+	vvcNALAUD       = 20
+	vvcNALPrefixSEI = 23
+	vvcNALSuffixSEI = 24
+)
+
+// vvcNALHeader returns nal_unit_type and nuh_layer_id of a two-byte VVC NAL unit header.
+func vvcNALHeader(nal []byte) (nalType byte, layerID byte, ok bool) {
+	if len(nal) < 2 || nal[0]&0xC0 != 0 || nal[1]&0x07 == 0 {
+		return 0, 0, false
+	}
+	return nal[1] >> 3, nal[0] & 0x3F, true
+}
+
+func vvcProfileName(idc byte) string {
+	switch idc {
+	case 1:
+		return "Main 10"
+	case 2:
+		return "Main 12"
+	case 10:
+		return "Main 12 Intra"
+	case 17:
+		return "Multilayer Main 10"
+	case 33:
+		return "Main 10 4:4:4"
+	case 34:
+		return "Main 12 4:4:4"
+	case 35:
+		return "Main 16 4:4:4"
+	case 42:
+		return "Main 12 4:4:4 Intra"
+	case 43:
+		return "Main 16 4:4:4 Intra"
+	case 49:
+		return "Multilayer Main 10 4:4:4"
+	case 65:
+		return "Main 10 Still Picture"
+	case 66:
+		return "Main 12 Still Picture"
+	case 81:
+		return "Multilayer Main 10 Still Picture"
+	case 97:
+		return "Main 10 4:4:4 Still Picture"
+	case 98:
+		return "Main 12 4:4:4 Still Picture"
+	case 99:
+		return "Main 16 4:4:4 Still Picture"
+	case 113:
+		return "Multilayer Main 10 4:4:4 Still Picture"
+	default:
+		return ""
+	}
+}
+
+// vvcLevelName maps general_level_idc (16 * major + 3 * minor) to its level.
+func vvcLevelName(idc byte) string {
+	if idc == 0 {
+		return ""
+	}
+	major, minor := idc/16, idc%16/3
+	if minor == 0 {
+		return strconv.Itoa(int(major))
+	}
+	return fmt.Sprintf("%d.%d", major, minor)
+}
+
+// parseVVCConfig reads a VvcDecoderConfigurationRecord (vvcC, Matroska V_MPEGI/ISO/VVC
+// CodecPrivate) and returns the stream info with the NAL length size. The SPS in the
+// arrays wins over the fixed fields; SEI NAL units there are added to hdr, which may be
+// nil.
+func parseVVCConfig(payload []byte, hdr *hevcHDRInfo) (h264SPSInfo, int, bool) {
+	// The vvcC box is a FullBox: skip its version and flags when present.
+	if len(payload) > 4 && payload[0]&0xF8 != 0xF8 && payload[4]&0xF8 == 0xF8 {
+		payload = payload[4:]
+	}
+	if len(payload) < 2 || payload[0]&0xF8 != 0xF8 {
+		return h264SPSInfo{}, 0, false
+	}
+	br := newBitReader(payload)
+	_ = br.readBitsValue(5) // reserved
+	nalLengthSize := int(br.readBitsValue(2)) + 1
+	var info h264SPSInfo
+	if br.readBitsValue(1) == 1 { // ptl_present_flag
+		_ = br.readBitsValue(9) // ols_idx
+		numSublayers := int(br.readBitsValue(3))
+		_ = br.readBitsValue(2) // constant_frame_rate
+		info.ChromaFormat = hevcChromaFormatName(byte(br.readBitsValue(2)))
+		info.BitDepth = int(br.readBitsValue(3)) + 8
+		_ = br.readBitsValue(5) // reserved
+
+		// VvcPTLRecord
+		_ = br.readBitsValue(2) // reserved
+		constraintBytes := int(br.readBitsValue(6))
+		info.ProfileID = byte(br.readBitsValue(7))
+		info.HEVCTier = hevcTierName(byte(br.readBitsValue(1)))
+		info.LevelID = byte(br.readBitsValue(8))
+		for range constraintBytes { // ptl_frame_only_constraint_flag, ptl_multilayer_enabled_flag, general_constraint_info
+			_ = br.readBitsValue(8)
+		}
+		if numSublayers > 1 {
+			levelPresent := br.readBitsValue(uint8(numSublayers - 1))
+			_ = br.readBitsValue(uint8(9 - numSublayers)) // ptl_reserved_zero_bit
+			for i := 0; i < numSublayers-1; i++ {
+				if levelPresent>>i&1 == 1 {
+					_ = br.readBitsValue(8) // sublayer_level_idc
+				}
+			}
+		}
+		subProfiles := int(br.readBitsValue(8))
+		for range subProfiles {
+			_ = br.readBitsValue(32) // general_sub_profile_idc
+		}
+		info.Width = br.readBitsValue(16)
+		info.Height = br.readBitsValue(16)
+		avgFrameRate := br.readBitsValue(16)
+		if avgFrameRate == ^uint64(0) {
+			return h264SPSInfo{}, 0, false
+		}
+		info.FrameRate = float64(avgFrameRate) / 256
+	}
+
+	offset := br.pos
+	if offset >= len(payload) {
+		return info, nalLengthSize, info.ProfileID != 0
+	}
+	numArrays := int(payload[offset])
+	offset++
+	for range numArrays {
+		if offset >= len(payload) {
+			break
+		}
+		nalType := payload[offset] & 0x1F
+		offset++
+		numNALUs := 1
+		if nalType != vvcNALDCI && nalType != vvcNALOPI {
+			if offset+2 > len(payload) {
+				break
+			}
+			numNALUs = int(binary.BigEndian.Uint16(payload[offset : offset+2]))
+			offset += 2
+		}
+		for range numNALUs {
+			if offset+2 > len(payload) {
+				break
+			}
+			nalLen := int(binary.BigEndian.Uint16(payload[offset : offset+2]))
+			offset += 2
+			if offset+nalLen > len(payload) {
+				break
+			}
+			nal := payload[offset : offset+nalLen]
+			offset += nalLen
+			switch nalType {
+			case vvcNALSPS:
+				if sps, ok := parseVVCSPS(nal); ok {
+					if sps.FrameRate == 0 {
+						sps.FrameRate = info.FrameRate
+					}
+					info = sps
+				}
+			case vvcNALPrefixSEI, vvcNALSuffixSEI:
+				if hdr != nil {
+					parseVVCNAL(nal, hdr)
+				}
+			}
+		}
+	}
+	return info, nalLengthSize, info.ProfileID != 0 || info.Width > 0
+}
+
+// parseVVCNAL reads the HDR metadata of an SEI NAL unit. VVC SEI messages share the HEVC
+// syntax and payload types (H.274).
+func parseVVCNAL(nal []byte, hdr *hevcHDRInfo) {
+	nalType, _, ok := vvcNALHeader(nal)
+	if !ok || nalType != vvcNALPrefixSEI && nalType != vvcNALSuffixSEI {
+		return
+	}
+	parseHEVCSEI(nalToRBSPWithHeader(nal, 2), hdr)
+}
+
+// parseVVCSampleHDR reads the SEI NAL units of a sample, length-prefixed when
+// nalLengthSize is set and Annex B otherwise.
+func parseVVCSampleHDR(sample []byte, nalLengthSize int, hdr *hevcHDRInfo) {
+	if nalLengthSize > 0 && nalLengthSize <= 4 {
+		for offset := 0; offset+nalLengthSize <= len(sample) && !hdr.complete(); {
+			nalSize := readNALSize(sample[offset:], nalLengthSize)
+			offset += nalLengthSize
+			if nalSize <= 0 || offset+nalSize > len(sample) {
+				return
+			}
+			parseVVCNAL(sample[offset:offset+nalSize], hdr)
+			offset += nalSize
+		}
+		return
+	}
+	scanAnnexBNALs(sample, func(nal []byte) bool {
+		parseVVCNAL(nal, hdr)
+		return !hdr.complete()
+	})
+}
+
+// parseVVCAnnexBMeta reads the first SPS of an Annex B buffer and adds its HDR SEI
+// messages to hdr.
+func parseVVCAnnexBMeta(data []byte, hdr *hevcHDRInfo) (h264SPSInfo, bool) {
+	var (
+		sps    h264SPSInfo
+		hasSPS bool
+	)
+	scanAnnexBNALs(data, func(nal []byte) bool {
+		nalType, _, ok := vvcNALHeader(nal)
+		if !ok {
+			return true
+		}
+		switch nalType {
+		case vvcNALSPS:
+			if !hasSPS {
+				sps, hasSPS = parseVVCSPS(nal)
+			}
+		case vvcNALPrefixSEI, vvcNALSuffixSEI:
+			parseVVCNAL(nal, hdr)
+		}
+		return true
+	})
+	return sps, hasSPS
+}
+
+// vvcFormatFields returns the profile, tier, chroma and bit depth fields of a VVC stream.
+func vvcFormatFields(sps h264SPSInfo) []Field {
+	var fields []Field
+	if profile := vvcProfileName(sps.ProfileID); profile != "" {
+		if level := vvcLevelName(sps.LevelID); level != "" {
+			profile += "@L" + level
+		}
+		fields = append(fields, Field{Name: "Format profile", Value: profile})
+	}
+	if sps.HEVCTier == "High" {
+		fields = append(fields, Field{Name: "Format tier", Value: sps.HEVCTier})
+	}
+	if sps.ChromaFormat != "" {
+		fields = append(fields, Field{Name: "Chroma subsampling", Value: sps.ChromaFormat})
+	}
+	if sps.BitDepth > 0 {
+		fields = append(fields, Field{Name: "Bit depth", Value: formatBitDepth(uint8(sps.BitDepth))})
+	}
+	return fields
+}
+
+// buildVVCFields returns the video fields of a VVC stream: format, color and HDR metadata.
+// The color and HDR JSON keys go to jsonExtras.
+func buildVVCFields(sps h264SPSInfo, hdr hevcHDRInfo, jsonExtras map[string]string) []Field {
+	fields := vvcFormatFields(sps)
+	space := "YUV"
+	if sps.ChromaFormat == "4:0:0" {
+		space = "Y"
+	}
+	fields = append(fields, Field{Name: "Color space", Value: space})
+	fields = appendColorInfoFields(fields, sps, jsonExtras)
+	return appendHDRInfoFields(fields, hdr, jsonExtras)
+}
@@ -0,0 +1,62 @@
+package mediainfo
+
+import "io"
+
+// isVVCAnnexB reports whether buf starts with a start code and the NAL unit header of a
+// VVC parameter set, access unit delimiter or SEI.
+func isVVCAnnexB(buf []byte) bool {
+	start, startLen := findAnnexBStartCode(buf, 0)
+	if start != 0 {
+		return false
+	}
+	nalType, _, ok := vvcNALHeader(buf[startLen:])
+	if !ok {
+		return false
+	}
+	switch nalType {
+	case vvcNALOPI, vvcNALDCI, vvcNALVPS, vvcNALSPS, vvcNALAUD, vvcNALPrefixSEI:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseVVC reads raw H.266 Annex B streams (.266). The SPS and HDR SEI messages come from
+// the head of the file; pictures are counted from picture headers and from slices that
+// carry their own.
+func ParseVVC(r io.ReaderAt, size int64, parseSpeed float64) (ContainerInfo, []Stream, bool) {
+	head, ok := readRawHead(r, size)
+	if !ok {
+		return ContainerInfo{}, nil, false
+	}
+	var hdr hevcHDRInfo
+	sps, ok := parseVVCAnnexBMeta(head, &hdr)
+	if !ok {
+		return ContainerInfo{}, nil, false
+	}
+
+	scanSize := rawScanSize(size, parseSpeed)
+	frames := extrapolateRawCount(countAnnexBPictures(r, scanSize, vvcPictureCount), scanSize, size)
+
+	fields := []Field{
+		{Name: "Format", Value: "VVC"},
+		{Name: "Format/Info", Value: "Versatile Video Coding"},
+	}
+	jsonExtras := map[string]string{}
+	fields = append(fields, buildVVCFields(sps, hdr, jsonExtras)...)
+	fields = appendVideoStreamStats(fields, jsonExtras, frames, sps.FrameRate, size, size, sps.Width, sps.Height)
+	return rawVideoResult(fields, jsonExtras, frames, sps.FrameRate)
+}
+
+// vvcPictureCount returns 1 when nal starts a base layer picture: a picture header NAL
+// unit, or a slice with sh_picture_header_in_slice_header_flag set.
+func vvcPictureCount(nal []byte) int64 {
+	nalType, layerID, ok := vvcNALHeader(nal)
+	if !ok || layerID != 0 || len(nal) < 3 {
+		return 0
+	}
+	if nalType == vvcNALPH || nalType <= vvcNALLastVCL && nal[2]&0x80 != 0 {
+		return 1
+	}
+	return 0
+}
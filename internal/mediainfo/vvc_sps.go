@@ -0,0 +1,486 @@
+package mediainfo
+
+import "math/bits"
+
+// parseVVCSPS parses a VVC seq_parameter_set_rbsp (H.266 7.3.2.4). The profile, chroma,
+// bit depth and picture size come first; the rest of the SPS is walked to reach the
+// timing HRD parameters and the VUI, and a stream that desyncs before them keeps the
+// fields read so far.
+func parseVVCSPS(nal []byte) (h264SPSInfo, bool) {
+	rbsp := nalToRBSPWithHeader(nal, 2)
+	if len(rbsp) == 0 {
+		return h264SPSInfo{}, false
+	}
+	br := newBitReader(rbsp)
+	var info h264SPSInfo
+
+	_ = br.readBitsValue(4) // sps_seq_parameter_set_id
+	vpsID := br.readBitsValue(4)
+	maxSublayersMinus1 := int(br.readBitsValue(3))
+	chromaFormatIDC := br.readBitsValue(2)
+	ctbLog2Size := int(br.readBitsValue(2)) + 5
+	ptlDPBHRD := br.readBitsValue(1) == 1
+	if maxSublayersMinus1 > 6 {
+		return h264SPSInfo{}, false
+	}
+	if ptlDPBHRD && !readVVCProfileTierLevel(br, maxSublayersMinus1, &info) {
+		return h264SPSInfo{}, false
+	}
+	_ = br.readBitsValue(1)       // sps_gdr_enabled_flag
+	if br.readBitsValue(1) == 1 { // sps_ref_pic_resampling_enabled_flag
+		_ = br.readBitsValue(1) // sps_res_change_in_clvs_allowed_flag
+	}
+	picWidth, _ := br.readUEWithOk()
+	picHeight, ok := br.readUEWithOk()
+	if !ok || picWidth <= 0 || picHeight <= 0 {
+		return h264SPSInfo{}, false
+	}
+	var confWin [4]int // left, right, top, bottom
+	if br.readBitsValue(1) == 1 {
+		for i := range confWin {
+			confWin[i], _ = br.readUEWithOk()
+		}
+	}
+	if br.readBitsValue(1) == 1 && !skipVVCSubpicInfo(br, picWidth, picHeight, ctbLog2Size) {
+		return h264SPSInfo{}, false
+	}
+	bitDepthMinus8, ok := br.readUEWithOk()
+	if !ok {
+		return h264SPSInfo{}, false
+	}
+
+	subWidthC, subHeightC := 1, 1
+	switch chromaFormatIDC {
+	case 1:
+		subWidthC, subHeightC = 2, 2
+	case 2:
+		subWidthC = 2
+	}
+	width, height := picWidth, picHeight
+	if crop := (confWin[0] + confWin[1]) * subWidthC; width > crop {
+		width -= crop
+	}
+	if crop := (confWin[2] + confWin[3]) * subHeightC; height > crop {
+		height -= crop
+	}
+	info.ChromaFormat = hevcChromaFormatName(byte(chromaFormatIDC))
+	info.BitDepth = bitDepthMinus8 + 8
+	info.Width = uint64(width)
+	info.Height = uint64(height)
+	info.CodedWidth = uint64(picWidth)
+	info.CodedHeight = uint64(picHeight)
+
+	_ = br.readBitsValue(2) // sps_entropy_coding_sync_enabled_flag, sps_entry_point_offsets_present_flag
+	pocLSBBits := uint8(br.readBitsValue(4)) + 4
+	if br.readBitsValue(1) == 1 { // sps_poc_msb_cycle_flag
+		_, _ = br.readUEWithOk()
+	}
+	for range 2 { // sps_num_extra_ph_bytes, sps_num_extra_sh_bytes
+		extraBytes := br.readBitsValue(2)
+		if extraBytes > 3 {
+			return info, true
+		}
+		_ = br.readBitsValue(uint8(extraBytes * 8))
+	}
+	if ptlDPBHRD {
+		sublayerDPB := maxSublayersMinus1 > 0 && br.readBitsValue(1) == 1
+		first := maxSublayersMinus1
+		if sublayerDPB {
+			first = 0
+		}
+		for i := first; i <= maxSublayersMinus1; i++ {
+			for range 3 { // dpb_max_dec_pic_buffering_minus1, dpb_max_num_reorder_pics, dpb_max_latency_increase_plus1
+				_, _ = br.readUEWithOk()
+			}
+		}
+	}
+
+	_, _ = br.readUEWithOk() // sps_log2_min_luma_coding_block_size_minus2
+	_ = br.readBitsValue(1)  // sps_partition_constraints_override_enabled_flag
+	_, _ = br.readUEWithOk() // sps_log2_diff_min_qt_min_cb_intra_slice_luma
+	skipVVCMTTDepth(br)
+	if chromaFormatIDC != 0 && br.readBitsValue(1) == 1 { // sps_qtbtt_dual_tree_intra_flag
+		_, _ = br.readUEWithOk() // sps_log2_diff_min_qt_min_cb_intra_slice_chroma
+		skipVVCMTTDepth(br)
+	}
+	_, _ = br.readUEWithOk() // sps_log2_diff_min_qt_min_cb_inter_slice
+	skipVVCMTTDepth(br)
+	transform64 := ctbLog2Size > 5 && br.readBitsValue(1) == 1
+	transformSkip := br.readBitsValue(1) == 1
+	if transformSkip {
+		_, _ = br.readUEWithOk() // sps_log2_transform_skip_max_size_minus2
+		_ = br.readBitsValue(1)  // sps_bdpcm_enabled_flag
+	}
+	if br.readBitsValue(1) == 1 { // sps_mts_enabled_flag
+		_ = br.readBitsValue(2)
+	}
+	lfnst := br.readBitsValue(1) == 1
+	if chromaFormatIDC != 0 {
+		jointCbCr := br.readBitsValue(1) == 1
+		qpTables := 2
+		if br.readBitsValue(1) == 1 { // sps_same_qp_table_for_chroma_flag
+			qpTables = 1
+		} else if jointCbCr {
+			qpTables = 3
+		}
+		for range qpTables {
+			_, _ = br.readSEWithOk() // sps_qp_table_start_minus26
+			points, ok := br.readUEWithOk()
+			if !ok || points > 63 {
+				return info, true
+			}
+			for range 2 * (points + 1) { // sps_delta_qp_in_val_minus1, sps_delta_qp_diff_val
+				_, _ = br.readUEWithOk()
+			}
+		}
+	}
+	_ = br.readBitsValue(1) // sps_sao_enabled_flag
+	alf := br.readBitsValue(1) == 1
+	if alf && chromaFormatIDC != 0 {
+		_ = br.readBitsValue(1) // sps_ccalf_enabled_flag
+	}
+	_ = br.readBitsValue(1) // sps_lmcs_enabled_flag
+	weighted := br.readBitsValue(1) == 1
+	weighted = br.readBitsValue(1) == 1 || weighted
+	longTerm := br.readBitsValue(1) == 1
+	interLayer := vpsID > 0 && br.readBitsValue(1) == 1
+	_ = br.readBitsValue(1) // sps_idr_rpl_present_flag
+	refPicLists := 2
+	if br.readBitsValue(1) == 1 { // sps_rpl1_same_as_rpl0_flag
+		refPicLists = 1
+	}
+	for range refPicLists {
+		count, ok := br.readUEWithOk()
+		if !ok || count > 64 {
+			return info, true
+		}
+		for range count {
+			if !skipVVCRefPicListStruct(br, longTerm, interLayer, weighted, pocLSBBits) {
+				return info, true
+			}
+		}
+	}
+	_ = br.readBitsValue(1)       // sps_ref_wraparound_enabled_flag
+	if br.readBitsValue(1) == 1 { // sps_temporal_mvp_enabled_flag
+		_ = br.readBitsValue(1) // sps_sbtmvp_enabled_flag
+	}
+	amvr := br.readBitsValue(1) == 1
+	if br.readBitsValue(1) == 1 { // sps_bdof_enabled_flag
+		_ = br.readBitsValue(1)
+	}
+	_ = br.readBitsValue(1)       // sps_smvd_enabled_flag
+	if br.readBitsValue(1) == 1 { // sps_dmvr_enabled_flag
+		_ = br.readBitsValue(1)
+	}
+	if br.readBitsValue(1) == 1 { // sps_mmvd_enabled_flag
+		_ = br.readBitsValue(1)
+	}
+	sixMinusMergeCand, _ := br.readUEWithOk()
+	maxMergeCand := 6 - sixMinusMergeCand
+	_ = br.readBitsValue(1)       // sps_sbt_enabled_flag
+	if br.readBitsValue(1) == 1 { // sps_affine_enabled_flag
+		_, _ = br.readUEWithOk() // sps_five_minus_max_num_subblock_merge_cand
+		_ = br.readBitsValue(1)  // sps_6param_affine_enabled_flag
+		if amvr {
+			_ = br.readBitsValue(1) // sps_affine_amvr_enabled_flag
+		}
+		if br.readBitsValue(1) == 1 { // sps_affine_prof_enabled_flag
+			_ = br.readBitsValue(1)
+		}
+	}
+	_ = br.readBitsValue(2) // sps_bcw_enabled_flag, sps_ciip_enabled_flag
+	gpm := maxMergeCand >= 2 && br.readBitsValue(1) == 1
+	if gpm && maxMergeCand >= 3 {
+		_, _ = br.readUEWithOk() // sps_max_num_merge_cand_minus_max_num_gpm_cand
+	}
+	_, _ = br.readUEWithOk() // sps_log2_parallel_merge_level_minus2
+	_ = br.readBitsValue(3)  // sps_isp_enabled_flag, sps_mrl_enabled_flag, sps_mip_enabled_flag
+	if chromaFormatIDC != 0 {
+		_ = br.readBitsValue(1) // sps_cclm_enabled_flag
+	}
+	if chromaFormatIDC == 1 {
+		_ = br.readBitsValue(2) // sps_chroma_horizontal_collocated_flag, sps_chroma_vertical_collocated_flag
+	}
+	palette := br.readBitsValue(1) == 1
+	act := chromaFormatIDC == 3 && !transform64 && br.readBitsValue(1) == 1
+	if transformSkip || palette {
+		_, _ = br.readUEWithOk() // sps_min_qp_prime_ts
+	}
+	if br.readBitsValue(1) == 1 { // sps_ibc_enabled_flag
+		_, _ = br.readUEWithOk()
+	}
+	if br.readBitsValue(1) == 1 { // sps_ladf_enabled_flag
+		intervals := br.readBitsValue(2) + 1
+		if intervals > 4 {
+			return info, true
+		}
+		_, _ = br.readSEWithOk() // sps_ladf_lowest_interval_qp_offset
+		for range intervals {
+			_, _ = br.readSEWithOk()
+			_, _ = br.readUEWithOk()
+		}
+	}
+	explicitScalingList := br.readBitsValue(1) == 1
+	if lfnst && explicitScalingList {
+		_ = br.readBitsValue(1) // sps_scaling_matrix_for_lfnst_disabled_flag
+	}
+	if act && explicitScalingList && br.readBitsValue(1) == 1 { // sps_scaling_matrix_for_alternative_colour_space_disabled_flag
+		_ = br.readBitsValue(1)
+	}
+	_ = br.readBitsValue(2)       // sps_dep_quant_enabled_flag, sps_sign_data_hiding_enabled_flag
+	if br.readBitsValue(1) == 1 { // sps_virtual_boundaries_enabled_flag
+		if br.readBitsValue(1) == 1 { // sps_virtual_boundaries_present_flag
+			for range 2 {
+				count, ok := br.readUEWithOk()
+				if !ok || count > 3 {
+					return info, true
+				}
+				for range count {
+					_, _ = br.readUEWithOk()
+				}
+			}
+		}
+	}
+	if ptlDPBHRD && br.readBitsValue(1) == 1 { // sps_timing_hrd_params_present_flag
+		frameRate, ok := readVVCTimingHRD(br, maxSublayersMinus1)
+		info.FrameRate = frameRate
+		if !ok {
+			return info, true
+		}
+	}
+	_ = br.readBitsValue(1)       // sps_field_seq_flag
+	if br.readBitsValue(1) == 1 { // sps_vui_parameters_present_flag
+		_, _ = br.readUEWithOk() // sps_vui_payload_size_minus1
+		for br.bit != 0 {
+			_ = br.readBitsValue(1) // sps_vui_alignment_zero_bit
+		}
+		parseVVCVUI(br, &info)
+	}
+	return info, true
+}
+
+// readVVCProfileTierLevel reads profile_tier_level(1, maxSublayersMinus1) (7.3.3.1).
+func readVVCProfileTierLevel(br *bitReader, maxSublayersMinus1 int, info *h264SPSInfo) bool {
+	info.ProfileID = byte(br.readBitsValue(7))
+	info.HEVCTier = hevcTierName(byte(br.readBitsValue(1)))
+	info.LevelID = byte(br.readBitsValue(8))
+	_ = br.readBitsValue(2)       // ptl_frame_only_constraint_flag, ptl_multilayer_enabled_flag
+	if br.readBitsValue(1) == 1 { // gci_present_flag
+		// 71 constraint flags and fields, then the reserved bits.
+		_ = br.readBitsValue(32)
+		_ = br.readBitsValue(32)
+		_ = br.readBitsValue(7)
+		_ = br.readBitsValue(uint8(br.readBitsValue(8)))
+	}
+	for br.bit != 0 {
+		_ = br.readBitsValue(1) // gci_alignment_zero_bit
+	}
+	levelPresent := br.readBitsValue(uint8(maxSublayersMinus1))
+	for br.bit != 0 {
+		_ = br.readBitsValue(1) // ptl_reserved_zero_bit
+	}
+	for i := range maxSublayersMinus1 {
+		if levelPresent>>i&1 == 1 {
+			_ = br.readBitsValue(8) // sublayer_level_idc
+		}
+	}
+	subProfiles := int(br.readBitsValue(8))
+	for range subProfiles {
+		_ = br.readBitsValue(32) // general_sub_profile_idc
+	}
+	return br.pos < len(br.data)
+}
+
+// skipVVCSubpicInfo skips the subpicture layout of an SPS.
+func skipVVCSubpicInfo(br *bitReader, picWidth, picHeight, ctbLog2Size int) bool {
+	numMinus1, ok := br.readUEWithOk()
+	if !ok || numMinus1 > 599 {
+		return false
+	}
+	independent, sameSize := true, false
+	if numMinus1 > 0 {
+		independent = br.readBitsValue(1) == 1
+		sameSize = br.readBitsValue(1) == 1
+	}
+	ctbSize := 1 << ctbLog2Size
+	widthBits := uint8(bits.Len(uint((picWidth+ctbSize-1)>>ctbLog2Size - 1)))
+	heightBits := uint8(bits.Len(uint((picHeight+ctbSize-1)>>ctbLog2Size - 1)))
+	for i := 0; numMinus1 > 0 && i <= numMinus1; i++ {
+		if !sameSize || i == 0 {
+			if i > 0 && picWidth > ctbSize {
+				_ = br.readBitsValue(widthBits) // sps_subpic_ctu_top_left_x
+			}
+			if i > 0 && picHeight > ctbSize {
+				_ = br.readBitsValue(heightBits) // sps_subpic_ctu_top_left_y
+			}
+			if i < numMinus1 && picWidth > ctbSize {
+				_ = br.readBitsValue(widthBits) // sps_subpic_width_minus1
+			}
+			if i < numMinus1 && picHeight > ctbSize {
+				_ = br.readBitsValue(heightBits) // sps_subpic_height_minus1
+			}
+		}
+		if !independent {
+			_ = br.readBitsValue(2) // sps_subpic_treated_as_pic_flag, sps_loop_filter_across_subpic_enabled_flag
+		}
+	}
+	idLenMinus1, ok := br.readUEWithOk()
+	if !ok || idLenMinus1 > 15 {
+		return false
+	}
+	if br.readBitsValue(1) == 1 && br.readBitsValue(1) == 1 { // sps_subpic_id_mapping_explicitly_signalled_flag, sps_subpic_id_mapping_present_flag
+		for range numMinus1 + 1 {
+			_ = br.readBitsValue(uint8(idLenMinus1 + 1))
+		}
+	}
+	return br.pos < len(br.data)
+}
+
+// skipVVCMTTDepth skips a max_mtt_hierarchy_depth and the BT/TT sizes that follow it.
+func skipVVCMTTDepth(br *bitReader) {
+	if depth, ok := br.readUEWithOk(); ok && depth != 0 {
+		_, _ = br.readUEWithOk() // log2_diff_max_bt_min_qt
+		_, _ = br.readUEWithOk() // log2_diff_max_tt_min_qt
+	}
+}
+
+// skipVVCRefPicListStruct skips a ref_pic_list_struct of an SPS (7.3.10).
+func skipVVCRefPicListStruct(br *bitReader, longTerm, interLayer, weighted bool, pocLSBBits uint8) bool {
+	entries, ok := br.readUEWithOk()
+	if !ok || entries > 64 {
+		return false
+	}
+	ltrpInHeader := longTerm && entries > 0 && br.readBitsValue(1) == 1
+	for i := range entries {
+		if interLayer && br.readBitsValue(1) == 1 { // inter_layer_ref_pic_flag
+			_, _ = br.readUEWithOk() // ilrp_idx
+			continue
+		}
+		shortTerm := !longTerm || br.readBitsValue(1) == 1
+		switch {
+		case shortTerm:
+			absDelta, ok := br.readUEWithOk()
+			if !ok {
+				return false
+			}
+			// AbsDeltaPocSt is abs_delta_poc_st + 1 except for repeated weighted entries.
+			if !weighted || i == 0 || absDelta > 0 {
+				_ = br.readBitsValue(1) // strp_entry_sign_flag
+			}
+		case !ltrpInHeader:
+			_ = br.readBitsValue(pocLSBBits) // rpls_poc_lsb_lt
+		}
+	}
+	return br.pos < len(br.data)
+}
+
+// readVVCTimingHRD reads general_timing_hrd_parameters and ols_timing_hrd_parameters and
+// returns the frame rate of the highest sublayer.
+func readVVCTimingHRD(br *bitReader, maxSublayersMinus1 int) (float64, bool) {
+	numUnitsInTick := br.readBitsValue(32)
+	timeScale := br.readBitsValue(32)
+	if numUnitsInTick == ^uint64(0) || timeScale == ^uint64(0) {
+		return 0, false
+	}
+	frameRate := 0.0
+	if numUnitsInTick > 0 && timeScale > 0 {
+		frameRate = float64(timeScale) / float64(numUnitsInTick)
+	}
+	nalHRD := br.readBitsValue(1) == 1
+	vclHRD := br.readBitsValue(1) == 1
+	duHRD := false
+	cpbCount := 0
+	if nalHRD || vclHRD {
+		_ = br.readBitsValue(1) // general_same_pic_timing_in_all_ols_flag
+		duHRD = br.readBitsValue(1) == 1
+		if duHRD {
+			_ = br.readBitsValue(8) // tick_divisor_minus2
+		}
+		_ = br.readBitsValue(8) // bit_rate_scale, cpb_size_scale
+		if duHRD {
+			_ = br.readBitsValue(4) // cpb_size_du_scale
+		}
+		cpbCountMinus1, ok := br.readUEWithOk()
+		if !ok || cpbCountMinus1 > 31 {
+			return frameRate, false
+		}
+		cpbCount = cpbCountMinus1 + 1
+	}
+	first := maxSublayersMinus1
+	if maxSublayersMinus1 > 0 && br.readBitsValue(1) == 1 { // sps_sublayer_cpb_params_present_flag
+		first = 0
+	}
+	rate := frameRate
+	for i := first; i <= maxSublayersMinus1; i++ {
+		fixedPicRate := br.readBitsValue(1) == 1 // fixed_pic_rate_general_flag
+		if !fixedPicRate {
+			fixedPicRate = br.readBitsValue(1) == 1 // fixed_pic_rate_within_cvs_flag
+		}
+		if fixedPicRate {
+			elementalDurationMinus1, ok := br.readUEWithOk()
+			if !ok {
+				return frameRate, false
+			}
+			rate = frameRate / float64(elementalDurationMinus1+1)
+		} else if (nalHRD || vclHRD) && cpbCount == 1 {
+			_ = br.readBitsValue(1) // low_delay_hrd_flag
+		}
+		for _, present := range []bool{nalHRD, vclHRD} {
+			if !present {
+				continue
+			}
+			for range cpbCount { // sublayer_hrd_parameters
+				for range 2 { // bit_rate_value_minus1, cpb_size_value_minus1
+					_, _ = br.readUEWithOk()
+				}
+				if duHRD {
+					for range 2 { // cpb_size_du_value_minus1, bit_rate_du_value_minus1
+						_, _ = br.readUEWithOk()
+					}
+				}
+				_ = br.readBitsValue(1) // cbr_flag
+			}
+		}
+	}
+	return rate, br.pos < len(br.data)
+}
+
+// parseVVCVUI reads the sample aspect ratio and color description of vui_parameters
+// (H.274 7.2).
+func parseVVCVUI(br *bitReader, info *h264SPSInfo) {
+	_ = br.readBitsValue(4)       // progressive, interlaced, non-packed and non-projected flags
+	if br.readBitsValue(1) == 1 { // vui_aspect_ratio_info_present_flag
+		_ = br.readBitsValue(1) // vui_aspect_ratio_constant_flag
+		idc := br.readBitsValue(8)
+		if idc == 255 {
+			sarWidth := br.readBitsValue(16)
+			sarHeight := br.readBitsValue(16)
+			if sarWidth != ^uint64(0) && sarHeight != ^uint64(0) && sarWidth > 0 && sarHeight > 0 {
+				info.SARWidth, info.SARHeight, info.HasSAR = uint32(sarWidth), uint32(sarHeight), true
+			}
+		} else if w, h, ok := h264SARFromIDC(idc); ok {
+			info.SARWidth, info.SARHeight, info.HasSAR = w, h, true
+		}
+	}
+	if br.readBitsValue(1) == 1 { // vui_overscan_info_present_flag
+		_ = br.readBitsValue(1)
+	}
+	if br.readBitsValue(1) == 1 { // vui_colour_description_present_flag
+		primaries := br.readBitsValue(8)
+		transfer := br.readBitsValue(8)
+		matrix := br.readBitsValue(8)
+		fullRange := br.readBitsValue(1)
+		if fullRange == ^uint64(0) {
+			return
+		}
+		info.ColorPrimaries = matroskaColorPrimariesName(primaries)
+		info.TransferCharacteristics = matroskaTransferName(transfer)
+		info.MatrixCoefficients = matroskaMatrixName(matrix)
+		info.HasColorDescription = true
+		info.ColorRange = "Limited"
+		if fullRange == 1 {
+			info.ColorRange = "Full"
+		}
+		info.HasColorRange = true
+	}
+}
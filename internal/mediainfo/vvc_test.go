@@ -0,0 +1,311 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/bits"
+	"strings"
+	"testing"
+)
+
+func (w *testBitWriter) putUE(value int) {
+	n := bits.Len(uint(value + 1))
+	w.put(0, n-1)
+	w.put(uint64(value+1), n)
+}
+
+// testVVCNAL returns a VVC NAL unit of nalType with emulation prevention applied to rbsp.
+func testVVCNAL(nalType byte, rbsp []byte) []byte {
+	nal := []byte{0x00, nalType<<3 | 1}
+	zeros := 0
+	for _, b := range rbsp {
+		if zeros == 2 && b <= 0x03 {
+			nal = append(nal, 0x03)
+			zeros = 0
+		}
+		nal = append(nal, b)
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+	}
+	return nal
+}
+
+// testVVCSPS is a Main 10 5.1 10-bit 4:2:0 1920x1080 23.976 fps BT.2020 PQ SPS with the
+// timing HRD parameters and the VUI at the end.
+func testVVCSPS() []byte {
+	w := &testBitWriter{}
+	w.put(0, 4) // sps_seq_parameter_set_id
+	w.put(0, 4) // sps_video_parameter_set_id
+	w.put(0, 3) // sps_max_sublayers_minus1
+	w.put(1, 2) // sps_chroma_format_idc
+	w.put(2, 2) // sps_log2_ctu_size_minus5
+	w.put(1, 1) // sps_ptl_dpb_hrd_params_present_flag
+	w.put(1, 7) // general_profile_idc
+	w.put(0, 1) // general_tier_flag
+	w.put(83, 8)
+	w.put(2, 2) // ptl_frame_only_constraint_flag, ptl_multilayer_enabled_flag
+	w.put(0, 1) // gci_present_flag
+	w.put(0, 5) // gci_alignment_zero_bit
+	w.put(0, 8) // ptl_num_sub_profiles
+	w.put(0, 2) // sps_gdr_enabled_flag, sps_ref_pic_resampling_enabled_flag
+	w.putUE(1920)
+	w.putUE(1080)
+	w.put(0, 2) // sps_conformance_window_flag, sps_subpic_info_present_flag
+	w.putUE(2)  // sps_bitdepth_minus8
+	w.put(0, 2)
+	w.put(4, 4) // sps_log2_max_pic_order_cnt_lsb_minus4
+	w.put(0, 5) // sps_poc_msb_cycle_flag, sps_num_extra_ph_bytes, sps_num_extra_sh_bytes
+	w.putUE(5)  // dpb_parameters
+	w.putUE(2)
+	w.putUE(0)
+	w.putUE(0)  // sps_log2_min_luma_coding_block_size_minus2
+	w.put(0, 1) // sps_partition_constraints_override_enabled_flag
+	w.putUE(1)
+	w.putUE(0)  // sps_max_mtt_hierarchy_depth_intra_slice_luma
+	w.put(1, 1) // sps_qtbtt_dual_tree_intra_flag
+	w.putUE(2)
+	w.putUE(0)
+	w.putUE(1)
+	w.putUE(3) // sps_max_mtt_hierarchy_depth_inter_slice
+	w.putUE(2)
+	w.putUE(1)
+	w.put(1, 1) // sps_max_luma_transform_size_64_flag
+	w.put(1, 1) // sps_transform_skip_enabled_flag
+	w.putUE(3)
+	w.put(0, 1)
+	w.put(4, 3) // sps_mts_enabled_flag, explicit intra/inter
+	w.put(1, 1) // sps_lfnst_enabled_flag
+	w.put(3, 2) // sps_joint_cbcr_enabled_flag, sps_same_qp_table_for_chroma_flag
+	w.putUE(0)  // sps_qp_table_start_minus26
+	w.putUE(1)  // sps_num_points_in_qp_table_minus1
+	for _, v := range []int{24, 1, 10, 2} {
+		w.putUE(v)
+	}
+	w.put(15, 4) // sao, alf, ccalf, lmcs
+	w.put(0, 3)  // weighted pred, weighted bipred, long term ref pics
+	w.put(0, 2)  // sps_idr_rpl_present_flag, sps_rpl1_same_as_rpl0_flag
+	for range 2 {
+		w.putUE(1) // sps_num_ref_pic_lists
+		w.putUE(2) // num_ref_entries
+		for range 2 {
+			w.putUE(0)  // abs_delta_poc_st
+			w.put(0, 1) // strp_entry_sign_flag
+		}
+	}
+	w.put(0, 1)  // sps_ref_wraparound_enabled_flag
+	w.put(63, 6) // tmvp, sbtmvp, amvr, bdof, bdof control, smvd
+	w.put(15, 4) // dmvr, dmvr control, mmvd, mmvd fullpel
+	w.putUE(0)   // sps_six_minus_max_num_merge_cand
+	w.put(3, 2)  // sps_sbt_enabled_flag, sps_affine_enabled_flag
+	w.putUE(0)   // sps_five_minus_max_num_subblock_merge_cand
+	w.put(15, 4) // 6param, affine amvr, prof, prof control
+	w.put(7, 3)  // bcw, ciip, gpm
+	w.putUE(1)   // sps_max_num_merge_cand_minus_max_num_gpm_cand
+	w.putUE(0)   // sps_log2_parallel_merge_level_minus2
+	w.put(63, 6) // isp, mrl, mip, cclm, chroma collocated
+	w.put(0, 1)  // sps_palette_enabled_flag
+	w.putUE(0)   // sps_min_qp_prime_ts
+	w.put(0, 3)  // ibc, ladf, explicit scaling list
+	w.put(2, 3)  // dep quant, sign data hiding, virtual boundaries
+	w.put(1, 1)  // sps_timing_hrd_params_present_flag
+	w.put(1001, 32)
+	w.put(24000, 32)
+	w.put(2, 2)    // general_nal_hrd_params_present_flag, general_vcl_hrd_params_present_flag
+	w.put(2, 2)    // general_same_pic_timing_in_all_ols_flag, general_du_hrd_params_present_flag
+	w.put(0x44, 8) // bit_rate_scale, cpb_size_scale
+	w.putUE(0)     // hrd_cpb_cnt_minus1
+	w.put(1, 1)    // fixed_pic_rate_general_flag
+	w.putUE(0)     // elemental_duration_in_tc_minus1
+	w.putUE(9999)  // bit_rate_value_minus1
+	w.putUE(19999) // cpb_size_value_minus1
+	w.put(0, 1)    // cbr_flag
+	w.put(0, 1)    // sps_field_seq_flag
+	w.put(1, 1)    // sps_vui_parameters_present_flag
+	w.putUE(4)
+	for w.bits%8 != 0 {
+		w.put(0, 1)
+	}
+	w.put(8, 4) // vui_progressive_source_flag
+	w.put(1, 3) // aspect ratio, overscan, colour description
+	w.put(9, 8)
+	w.put(16, 8)
+	w.put(9, 8)
+	w.put(0, 2) // vui_full_range_flag, vui_chroma_loc_info_present_flag
+	w.put(0, 1) // sps_extension_flag
+	w.put(1, 1) // rbsp_stop_one_bit
+	return testVVCNAL(vvcNALSPS, w.buf)
+}
+
+// testVVCHDRSEI returns a prefix SEI NAL unit with BT.2020 mastering display and 1000/400
+// content light level messages.
+func testVVCHDRSEI() []byte {
+	mdcv := []byte{}
+	for _, v := range []float64{0.170, 0.797, 0.131, 0.046, 0.708, 0.292, 0.3127, 0.3290} {
+		mdcv = binary.BigEndian.AppendUint16(mdcv, uint16(v*50000+0.5))
+	}
+	mdcv = binary.BigEndian.AppendUint32(mdcv, 1000*10000)
+	mdcv = binary.BigEndian.AppendUint32(mdcv, 50)
+	rbsp := append([]byte{137, byte(len(mdcv))}, mdcv...)
+	rbsp = append(rbsp, 144, 4, 0x03, 0xE8, 0x01, 0x90, 0x80)
+	return testVVCNAL(vvcNALPrefixSEI, rbsp)
+}
+
+func TestParseVVCSPS(t *testing.T) {
+	sps, ok := parseVVCSPS(testVVCSPS())
+	if !ok {
+		t.Fatal("expected SPS")
+	}
+	if sps.ProfileID != 1 || sps.LevelID != 83 || sps.BitDepth != 10 || sps.ChromaFormat != "4:2:0" {
+		t.Fatalf("unexpected format info: %+v", sps)
+	}
+	if sps.Width != 1920 || sps.Height != 1080 {
+		t.Fatalf("size = %dx%d", sps.Width, sps.Height)
+	}
+	if sps.FrameRate < 23.976 || sps.FrameRate > 23.977 {
+		t.Fatalf("frame rate = %f", sps.FrameRate)
+	}
+	if sps.ColorPrimaries != "BT.2020" || sps.TransferCharacteristics != "PQ" || sps.ColorRange != "Limited" {
+		t.Fatalf("unexpected color info: %+v", sps)
+	}
+	if got := findField(vvcFormatFields(sps), "Format profile"); got != "Main 10@L5.1" {
+		t.Fatalf("Format profile = %q", got)
+	}
+}
+
+// testVVCConfig returns a VvcDecoderConfigurationRecord for testVVCSPS with the HDR SEI.
+func testVVCConfig() []byte {
+	w := &testBitWriter{}
+	w.put(0x1F, 5)
+	w.put(3, 2) // LengthSizeMinusOne
+	w.put(1, 1) // ptl_present_flag
+	w.put(0, 9) // ols_idx
+	w.put(1, 3) // num_sublayers
+	w.put(1, 2) // constant_frame_rate
+	w.put(1, 2) // chroma_format_idc
+	w.put(2, 3) // bit_depth_minus8
+	w.put(0x1F, 5)
+	w.put(1, 8) // num_bytes_constraint_info
+	w.put(1, 7)
+	w.put(0, 1)
+	w.put(83, 8)
+	w.put(0x80, 8)
+	w.put(0, 8) // ptl_num_sub_profiles
+	w.put(1920, 16)
+	w.put(1080, 16)
+	w.put(6138, 16) // avg_frame_rate
+	config := w.buf
+	config = append(config, 2)
+	for _, nal := range [][]byte{testVVCSPS(), testVVCHDRSEI()} {
+		config = append(config, 0x80|nal[1]>>3, 0, 1)
+		config = binary.BigEndian.AppendUint16(config, uint16(len(nal)))
+		config = append(config, nal...)
+	}
+	return config
+}
+
+func TestParseMP4VVCSampleEntry(t *testing.T) {
+	var vvcC bytes.Buffer
+	writeMP4Box(&vvcC, "vvcC", append([]byte{0, 0, 0, 0}, testVVCConfig()...))
+	entry := make([]byte, 86)
+	copy(entry[4:8], "vvc1")
+	binary.BigEndian.PutUint16(entry[32:34], 1920)
+	binary.BigEndian.PutUint16(entry[34:36], 1080)
+	entry = append(entry, vvcC.Bytes()...)
+	binary.BigEndian.PutUint32(entry[0:4], uint32(len(entry)))
+
+	result := parseVisualSampleEntry(entry, "vvc1")
+	for _, f := range []Field{
+		{"Format/Info", "Versatile Video Coding"},
+		{"Format profile", "Main 10@L5.1"},
+		{"Bit depth", "10 bits"},
+		{"Color primaries", "BT.2020"},
+		{"HDR format", "SMPTE ST 2086, HDR10 compatible"},
+		{"Mastering display color primaries", "BT.2020"},
+		{"Maximum Content Light Level", "1000 cd/m2"},
+		{"Codec configuration box", "vvcC"},
+	} {
+		if got := findField(result.Fields, f.Name); got != f.Value {
+			t.Errorf("%s = %q, want %q", f.Name, got, f.Value)
+		}
+	}
+}
+
+func TestParseMatroskaTrackEntryVVC(t *testing.T) {
+	entry := append(
+		buildMatroskaElement(mkvIDTrackType, encodeMatroskaUint(1)),
+		buildMatroskaElement(mkvIDTrackNumber, encodeMatroskaUint(1))...,
+	)
+	entry = append(entry, buildMatroskaElement(mkvIDCodecID, []byte("V_MPEGI/ISO/VVC"))...)
+	entry = append(entry, buildMatroskaElement(mkvIDCodecPrivate, testVVCConfig())...)
+
+	stream, ok := parseMatroskaTrackEntry(entry, 0, 3)
+	if !ok {
+		t.Fatal("expected parsed stream")
+	}
+	if got := findField(stream.Fields, "Format"); got != "VVC" {
+		t.Fatalf("Format = %q", got)
+	}
+	if got := findField(stream.Fields, "Format profile"); got != "Main 10@L5.1" {
+		t.Fatalf("Format profile = %q", got)
+	}
+	if stream.nalLengthSize != 4 {
+		t.Fatalf("nalLengthSize = %d", stream.nalLengthSize)
+	}
+}
+
+func TestAnalyzeVVCAnnexB(t *testing.T) {
+	startCode := []byte{0x00, 0x00, 0x00, 0x01}
+	var data []byte
+	for i := range 48 {
+		if i == 0 {
+			data = append(data, startCode...)
+			data = append(data, testVVCSPS()...)
+			data = append(data, startCode...)
+			data = append(data, testVVCHDRSEI()...)
+		}
+		data = append(data, startCode...)
+		data = append(data, testVVCNAL(vvcNALAUD, []byte{0x08})...)
+		nalType := byte(0) // TRAIL_NUT
+		if i == 0 {
+			nalType = 8 // IDR_N_LP
+		}
+		// sh_picture_header_in_slice_header_flag set, then slice data.
+		data = append(data, startCode...)
+		data = append(data, testVVCNAL(nalType, append([]byte{0x80}, bytes.Repeat([]byte{0x5A}, 200)...))...)
+	}
+
+	if got := DetectFormat(data, "clip.266"); got != "VVC" {
+		t.Fatalf("DetectFormat = %q", got)
+	}
+	report, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), "clip.266", defaultAnalyzeOptions())
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	text := RenderText([]Report{report})
+	for _, want := range []string{
+		"Format                                   : VVC\n",
+		"Format/Info                              : Versatile Video Coding\n",
+		"Format profile                           : Main 10@L5.1\n",
+		"HDR format                               : SMPTE ST 2086, HDR10 compatible\n",
+		"Width                                    : 1 920 pixels\n",
+		"Height                                   : 1 080 pixels\n",
+		"Frame rate                               : 23.976 (24000/1001) FPS\n",
+		"Duration                                 : 2 s 2 ms\n",
+		"Chroma subsampling                       : 4:2:0\n",
+		"Transfer characteristics                 : PQ\n",
+		"Maximum Frame-Average Light Level        : 400 cd/m2\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %q in:\n%s", want, text)
+		}
+	}
+	if jsonOut := RenderJSON([]Report{report}); !strings.Contains(jsonOut, `"FrameCount":"48"`) {
+		t.Errorf("missing FrameCount in %s", jsonOut)
+	}
+	if kind, format := mapTSStream(0x33, 0); kind != StreamVideo || format != "VVC" {
+		t.Errorf("mapTSStream(0x33) = %v %q", kind, format)
+	}
+}
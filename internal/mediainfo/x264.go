@@ -131,3 +131,18 @@ func findX264Keyint(encoding string) (int, bool) {
 func findX264Bframes(encoding string) (int, bool) {
 	return findX264ParamInt(encoding, "bframes")
 }
+
+// parseX264Settings splits x264 or x265 encoding settings ("cabac=1 / ref=3 / ...") into
+// options. Bare x265 flags ("wpp", "no-pmode") map to "".
+func parseX264Settings(encoding string) map[string]string {
+	settings := map[string]string{}
+	for token := range strings.SplitSeq(encoding, " / ") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(token, "=")
+		settings[key] = value
+	}
+	return settings
+}
@@ -0,0 +1,114 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// xmlNode is a small element tree for the schema-driven exports (EBUCore, PBCore), which
+// are easier to build as a tree than as MediaInfo XML's flat field lists.
+type xmlNode struct {
+	name     string
+	attrs    []xmlAttr
+	text     string
+	children []*xmlNode
+}
+
+type xmlAttr struct {
+	name  string
+	value string
+}
+
+func newXMLNode(name string, attrs ...xmlAttr) *xmlNode {
+	return &xmlNode{name: name, attrs: attrs}
+}
+
+// add appends child and returns it.
+func (n *xmlNode) add(child *xmlNode) *xmlNode {
+	n.children = append(n.children, child)
+	return child
+}
+
+// addText appends a text element unless value is empty.
+func (n *xmlNode) addText(name, value string, attrs ...xmlAttr) {
+	if value == "" {
+		return
+	}
+	n.children = append(n.children, &xmlNode{name: name, attrs: attrs, text: value})
+}
+
+// attr sets an attribute unless value is empty.
+func (n *xmlNode) attr(name, value string) {
+	if value == "" {
+		return
+	}
+	n.attrs = append(n.attrs, xmlAttr{name: name, value: value})
+}
+
+// writeXML writes n indented with tabs, one element per line.
+func (n *xmlNode) writeXML(buf *bytes.Buffer, depth int) {
+	buf.WriteString(strings.Repeat("\t", depth))
+	buf.WriteString("<" + n.name)
+	for _, attr := range n.attrs {
+		buf.WriteString(" " + attr.name + "=\"" + xmlEscapeAttr(attr.value) + "\"")
+	}
+	switch {
+	case len(n.children) > 0:
+		buf.WriteString(">\n")
+		for _, child := range n.children {
+			child.writeXML(buf, depth+1)
+		}
+		buf.WriteString(strings.Repeat("\t", depth))
+		buf.WriteString("</" + n.name + ">\n")
+	case n.text != "":
+		buf.WriteString(">" + xmlEscape(n.text) + "</" + n.name + ">\n")
+	default:
+		buf.WriteString("/>\n")
+	}
+}
+
+// renderJSON renders n the way MediaInfo serializes its XML exports to JSON: attributes
+// become "@name" keys, text becomes "#value" and children are grouped by name into arrays.
+// Element namespace prefixes are dropped.
+func (n *xmlNode) renderJSON() string {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	writeJSONField(&buf, jsonLocalName(n.name), n.jsonObject(), true)
+	buf.WriteString("}")
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return buf.String()
+	}
+	return out.String()
+}
+
+func (n *xmlNode) jsonObject() string {
+	fields := make([]jsonKV, 0, len(n.attrs)+len(n.children)+1)
+	for _, attr := range n.attrs {
+		fields = append(fields, jsonKV{Key: "@" + attr.name, Val: attr.value})
+	}
+	var names []string
+	groups := map[string][]string{}
+	for _, child := range n.children {
+		name := jsonLocalName(child.name)
+		if _, ok := groups[name]; !ok {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], child.jsonObject())
+	}
+	for _, name := range names {
+		fields = append(fields, jsonKV{Key: name, Val: renderJSONArray(groups[name], false), Raw: true})
+	}
+	if n.text != "" {
+		fields = append(fields, jsonKV{Key: "#value", Val: n.text})
+	}
+	return renderJSONObject(fields, false)
+}
+
+func jsonLocalName(name string) string {
+	if _, local, ok := strings.Cut(name, ":"); ok {
+		return local
+	}
+	return name
+}
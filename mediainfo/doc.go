@@ -0,0 +1,25 @@
+// Package mediainfo is the public API of go-mediainfo.
+//
+// It analyzes media files and renders the results in the same formats as the
+// mediainfo CLI (text, JSON, XML, CSV, HTML, ...):
+//
+//	report, err := mediainfo.AnalyzeFile("movie.mkv")
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Print(mediainfo.RenderText([]mediainfo.Report{report}))
+//
+// A Report holds one General stream plus the Video, Audio, Text, Image and
+// Menu streams found in the file. Each Stream carries the ordered, human
+// readable Fields shown in text output, and Video, Audio and Text streams (as
+// well as General) also carry a typed view for programmatic use:
+//
+//	for _, stream := range report.Streams {
+//		if v := stream.Video; v != nil {
+//			fmt.Println(v.Width, v.Height, v.FrameRate.FloatString(3), v.Duration)
+//		}
+//	}
+//
+// The container and codec parsers live in an internal package and are not
+// part of the supported surface; everything exported here is.
+package mediainfo
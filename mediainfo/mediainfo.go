@@ -0,0 +1,160 @@
+package mediainfo
+
+import (
+	"context"
+	"io"
+
+	core "github.com/autobrr/go-mediainfo/internal/mediainfo"
+)
+
+// Report is the analysis result for a single file.
+type Report = core.Report
+
+// Stream is one track of a Report (or its General section).
+type Stream = core.Stream
+
+// Field is a single name/value pair as shown in text output.
+type Field = core.Field
+
+// GeneralInfo, VideoInfo, AudioInfo and TextInfo are typed views of a Stream
+// (Stream.General, Stream.Video, Stream.Audio, Stream.Text). They are filled
+// during analysis from the same values the JSON output reports: durations are
+// time.Duration, frame rates exact big.Rat values and sizes and bit rates plain
+// integers. Fields that are not reported are left at their zero value.
+type (
+	GeneralInfo = core.GeneralInfo
+	VideoInfo   = core.VideoInfo
+	AudioInfo   = core.AudioInfo
+	TextInfo    = core.TextInfo
+)
+
+// StreamKind identifies the type of a Stream.
+type StreamKind = core.StreamKind
+
+// Stream kinds, in the order they are rendered.
+const (
+	StreamGeneral = core.StreamGeneral
+	StreamVideo   = core.StreamVideo
+	StreamAudio   = core.StreamAudio
+	StreamText    = core.StreamText
+	StreamImage   = core.StreamImage
+	StreamMenu    = core.StreamMenu
+)
+
+// AnalyzeOptions tunes analysis. The zero value matches the CLI defaults.
+//
+// ParseSpeed (0..1) trades accuracy for speed the same way MediaInfo's
+// --ParseSpeed does; it is only applied when HasParseSpeed is set.
+// TestContinuousFileNames enables MediaInfo's File_TestContinuousFileNames
+// behavior for numbered TS/M2TS segments; it is only applied when
+// HasTestContinuousFileNames is set. Timeout, when non-zero, bounds the
+// analysis of each file. Concurrency is the number of files the batch entry
+// points analyze in parallel (zero means GOMAXPROCS); reports are always
+// returned in input order.
+//
+// Directory arguments are expanded to the files they contain. Recursive
+// descends into subdirectories (up to MaxDepth levels when non-zero), Include
+// and Exclude filter the files found with path.Match patterns matched against
+// the base name and the path relative to the directory argument (a trailing
+// "/" makes a pattern match directories only, e.g. "Sample/"), and Symlinks
+// selects how symbolic links are handled.
+type AnalyzeOptions = core.AnalyzeOptions
+
+// SymlinkPolicy selects how directory expansion treats symbolic links.
+type SymlinkPolicy = core.SymlinkPolicy
+
+// Symbolic link policies for AnalyzeOptions.Symlinks.
+const (
+	// SymlinkFiles analyzes links to files but does not descend into linked
+	// directories. It is the default.
+	SymlinkFiles = core.SymlinkFiles
+	// SymlinkFollow also descends into linked directories, visiting each real
+	// directory once.
+	SymlinkFollow = core.SymlinkFollow
+	// SymlinkSkip ignores symbolic links found in directories.
+	SymlinkSkip = core.SymlinkSkip
+)
+
+// CanceledError is returned when analysis is stopped by its context or by
+// AnalyzeOptions.Timeout. It unwraps to context.Canceled or
+// context.DeadlineExceeded.
+type CanceledError = core.CanceledError
+
+// FileError describes one file of a batch that could not be analyzed.
+type FileError = core.FileError
+
+// BatchError is returned by the batch entry points when some files failed.
+// The reports are still returned: failed entries only carry Ref and Err, and
+// every renderer shows them as error entries.
+type BatchError = core.BatchError
+
+// AnalyzeFile analyzes the file at path with default options.
+func AnalyzeFile(path string) (Report, error) {
+	return core.AnalyzeFile(path)
+}
+
+// AnalyzeFileWithOptions analyzes the file at path.
+func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
+	return core.AnalyzeFileWithOptions(path, opts)
+}
+
+// AnalyzeFileContext is AnalyzeFileWithOptions with cancellation. The
+// container parsers check ctx while scanning, so a canceled request returns a
+// *CanceledError promptly and the file is closed before returning.
+func AnalyzeFileContext(ctx context.Context, path string, opts AnalyzeOptions) (Report, error) {
+	return core.AnalyzeFileContext(ctx, path, opts)
+}
+
+// AnalyzeReader analyzes size bytes of media read from r, e.g. an object
+// storage range reader, a bytes.Reader or an archive member. name is used as
+// the report reference and for extension hints (".vob", ".m2ts", ".ifo"); it
+// may be empty. File timestamps are not reported and FileSize is size.
+//
+// Data that is only available as an io.Reader must be buffered first (for
+// example into a bytes.Reader), since the parsers need random access.
+func AnalyzeReader(r io.ReaderAt, size int64, name string, opts AnalyzeOptions) (Report, error) {
+	return core.AnalyzeReader(r, size, name, opts)
+}
+
+// AnalyzeReaderContext is AnalyzeReader with cancellation, see
+// AnalyzeFileContext.
+func AnalyzeReaderContext(ctx context.Context, r io.ReaderAt, size int64, name string, opts AnalyzeOptions) (Report, error) {
+	return core.AnalyzeReaderContext(ctx, r, size, name, opts)
+}
+
+// AnalyzeFiles analyzes every path with default options. Directories are
+// expanded to the files they contain, sorted by name. It returns one report
+// per file, in order, and the number of files analyzed successfully.
+//
+// Unreadable files do not stop the batch: they get a report with Err set and
+// the call returns a *BatchError next to the reports.
+func AnalyzeFiles(paths []string) ([]Report, int, error) {
+	return core.AnalyzeFiles(paths)
+}
+
+// AnalyzeFilesWithOptions is AnalyzeFiles with explicit options.
+func AnalyzeFilesWithOptions(paths []string, opts AnalyzeOptions) ([]Report, int, error) {
+	return core.AnalyzeFilesWithOptions(paths, opts)
+}
+
+// AnalyzeFilesContext is AnalyzeFilesWithOptions with cancellation.
+// AnalyzeOptions.Timeout applies to each file separately; a file that times
+// out is reported as failed. Canceling ctx aborts the whole batch with a
+// *CanceledError.
+func AnalyzeFilesContext(ctx context.Context, paths []string, opts AnalyzeOptions) ([]Report, int, error) {
+	return core.AnalyzeFilesContext(ctx, paths, opts)
+}
+
+// AnalyzeFilesFunc is AnalyzeFilesContext without collecting the reports: fn
+// receives each report in input order as soon as it and the files before it
+// are analyzed. fn is never called concurrently; an error from fn stops the
+// batch and is returned unchanged.
+func AnalyzeFilesFunc(ctx context.Context, paths []string, opts AnalyzeOptions, fn func(Report) error) (int, error) {
+	return core.AnalyzeFilesFunc(ctx, paths, opts, fn)
+}
+
+// DetectFormat returns the container format name for a file header. The
+// filename is only used for extension hints and may be empty.
+func DetectFormat(header []byte, filename string) string {
+	return core.DetectFormat(header, filename)
+}
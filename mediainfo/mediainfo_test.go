@@ -0,0 +1,39 @@
+package mediainfo
+
+import (
+	"path/filepath"
+	"testing"
+
+	core "github.com/autobrr/go-mediainfo/internal/mediainfo"
+)
+
+func TestPublicAPIMatchesCore(t *testing.T) {
+	path := filepath.Join("..", "samples", "sample.mkv")
+	report, err := AnalyzeFile(path)
+	if err != nil {
+		t.Fatalf("analyze sample: %v", err)
+	}
+	want, err := core.AnalyzeFile(path)
+	if err != nil {
+		t.Fatalf("analyze sample (core): %v", err)
+	}
+	if got, expected := RenderJSON([]Report{report}), core.RenderJSON([]core.Report{want}); got != expected {
+		t.Fatalf("public JSON differs from core JSON")
+	}
+	if report.General.Kind != StreamGeneral {
+		t.Fatalf("General.Kind=%q, want %q", report.General.Kind, StreamGeneral)
+	}
+	if len(report.Streams) == 0 || report.Streams[0].Kind != StreamVideo {
+		t.Fatalf("expected video stream first, got %+v", report.Streams)
+	}
+}
+
+func TestAnalyzeFilesExpandsDirectory(t *testing.T) {
+	reports, count, err := AnalyzeFiles([]string{filepath.Join("..", "samples")})
+	if err != nil {
+		t.Fatalf("analyze samples: %v", err)
+	}
+	if count != len(reports) || count == 0 {
+		t.Fatalf("count=%d reports=%d", count, len(reports))
+	}
+}
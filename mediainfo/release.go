@@ -0,0 +1,53 @@
+package mediainfo
+
+import core "github.com/autobrr/go-mediainfo/internal/mediainfo"
+
+// ReleaseAttributes are the attributes release names advertise (resolution
+// class, codecs, HDR formats, audio flavors, languages), derived from what a
+// file contains and spelled the way release names spell them.
+type ReleaseAttributes = core.ReleaseAttributes
+
+// ReleaseAudio describes one audio stream of ReleaseAttributes.
+type ReleaseAudio = core.ReleaseAudio
+
+// ExtractReleaseAttributes derives the release attributes of a report, for
+// checking or replacing attributes parsed from a release name:
+//
+//	attrs := mediainfo.ExtractReleaseAttributes(report)
+//	// attrs.Resolution == "2160p", attrs.VideoCodec == "HEVC",
+//	// attrs.HDRString() == "DV HDR10", attrs.Audio[0].String() == "TrueHD Atmos 7.1"
+func ExtractReleaseAttributes(report Report) ReleaseAttributes {
+	return core.ExtractReleaseAttributes(report)
+}
+
+// CheckStatus is the outcome of one NameCheck.
+type CheckStatus = core.CheckStatus
+
+// Outcomes of a NameCheck.
+const (
+	CheckPass       = core.CheckPass
+	CheckFail       = core.CheckFail
+	CheckUnverified = core.CheckUnverified
+)
+
+// NameCheck compares one claim of a release name with the analyzed file.
+type NameCheck = core.NameCheck
+
+// ParseReleaseName reads the attributes a scene or P2P release name claims,
+// such as "Movie.2023.2160p.UHD.BluRay.DV.HDR10.TrueHD.Atmos.7.1.x265-GROUP".
+// Attributes the name does not mention are left empty.
+func ParseReleaseName(name string) ReleaseAttributes {
+	return core.ParseReleaseName(name)
+}
+
+// CheckReleaseName compares every claim of a release name with report and
+// returns one check per claim:
+//
+//	for _, check := range mediainfo.CheckReleaseName(name, report) {
+//		if check.Status == mediainfo.CheckFail {
+//			fmt.Printf("%s: claimed %s, file has %s\n", check.Attribute, check.Claimed, check.Actual)
+//		}
+//	}
+func CheckReleaseName(name string, report Report) []NameCheck {
+	return core.CheckReleaseName(name, report)
+}
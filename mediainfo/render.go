@@ -0,0 +1,238 @@
+package mediainfo
+
+import (
+	"io"
+
+	core "github.com/autobrr/go-mediainfo/internal/mediainfo"
+)
+
+// RenderText renders reports as the default MediaInfo text report.
+func RenderText(reports []Report) string {
+	return core.RenderText(reports)
+}
+
+// RenderOptions tunes RenderTextWithOptions and RenderHTMLWithOptions. Set Full for
+// the complete field set of mediainfo --Full: raw values next to display values,
+// alternative duration forms, stream counts and stream proportions. Set Language to
+// translate field names and values.
+type RenderOptions = core.RenderOptions
+
+// Language translates the field names and value words of the text and HTML reports.
+// Field names are looked up by internal name (the JSON key), value words by their
+// English spelling; missing entries stay in English.
+type Language = core.Language
+
+// RawLanguage returns the "raw" language, which prints internal field names instead
+// of display names.
+func RawLanguage() *Language {
+	return core.RawLanguage()
+}
+
+// BuiltinLanguage returns the translation embedded for code, e.g. "de" or "fr-CA".
+// ok is false when none is embedded.
+func BuiltinLanguage(code string) (*Language, bool) {
+	return core.BuiltinLanguage(code)
+}
+
+// BuiltinLanguageCodes lists the language codes with an embedded translation.
+func BuiltinLanguageCodes() []string {
+	return core.BuiltinLanguageCodes()
+}
+
+// LoadLanguageCSV reads a MediaInfo language file of "key;translation" lines. For
+// files with one column per language (first line "Language_ISO639;en;fr;..."), code
+// selects the column.
+func LoadLanguageCSV(r io.Reader, code string) (*Language, error) {
+	return core.LoadLanguageCSV(r, code)
+}
+
+// RenderTextWithOptions renders reports as the MediaInfo text report, honoring opts.
+func RenderTextWithOptions(reports []Report, opts RenderOptions) string {
+	return core.RenderTextWithOptions(reports, opts)
+}
+
+// RenderJSON renders reports as MediaInfo JSON. A single report is rendered
+// as an object, several as an array.
+func RenderJSON(reports []Report) string {
+	return core.RenderJSON(reports)
+}
+
+// RenderFFprobeJSON renders reports in the schema of `ffprobe -show_format
+// -show_streams -of json` (codec names, pix_fmt, color and HDR side data), for
+// tools written against ffprobe.
+func RenderFFprobeJSON(reports []Report) string {
+	return core.RenderFFprobeJSON(reports)
+}
+
+// TrackerMarkup selects the BBCode or Markdown wrapping of RenderTracker.
+type TrackerMarkup = core.TrackerMarkup
+
+// Markups for TrackerOptions.Markup.
+const (
+	// TrackerBBCode wraps the report in [mediainfo]...[/mediainfo]. It is the
+	// default.
+	TrackerBBCode = core.TrackerBBCode
+	// TrackerBBCodeCode wraps the report in [code]...[/code].
+	TrackerBBCodeCode = core.TrackerBBCodeCode
+	// TrackerMarkdown wraps the report in a fenced code block.
+	TrackerMarkdown = core.TrackerMarkdown
+)
+
+// TrackerOptions configures RenderTracker.
+type TrackerOptions = core.TrackerOptions
+
+// RenderTracker renders reports for tracker upload forms: a one-paragraph
+// summary followed by the text report in [mediainfo], [code] or a Markdown
+// fence, with Complete name reduced to the file name and, with Redact, unique
+// IDs, file times and absolute paths removed; paths inside other values are
+// reduced to their base names.
+func RenderTracker(reports []Report, opts TrackerOptions) string {
+	return core.RenderTracker(reports, opts)
+}
+
+// ReportSummary describes a report in one paragraph: resolution, codec and
+// HDR of the video, language, codec and channels of each audio track, and the
+// subtitle languages.
+func ReportSummary(report Report) string {
+	return core.ReportSummary(report)
+}
+
+// RenderNDJSON renders reports as newline-delimited JSON: one compact
+// single-file JSON document per line.
+func RenderNDJSON(reports []Report) string {
+	return core.RenderNDJSON(reports)
+}
+
+// Encoder writes reports as newline-delimited JSON, one line per Write.
+type Encoder = core.Encoder
+
+// NewEncoder returns an Encoder writing to w. Its Write method fits
+// AnalyzeFilesFunc:
+//
+//	enc := mediainfo.NewEncoder(os.Stdout)
+//	_, err := mediainfo.AnalyzeFilesFunc(ctx, paths, opts, enc.Write)
+func NewEncoder(w io.Writer) *Encoder {
+	return core.NewEncoder(w)
+}
+
+// RenderXML renders reports as MediaInfo XML (mediainfo_2_0.xsd).
+func RenderXML(reports []Report) string {
+	return core.RenderXML(reports)
+}
+
+// RenderCSV renders reports as MediaInfo CSV.
+func RenderCSV(reports []Report) string {
+	return core.RenderCSV(reports)
+}
+
+// RenderHTML renders reports as an HTML table.
+func RenderHTML(reports []Report) string {
+	return core.RenderHTML(reports)
+}
+
+// RenderHTMLWithOptions renders reports as an HTML table, honoring opts.
+func RenderHTMLWithOptions(reports []Report, opts RenderOptions) string {
+	return core.RenderHTMLWithOptions(reports, opts)
+}
+
+// RenderEBUCore renders reports as an EBUCore 1.8 XML document, with one
+// ebucore:format element per file.
+func RenderEBUCore(reports []Report) string {
+	return core.RenderEBUCore(reports)
+}
+
+// RenderEBUCoreJSON renders the RenderEBUCore document as JSON, using
+// MediaInfo's serialization ("@attribute" keys, "#value" text, arrays of
+// child elements).
+func RenderEBUCoreJSON(reports []Report) string {
+	return core.RenderEBUCoreJSON(reports)
+}
+
+// RenderPBCore renders reports as a PBCore 1.2.1 PBCoreDescriptionDocument
+// with one pbcoreInstantiation per file.
+func RenderPBCore(reports []Report) string {
+	return core.RenderPBCore(reports)
+}
+
+// RenderPBCore2 renders reports as PBCore 2.1: a pbcoreInstantiationDocument
+// for a single file, a pbcoreDescriptionDocument with one pbcoreInstantiation
+// per file otherwise.
+func RenderPBCore2(reports []Report) string {
+	return core.RenderPBCore2(reports)
+}
+
+// RenderGraphSVG renders the stream graph of reports (container, programs,
+// streams and the substreams they carry, such as caption services, E-AC-3 JOC
+// object audio or Dolby Vision layers) as SVG. The layout is done in Go;
+// Graphviz is not required.
+func RenderGraphSVG(reports []Report) string {
+	return core.RenderGraphSVG(reports)
+}
+
+// RenderGraphDOT renders the stream graph of reports as Graphviz DOT, one
+// cluster per file.
+func RenderGraphDOT(reports []Report) string {
+	return core.RenderGraphDOT(reports)
+}
+
+// InfoParameters lists every field per stream kind (internal name, description,
+// unit and text label), as mediainfo --Info-Parameters does. The names are the
+// ones usable in output templates.
+func InfoParameters() string {
+	return core.InfoParameters()
+}
+
+// FieldInfo describes a field: internal (JSON) name, text label, unit and
+// description.
+type FieldInfo = core.FieldInfo
+
+// RegisteredFields returns the fields streams of kind can carry, from the same
+// registry JSON and CSV output are checked against.
+func RegisteredFields(kind StreamKind) []FieldInfo {
+	return core.RegisteredFields(kind)
+}
+
+// Template is a parsed --Inform/--Output template, see ParseTemplate.
+type Template = core.Template
+
+// TemplateError reports a malformed template.
+type TemplateError = core.TemplateError
+
+// ParseTemplate parses a MediaInfo template such as
+// "Video;%Width%x%Height% %Format%\n". Each line selects a section: a stream
+// kind (General, Video, Audio, Text, Image, Menu), optionally suffixed with
+// _Begin, _Middle or _End, or one of Page_Begin, Page_Middle, Page_End,
+// File_Begin and File_End. %Name% is replaced by the field of the same name in
+// JSON output, $if(cond,then,else) picks text depending on whether cond is
+// empty, and \n, \r and \t are escapes.
+func ParseTemplate(text string) (*Template, error) {
+	return core.ParseTemplate(text)
+}
+
+// RenderTemplate renders reports with tmpl.
+func RenderTemplate(reports []Report, tmpl *Template) string {
+	return core.RenderTemplate(reports, tmpl)
+}
+
+// ConsistencyReport is the cross-file comparison of a batch such as a season
+// pack.
+type ConsistencyReport = core.ConsistencyReport
+
+// ConsistencyOutlier is one attribute of one file that differs from the
+// majority of the batch.
+type ConsistencyOutlier = core.ConsistencyOutlier
+
+// CheckConsistency compares the video codec, resolution, HDR formats, encoder
+// and its x264/x265 settings, audio tracks and languages and subtitle
+// languages across reports, typically the result of AnalyzeFilesWithOptions
+// on a directory, and lists the files that differ from the majority. Files
+// that failed to parse are listed with the Attribute "Error".
+func CheckConsistency(reports []Report) ConsistencyReport {
+	return core.CheckConsistency(reports)
+}
+
+// RenderConsistency renders the outliers of a ConsistencyReport as a table
+// (--output=Consistency).
+func RenderConsistency(report ConsistencyReport) string {
+	return core.RenderConsistency(report)
+}
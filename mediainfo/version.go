@@ -0,0 +1,28 @@
+package mediainfo
+
+import (
+	core "github.com/autobrr/go-mediainfo/internal/mediainfo"
+)
+
+// Library identification used in rendered output (ReportBy line,
+// creatingLibrary element).
+const (
+	AppName = core.AppName
+	AppURL  = core.AppURL
+)
+
+// SetAppVersion sets the version reported in rendered output. Empty values
+// are ignored.
+func SetAppVersion(version string) {
+	core.SetAppVersion(version)
+}
+
+// AppVersion returns the version reported in rendered output.
+func AppVersion() string {
+	return core.AppVersion
+}
+
+// FormatVersion formats a version for display ("dev" or "vX.Y.Z").
+func FormatVersion(version string) string {
+	return core.FormatVersion(version)
+}